@@ -10,19 +10,24 @@ import (
 )
 
 const (
-	numFields  = 6
+	numFields  = 7
 	colID      = 0
 	colName    = 1
 	colType    = 2
 	colParent  = 3
 	colTaxLine = 4
 	colDesc    = 5
+	colArchive = 6
+
+	// legacyFields is the column count before archived was added; such
+	// charts read as having every account active.
+	legacyFields = 6
 )
 
 // ReadAccounts reads chart-of-accounts.csv.
 func ReadAccounts(r io.Reader) ([]model.Account, error) {
 	cr := csv.NewReader(r)
-	cr.FieldsPerRecord = numFields
+	cr.FieldsPerRecord = -1 // legacy six-column charts are accepted
 
 	records, err := cr.ReadAll()
 	if err != nil {
@@ -49,7 +54,7 @@ func WriteAccounts(w io.Writer, accounts []model.Account) error {
 	cw := csv.NewWriter(w)
 	defer cw.Flush()
 
-	if err := cw.Write([]string{"account_id", "account_name", "account_type", "parent_id", "tax_line", "description"}); err != nil {
+	if err := cw.Write([]string{"account_id", "account_name", "account_type", "parent_id", "tax_line", "description", "archived"}); err != nil {
 		return fmt.Errorf("writing header: %w", err)
 	}
 
@@ -72,12 +77,16 @@ func MarshalAccount(acct model.Account) []string {
 	}
 	row[colTaxLine] = acct.TaxLine
 	row[colDesc] = acct.Description
+	if acct.Archived {
+		row[colArchive] = "true"
+	}
 	return row
 }
 
-// UnmarshalAccount converts a CSV row to an Account.
+// UnmarshalAccount converts a CSV row to an Account. Rows without the
+// archived column are active.
 func UnmarshalAccount(record []string) (model.Account, error) {
-	if len(record) != numFields {
+	if len(record) != numFields && len(record) != legacyFields {
 		return model.Account{}, fmt.Errorf("expected %d fields, got %d", numFields, len(record))
 	}
 
@@ -94,6 +103,14 @@ func UnmarshalAccount(record []string) (model.Account, error) {
 		}
 	}
 
+	var archived bool
+	if len(record) > colArchive && record[colArchive] != "" {
+		archived, err = strconv.ParseBool(record[colArchive])
+		if err != nil {
+			return model.Account{}, fmt.Errorf("parsing archived %q: %w", record[colArchive], err)
+		}
+	}
+
 	return model.Account{
 		ID:          id,
 		Name:        record[colName],
@@ -101,5 +118,6 @@ func UnmarshalAccount(record []string) (model.Account, error) {
 		ParentID:    parentID,
 		TaxLine:     record[colTaxLine],
 		Description: record[colDesc],
+		Archived:    archived,
 	}, nil
 }
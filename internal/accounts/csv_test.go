@@ -34,6 +34,38 @@ func TestRoundTrip(t *testing.T) {
 	assert.Equal(t, accounts[1].TaxLine, got[1].TaxLine)
 }
 
+func TestArchived_RoundTrip(t *testing.T) {
+	accounts := []model.Account{
+		{ID: 1010, Name: "Checking", Type: model.AccountTypeAsset},
+		{ID: 1020, Name: "Old Savings", Type: model.AccountTypeAsset, Archived: true},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteAccounts(&buf, accounts))
+	assert.Contains(t, buf.String(), "description,archived\n")
+
+	got, err := ReadAccounts(&buf)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.False(t, got[0].Archived)
+	assert.True(t, got[1].Archived)
+}
+
+func TestReadAccounts_LegacyColumns(t *testing.T) {
+	csv := "account_id,account_name,account_type,parent_id,tax_line,description\n" +
+		"1010,Checking,asset,,,Primary\n"
+
+	got, err := ReadAccounts(bytes.NewBufferString(csv))
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "Primary", got[0].Description)
+	assert.False(t, got[0].Archived, "charts without the column are active")
+
+	_, err = ReadAccounts(bytes.NewBufferString(csv + "1020,Savings,asset,,,,maybe\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `parsing archived "maybe"`)
+}
+
 func TestParentID(t *testing.T) {
 	accounts := []model.Account{
 		{ID: 1010, Name: "Checking", Type: model.AccountTypeAsset},
@@ -78,6 +110,54 @@ func TestDefaultChart_UnknownEntityType(t *testing.T) {
 	assert.NotEmpty(t, chart)
 }
 
+func TestDefaultChart_EntityTypes(t *testing.T) {
+	tests := []struct {
+		entityType string
+		equity     []string
+		extra      []int
+	}{
+		{"llc_single_member", []string{"Owner's Equity"}, nil},
+		{"sole_proprietor", []string{"Owner's Capital", "Owner's Draw"}, nil},
+		{"s_corp", []string{"Common Stock", "Additional Paid-In Capital", "Retained Earnings", "Shareholder Distributions"}, []int{2020, 5060, 5070, 5080}},
+		{"partnership", []string{"Partners' Capital", "Partners' Contributions", "Partners' Draws"}, []int{5060}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entityType, func(t *testing.T) {
+			chart := DefaultChart(tt.entityType)
+			assert.Empty(t, ValidateChart(chart))
+
+			svc := NewService(chart)
+			var equity []string
+			for _, a := range svc.ByType(model.AccountTypeEquity) {
+				equity = append(equity, a.Name)
+			}
+			assert.Equal(t, tt.equity, equity)
+
+			for _, id := range append([]int{1010, 2010, 4010, 5020}, tt.extra...) {
+				assert.True(t, svc.Exists(id), "expected account %d", id)
+			}
+		})
+	}
+
+	officer, ok := NewService(DefaultChart("s_corp")).Get(5060)
+	require.True(t, ok)
+	assert.Equal(t, "Officer Compensation", officer.Name)
+}
+
+func TestDefaultChart_TaxLines(t *testing.T) {
+	for _, entityType := range []string{"llc_single_member", "sole_proprietor"} {
+		software, ok := NewService(DefaultChart(entityType)).Get(5020)
+		require.True(t, ok)
+		assert.Equal(t, "schedule_c_18", software.TaxLine, entityType)
+	}
+	// S-corps and partnerships do not file Schedule C.
+	for _, entityType := range []string{"s_corp", "partnership"} {
+		for _, a := range DefaultChart(entityType) {
+			assert.Empty(t, a.TaxLine, "%s account %d", entityType, a.ID)
+		}
+	}
+}
+
 func TestReadTestdata(t *testing.T) {
 	f, err := os.Open("../../testdata/chart-of-accounts.csv")
 	require.NoError(t, err)
@@ -3,27 +3,108 @@ package accounts
 import "github.com/cleared-dev/cleared/internal/model"
 
 // DefaultChart returns the default chart of accounts for an entity type.
+// Every chart shares the same operating accounts (checking 1010, revenue
+// 4010, expenses 5010-5050) so imports and agents work unchanged; they differ
+// in equity and in entity-specific accounts such as officer compensation.
+// Only the charts of entities that file Schedule C (single-member LLCs and
+// sole proprietors) carry tax lines; S-corps and partnerships file Form
+// 1120-S and Form 1065, so their expense accounts are left unmapped.
+// Unknown entity types get the single-member LLC chart.
 func DefaultChart(entityType string) []model.Account {
 	switch entityType {
 	case "llc_single_member":
 		return llcSingleMemberChart()
+	case "sole_proprietor":
+		return soleProprietorChart()
+	case "s_corp":
+		return sCorpChart()
+	case "partnership":
+		return partnershipChart()
 	default:
 		return llcSingleMemberChart()
 	}
 }
 
 func llcSingleMemberChart() []model.Account {
-	return []model.Account{
+	return buildChart(
+		[]model.Account{
+			{ID: 3010, Name: "Owner's Equity", Type: model.AccountTypeEquity, Description: "Owner's equity"},
+		},
+		nil, nil,
+	)
+}
+
+func soleProprietorChart() []model.Account {
+	return buildChart(
+		[]model.Account{
+			{ID: 3010, Name: "Owner's Capital", Type: model.AccountTypeEquity, Description: "Owner's investment in the business"},
+			{ID: 3020, Name: "Owner's Draw", Type: model.AccountTypeEquity, Description: "Money taken out by the owner"},
+		},
+		nil, nil,
+	)
+}
+
+func sCorpChart() []model.Account {
+	return withoutTaxLines(buildChart(
+		[]model.Account{
+			{ID: 3010, Name: "Common Stock", Type: model.AccountTypeEquity, Description: "Shareholders' stock"},
+			{ID: 3020, Name: "Additional Paid-In Capital", Type: model.AccountTypeEquity, Description: "Capital contributed above par"},
+			{ID: 3030, Name: "Retained Earnings", Type: model.AccountTypeEquity, Description: "Accumulated undistributed earnings"},
+			{ID: 3040, Name: "Shareholder Distributions", Type: model.AccountTypeEquity, Description: "Distributions to shareholders"},
+		},
+		[]model.Account{
+			{ID: 2020, Name: "Payroll Liabilities", Type: model.AccountTypeLiability, Description: "Withheld and employer payroll taxes owed"},
+		},
+		[]model.Account{
+			{ID: 5060, Name: "Officer Compensation", Type: model.AccountTypeExpense, Description: "Shareholder-employee wages"},
+			{ID: 5070, Name: "Salaries & Wages", Type: model.AccountTypeExpense, Description: "Non-officer employee wages"},
+			{ID: 5080, Name: "Payroll Taxes", Type: model.AccountTypeExpense, Description: "Employer share of payroll taxes"},
+		},
+	))
+}
+
+func partnershipChart() []model.Account {
+	return withoutTaxLines(buildChart(
+		[]model.Account{
+			{ID: 3010, Name: "Partners' Capital", Type: model.AccountTypeEquity, Description: "Partners' capital accounts"},
+			{ID: 3020, Name: "Partners' Contributions", Type: model.AccountTypeEquity, Description: "Capital contributed by partners"},
+			{ID: 3030, Name: "Partners' Draws", Type: model.AccountTypeEquity, Description: "Distributions to partners"},
+		},
+		nil,
+		[]model.Account{
+			{ID: 5060, Name: "Guaranteed Payments to Partners", Type: model.AccountTypeExpense, Description: "Fixed payments to partners for services or capital"},
+		},
+	))
+}
+
+// withoutTaxLines clears the Schedule C tax lines of the shared accounts,
+// for entities that do not file Schedule C.
+func withoutTaxLines(chart []model.Account) []model.Account {
+	for i := range chart {
+		chart[i].TaxLine = ""
+	}
+	return chart
+}
+
+// buildChart assembles a chart from the shared operating accounts plus an
+// entity's equity accounts and any extra liabilities and expenses, in
+// account ID order within each type.
+func buildChart(equity, liabilities, expenses []model.Account) []model.Account {
+	chart := []model.Account{
 		{ID: 1010, Name: "Business Checking", Type: model.AccountTypeAsset, Description: "Primary checking account"},
 		{ID: 1020, Name: "Business Savings", Type: model.AccountTypeAsset, Description: "Savings account"},
 		{ID: 2010, Name: "Credit Card", Type: model.AccountTypeLiability, Description: "Business credit card"},
-		{ID: 3010, Name: "Owner's Equity", Type: model.AccountTypeEquity, Description: "Owner's equity"},
-		{ID: 4010, Name: "Service Revenue", Type: model.AccountTypeRevenue},
-		{ID: 4020, Name: "Product Revenue", Type: model.AccountTypeRevenue},
-		{ID: 5010, Name: "Advertising & Marketing", Type: model.AccountTypeExpense, TaxLine: "schedule_c_8", Description: "Advertising costs"},
-		{ID: 5020, Name: "Software & SaaS", Type: model.AccountTypeExpense, TaxLine: "schedule_c_18", Description: "Software subscriptions"},
-		{ID: 5030, Name: "Office Supplies", Type: model.AccountTypeExpense, TaxLine: "schedule_c_18", Description: "Office supplies and expenses"},
-		{ID: 5040, Name: "Professional Services", Type: model.AccountTypeExpense, TaxLine: "schedule_c_17", Description: "Legal, accounting, consulting"},
-		{ID: 5050, Name: "Shipping & Postage", Type: model.AccountTypeExpense, TaxLine: "schedule_c_18", Description: "Postage and shipping costs"},
 	}
+	chart = append(chart, liabilities...)
+	chart = append(chart, equity...)
+	chart = append(chart,
+		model.Account{ID: 4010, Name: "Service Revenue", Type: model.AccountTypeRevenue},
+		model.Account{ID: 4020, Name: "Product Revenue", Type: model.AccountTypeRevenue},
+		model.Account{ID: 5010, Name: "Advertising & Marketing", Type: model.AccountTypeExpense, TaxLine: "schedule_c_8", Description: "Advertising costs"},
+		model.Account{ID: 5020, Name: "Software & SaaS", Type: model.AccountTypeExpense, TaxLine: "schedule_c_18", Description: "Software subscriptions"},
+		model.Account{ID: 5030, Name: "Office Supplies", Type: model.AccountTypeExpense, TaxLine: "schedule_c_18", Description: "Office supplies and expenses"},
+		model.Account{ID: 5040, Name: "Professional Services", Type: model.AccountTypeExpense, TaxLine: "schedule_c_17", Description: "Legal, accounting, consulting"},
+		model.Account{ID: 5050, Name: "Shipping & Postage", Type: model.AccountTypeExpense, TaxLine: "schedule_c_18", Description: "Postage and shipping costs"},
+	)
+	return append(chart, expenses...)
 }
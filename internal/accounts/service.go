@@ -1,9 +1,12 @@
 package accounts
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/cleared-dev/cleared/internal/model"
 )
@@ -36,9 +39,38 @@ func Load(repoRoot string) (*Service, error) {
 	if err != nil {
 		return nil, fmt.Errorf("reading chart of accounts: %w", err)
 	}
+	if errs := ValidateChart(accts); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid chart of accounts: %w", errors.Join(errs...))
+	}
 	return NewService(accts), nil
 }
 
+// ValidateChart checks that account IDs are unique, types are known, and
+// every non-zero ParentID names an account in the chart. It returns one
+// error per problem, in chart order.
+func ValidateChart(accounts []model.Account) []error {
+	ids := make(map[int]bool, len(accounts))
+	for _, a := range accounts {
+		ids[a.ID] = true
+	}
+
+	var errs []error
+	seen := make(map[int]bool, len(accounts))
+	for _, a := range accounts {
+		if seen[a.ID] {
+			errs = append(errs, fmt.Errorf("account %d: duplicate ID", a.ID))
+		}
+		seen[a.ID] = true
+		if !a.Type.Valid() {
+			errs = append(errs, fmt.Errorf("account %d: unknown type %q", a.ID, a.Type))
+		}
+		if a.ParentID != 0 && !ids[a.ParentID] {
+			errs = append(errs, fmt.Errorf("account %d: parent account %d does not exist", a.ID, a.ParentID))
+		}
+	}
+	return errs
+}
+
 // All returns all accounts.
 func (s *Service) All() []model.Account {
 	return s.accounts
@@ -50,12 +82,20 @@ func (s *Service) Get(id int) (model.Account, bool) {
 	return a, ok
 }
 
-// Exists reports whether an account ID exists.
+// Exists reports whether an account ID exists. Archived accounts exist, so
+// their history still validates.
 func (s *Service) Exists(id int) bool {
 	_, ok := s.byID[id]
 	return ok
 }
 
+// Postable reports whether new entries may be booked to an account: it
+// exists and is not archived.
+func (s *Service) Postable(id int) bool {
+	a, ok := s.byID[id]
+	return ok && !a.Archived
+}
+
 // ByType returns all accounts of the given type.
 func (s *Service) ByType(accountType model.AccountType) []model.Account {
 	var result []model.Account
@@ -67,6 +107,100 @@ func (s *Service) ByType(accountType model.AccountType) []model.Account {
 	return result
 }
 
+// Search returns the postable accounts whose name contains query, ignoring
+// case, in chart order. An empty query matches nothing.
+func (s *Service) Search(query string) []model.Account {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+	var result []model.Account
+	for _, a := range s.accounts {
+		if !a.Archived && strings.Contains(strings.ToLower(a.Name), query) {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// Children returns the accounts whose parent is id, in chart order.
+func (s *Service) Children(id int) []model.Account {
+	var result []model.Account
+	for _, a := range s.accounts {
+		if a.ParentID == id && a.ID != id {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// Subtree returns the account id followed by all of its descendants,
+// depth-first in chart order. Each account appears once even if the chart
+// has a parent cycle. It returns nil when id is not in the chart.
+func (s *Service) Subtree(id int) []model.Account {
+	root, ok := s.byID[id]
+	if !ok {
+		return nil
+	}
+	visited := map[int]bool{id: true}
+	result := []model.Account{root}
+	for i := 0; i < len(result); i++ {
+		var next []model.Account
+		for _, c := range s.Children(result[i].ID) {
+			if !visited[c.ID] {
+				visited[c.ID] = true
+				next = append(next, c)
+			}
+		}
+		result = slices.Insert(result, i+1, next...)
+	}
+	return result
+}
+
+// Add validates acct and appends it to the chart. The ID must be positive
+// and unused, the name non-empty, the type known, and the parent (if any)
+// an existing account. Call Save to persist the change.
+func (s *Service) Add(acct model.Account) error {
+	switch {
+	case acct.ID <= 0:
+		return fmt.Errorf("account ID must be positive, got %d", acct.ID)
+	case strings.TrimSpace(acct.Name) == "":
+		return errors.New("account name is required")
+	case !acct.Type.Valid():
+		return fmt.Errorf("unknown account type %q (want asset, liability, equity, revenue, or expense)", acct.Type)
+	}
+	if existing, ok := s.byID[acct.ID]; ok {
+		return fmt.Errorf("account %d already exists (%s)", acct.ID, existing.Name)
+	}
+	if acct.ParentID != 0 && !s.Exists(acct.ParentID) {
+		return fmt.Errorf("parent account %d does not exist", acct.ParentID)
+	}
+
+	s.accounts = append(s.accounts, acct)
+	s.byID[acct.ID] = acct
+	return nil
+}
+
+// Archive closes account id to new postings. The account stays in the chart
+// so existing entries still validate. Call Save to persist the change.
+func (s *Service) Archive(id int) error {
+	a, ok := s.byID[id]
+	if !ok {
+		return fmt.Errorf("account %d does not exist", id)
+	}
+	if a.Archived {
+		return fmt.Errorf("account %d is already archived", id)
+	}
+	a.Archived = true
+	s.byID[id] = a
+	for i := range s.accounts {
+		if s.accounts[i].ID == id {
+			s.accounts[i] = a
+		}
+	}
+	return nil
+}
+
 // Save writes the chart of accounts to accounts/chart-of-accounts.csv.
 func (s *Service) Save(repoRoot string) error {
 	dir := filepath.Join(repoRoot, "accounts")
@@ -96,3 +96,164 @@ func TestSaveRoundTrip(t *testing.T) {
 		assert.Equal(t, orig.Type, got.Type)
 	}
 }
+
+func TestAdd(t *testing.T) {
+	svc := NewService(DefaultChart("llc_single_member"))
+	n := len(svc.All())
+
+	require.NoError(t, svc.Add(model.Account{ID: 5090, Name: "Travel", Type: model.AccountTypeExpense, TaxLine: "schedule_c_24a"}))
+	assert.Len(t, svc.All(), n+1)
+	got, ok := svc.Get(5090)
+	require.True(t, ok)
+	assert.Equal(t, "Travel", got.Name)
+
+	tests := []struct {
+		acct model.Account
+		want string
+	}{
+		{model.Account{ID: 1010, Name: "Dup", Type: model.AccountTypeAsset}, "account 1010 already exists (Business Checking)"},
+		{model.Account{ID: 6000, Name: "Bad", Type: "income"}, `unknown account type "income"`},
+		{model.Account{ID: 6001, Name: " ", Type: model.AccountTypeExpense}, "name is required"},
+		{model.Account{ID: 0, Name: "Zero", Type: model.AccountTypeExpense}, "must be positive"},
+		{model.Account{ID: 6002, Name: "Orphan", Type: model.AccountTypeExpense, ParentID: 42}, "parent account 42 does not exist"},
+	}
+	for _, tt := range tests {
+		err := svc.Add(tt.acct)
+		require.Error(t, err, tt.acct.Name)
+		assert.Contains(t, err.Error(), tt.want)
+	}
+	assert.Len(t, svc.All(), n+1, "rejected accounts are not added")
+}
+
+func TestValidateChart(t *testing.T) {
+	assert.Empty(t, ValidateChart(DefaultChart("llc_single_member")))
+
+	tests := []struct {
+		name  string
+		chart []model.Account
+		want  string
+	}{
+		{
+			name: "duplicate ID",
+			chart: []model.Account{
+				{ID: 1010, Name: "Checking", Type: model.AccountTypeAsset},
+				{ID: 1010, Name: "Savings", Type: model.AccountTypeAsset},
+			},
+			want: "account 1010: duplicate ID",
+		},
+		{
+			name:  "bad type",
+			chart: []model.Account{{ID: 1010, Name: "Checking", Type: "cash"}},
+			want:  `account 1010: unknown type "cash"`,
+		},
+		{
+			name:  "dangling parent",
+			chart: []model.Account{{ID: 1011, Name: "Sub-checking", Type: model.AccountTypeAsset, ParentID: 1010}},
+			want:  "account 1011: parent account 1010 does not exist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateChart(tt.chart)
+			require.Len(t, errs, 1)
+			assert.EqualError(t, errs[0], tt.want)
+		})
+	}
+}
+
+func TestLoad_InvalidChart(t *testing.T) {
+	dir := t.TempDir()
+	chart := []model.Account{
+		{ID: 1010, Name: "Checking", Type: model.AccountTypeAsset},
+		{ID: 1010, Name: "Savings", Type: "cash", ParentID: 1000},
+	}
+	require.NoError(t, NewService(chart).Save(dir))
+
+	_, err := Load(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid chart of accounts")
+	assert.Contains(t, err.Error(), "duplicate ID")
+	assert.Contains(t, err.Error(), `unknown type "cash"`)
+	assert.Contains(t, err.Error(), "parent account 1000 does not exist")
+}
+
+// nestedChart has two levels under 5000: 5000 > 5100 > 5110/5120, and
+// 5000 > 5200.
+func nestedChart() []model.Account {
+	return []model.Account{
+		{ID: 5000, Name: "Operating Expenses", Type: model.AccountTypeExpense},
+		{ID: 5100, Name: "Technology", Type: model.AccountTypeExpense, ParentID: 5000},
+		{ID: 5110, Name: "Software", Type: model.AccountTypeExpense, ParentID: 5100},
+		{ID: 5200, Name: "Travel", Type: model.AccountTypeExpense, ParentID: 5000},
+		{ID: 5120, Name: "Hosting", Type: model.AccountTypeExpense, ParentID: 5100},
+		{ID: 6000, Name: "Other", Type: model.AccountTypeExpense},
+	}
+}
+
+func ids(accts []model.Account) []int {
+	result := make([]int, len(accts))
+	for i, a := range accts {
+		result[i] = a.ID
+	}
+	return result
+}
+
+func TestChildren(t *testing.T) {
+	svc := NewService(nestedChart())
+
+	assert.Equal(t, []int{5100, 5200}, ids(svc.Children(5000)))
+	assert.Equal(t, []int{5110, 5120}, ids(svc.Children(5100)), "chart order")
+	assert.Empty(t, svc.Children(5110))
+	assert.Empty(t, svc.Children(9999))
+}
+
+func TestSearch(t *testing.T) {
+	chart := nestedChart()
+	chart[4].Archived = true // Hosting
+	svc := NewService(chart)
+
+	assert.Equal(t, []int{5110}, ids(svc.Search("soft")))
+	assert.Equal(t, []int{5000}, ids(svc.Search(" OPERATING ")), "case-insensitive substring")
+	assert.Empty(t, svc.Search("hosting"), "archived accounts are not candidates")
+	assert.Empty(t, svc.Search(""))
+}
+
+func TestSubtree(t *testing.T) {
+	svc := NewService(nestedChart())
+
+	assert.Equal(t, []int{5000, 5100, 5110, 5120, 5200}, ids(svc.Subtree(5000)), "depth-first")
+	assert.Equal(t, []int{5100, 5110, 5120}, ids(svc.Subtree(5100)))
+	assert.Equal(t, []int{5110}, ids(svc.Subtree(5110)))
+	assert.Nil(t, svc.Subtree(9999))
+}
+
+func TestSubtree_Cycle(t *testing.T) {
+	svc := NewService([]model.Account{
+		{ID: 5000, Name: "A", Type: model.AccountTypeExpense, ParentID: 5200},
+		{ID: 5100, Name: "B", Type: model.AccountTypeExpense, ParentID: 5000},
+		{ID: 5200, Name: "C", Type: model.AccountTypeExpense, ParentID: 5100},
+		{ID: 5300, Name: "Self", Type: model.AccountTypeExpense, ParentID: 5300},
+	})
+
+	assert.Equal(t, []int{5000, 5100, 5200}, ids(svc.Subtree(5000)))
+	assert.Equal(t, []int{5300}, ids(svc.Subtree(5300)))
+	assert.Empty(t, svc.Children(5300), "an account is not its own child")
+}
+
+func TestArchive(t *testing.T) {
+	svc := NewService(DefaultChart("llc_single_member"))
+	require.True(t, svc.Postable(1020))
+
+	require.NoError(t, svc.Archive(1020))
+	assert.True(t, svc.Exists(1020), "archived accounts still exist")
+	assert.False(t, svc.Postable(1020))
+	assert.True(t, svc.Postable(1010))
+	assert.False(t, svc.Postable(9999))
+
+	acct, _ := svc.Get(1020)
+	assert.True(t, acct.Archived)
+	assert.True(t, svc.All()[1].Archived, "All reflects the change")
+
+	assert.EqualError(t, svc.Archive(1020), "account 1020 is already archived")
+	assert.EqualError(t, svc.Archive(9999), "account 9999 does not exist")
+}
@@ -6,6 +6,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -18,13 +20,18 @@ type Entry struct {
 	Details    string
 	EntryID    string
 	CommitHash string
+	// DurationMS is how long the action took, or 0 if it was not timed.
+	DurationMS int64
 }
 
 // Header is the CSV header for agent-log.csv.
-const Header = "timestamp,agent,action,details,entry_id,commit_hash"
+const Header = "timestamp,agent,action,details,entry_id,commit_hash,duration_ms"
 
 const (
-	numFields     = 6
+	numFields = 7
+	// legacyFields is the column count before duration_ms was added. Such
+	// rows still read, with DurationMS 0.
+	legacyFields  = 6
 	logDir        = "logs"
 	logFile       = "logs/agent-log.csv"
 	colTimestamp  = 0
@@ -33,6 +40,7 @@ const (
 	colDetails    = 3
 	colEntryID    = 4
 	colCommitHash = 5
+	colDurationMS = 6
 )
 
 // MarshalEntry converts an Entry to a CSV row.
@@ -44,12 +52,16 @@ func MarshalEntry(e Entry) []string {
 	row[colDetails] = e.Details
 	row[colEntryID] = e.EntryID
 	row[colCommitHash] = e.CommitHash
+	if e.DurationMS != 0 {
+		row[colDurationMS] = strconv.FormatInt(e.DurationMS, 10)
+	}
 	return row
 }
 
-// UnmarshalEntry converts a CSV row to an Entry.
+// UnmarshalEntry converts a CSV row to an Entry. Legacy rows without the
+// duration_ms column are accepted.
 func UnmarshalEntry(record []string) (Entry, error) {
-	if len(record) != numFields {
+	if len(record) != numFields && len(record) != legacyFields {
 		return Entry{}, fmt.Errorf("expected %d fields, got %d", numFields, len(record))
 	}
 
@@ -58,6 +70,13 @@ func UnmarshalEntry(record []string) (Entry, error) {
 		return Entry{}, fmt.Errorf("parsing timestamp %q: %w", record[colTimestamp], err)
 	}
 
+	var duration int64
+	if len(record) == numFields && record[colDurationMS] != "" {
+		if duration, err = strconv.ParseInt(record[colDurationMS], 10, 64); err != nil {
+			return Entry{}, fmt.Errorf("parsing duration_ms %q: %w", record[colDurationMS], err)
+		}
+	}
+
 	return Entry{
 		Timestamp:  ts,
 		Agent:      record[colAgent],
@@ -65,6 +84,7 @@ func UnmarshalEntry(record []string) (Entry, error) {
 		Details:    record[colDetails],
 		EntryID:    record[colEntryID],
 		CommitHash: record[colCommitHash],
+		DurationMS: duration,
 	}, nil
 }
 
@@ -105,6 +125,67 @@ func Append(repoRoot string, entries []Entry) error {
 	return cw.Error()
 }
 
+// DefaultMaxSize is the agent log size, in bytes, past which Rotate starts a
+// new file when no other limit is configured.
+const DefaultMaxSize = 10 << 20
+
+// rotatedStamp names rotated logs, agent-log.<stamp>.csv, so they sort in
+// the order they were rotated.
+const rotatedStamp = "20060102T150405.000Z"
+
+// Rotate renames <repoRoot>/logs/agent-log.csv to agent-log.<timestamp>.csv
+// once it exceeds maxSize bytes, so the next Append starts a fresh file. It
+// returns the rotated path, or "" if the log was small enough or missing.
+func Rotate(repoRoot string, maxSize int64) (string, error) {
+	path := filepath.Join(repoRoot, logFile)
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("checking agent log: %w", err)
+	}
+	if info.Size() <= maxSize {
+		return "", nil
+	}
+
+	rotated := filepath.Join(repoRoot, logDir, "agent-log."+time.Now().UTC().Format(rotatedStamp)+".csv")
+	if err := os.Rename(path, rotated); err != nil {
+		return "", fmt.Errorf("rotating agent log: %w", err)
+	}
+	return rotated, nil
+}
+
+// ReadAll returns the entries from every rotated agent log, oldest first,
+// followed by those in the current one.
+func ReadAll(repoRoot string) ([]Entry, error) {
+	rotated, err := filepath.Glob(filepath.Join(repoRoot, logDir, "agent-log.*.csv"))
+	if err != nil {
+		return nil, fmt.Errorf("listing rotated agent logs: %w", err)
+	}
+	slices.Sort(rotated)
+
+	var entries []Entry
+	for _, path := range rotated {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("opening agent log: %w", err)
+		}
+		got, err := readEntries(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		entries = append(entries, got...)
+	}
+
+	current, err := Read(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	return append(entries, current...), nil
+}
+
 // Read returns all entries from <repoRoot>/logs/agent-log.csv.
 // Returns an empty slice if the file does not exist.
 func Read(repoRoot string) ([]Entry, error) {
@@ -123,7 +204,9 @@ func Read(repoRoot string) ([]Entry, error) {
 
 func readEntries(r io.Reader) ([]Entry, error) {
 	cr := csv.NewReader(r)
-	cr.FieldsPerRecord = numFields
+	// Logs begun before duration_ms mix 6- and 7-column rows; UnmarshalEntry
+	// checks each row's count.
+	cr.FieldsPerRecord = -1
 
 	records, err := cr.ReadAll()
 	if err != nil {
@@ -144,3 +227,42 @@ func readEntries(r io.Reader) ([]Entry, error) {
 	}
 	return entries, nil
 }
+
+// Filter selects agent log entries. Zero fields match everything.
+type Filter struct {
+	Agent   string
+	Action  string
+	EntryID string
+	// Since and Until bound the timestamp: at or after Since, before Until.
+	Since time.Time
+	Until time.Time
+}
+
+// Match reports whether e passes every set field of f.
+func (f Filter) Match(e Entry) bool {
+	switch {
+	case f.Agent != "" && e.Agent != f.Agent,
+		f.Action != "" && e.Action != f.Action,
+		f.EntryID != "" && e.EntryID != f.EntryID,
+		!f.Since.IsZero() && e.Timestamp.Before(f.Since),
+		!f.Until.IsZero() && !e.Timestamp.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+// Query returns the entries across the current and rotated agent logs that
+// match filter, oldest first.
+func Query(repoRoot string, filter Filter) ([]Entry, error) {
+	entries, err := ReadAll(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	var matched []Entry
+	for _, e := range entries {
+		if filter.Match(e) {
+			matched = append(matched, e)
+		}
+	}
+	return matched, nil
+}
@@ -20,6 +20,7 @@ func testEntry() Entry {
 		Details:    "Categorized GITHUB as software_expense",
 		EntryID:    "TXN-20250115-001",
 		CommitHash: "abc1234",
+		DurationMS: 42,
 	}
 }
 
@@ -66,6 +67,7 @@ func TestRead_RoundTrip(t *testing.T) {
 	assert.Equal(t, original.Details, got.Details)
 	assert.Equal(t, original.EntryID, got.EntryID)
 	assert.Equal(t, original.CommitHash, got.CommitHash)
+	assert.Equal(t, original.DurationMS, got.DurationMS)
 }
 
 func TestRead_NotFound(t *testing.T) {
@@ -88,7 +90,8 @@ func TestRead_EmptyFile(t *testing.T) {
 func TestMarshalUnmarshal(t *testing.T) {
 	e := testEntry()
 	row := MarshalEntry(e)
-	assert.Len(t, row, 6)
+	assert.Len(t, row, 7)
+	assert.Equal(t, "42", row[6])
 
 	got, err := UnmarshalEntry(row)
 	require.NoError(t, err)
@@ -98,12 +101,42 @@ func TestMarshalUnmarshal(t *testing.T) {
 	assert.Equal(t, e.Details, got.Details)
 	assert.Equal(t, e.EntryID, got.EntryID)
 	assert.Equal(t, e.CommitHash, got.CommitHash)
+	assert.Equal(t, e.DurationMS, got.DurationMS)
+
+	e.DurationMS = 0
+	row = MarshalEntry(e)
+	assert.Empty(t, row[6], "untimed entries leave the column blank")
+	got, err = UnmarshalEntry(row)
+	require.NoError(t, err)
+	assert.Zero(t, got.DurationMS)
+}
+
+func TestRead_LegacySixColumns(t *testing.T) {
+	dir := t.TempDir()
+	legacy := "timestamp,agent,action,details,entry_id,commit_hash\n" +
+		"2025-01-15T10:30:00Z,ingest,log,Parsed 6 transactions,,abc1234\n"
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "logs"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logs", "agent-log.csv"), []byte(legacy), 0o644))
+
+	// New rows append to the legacy file, so reads see both shapes.
+	require.NoError(t, Append(dir, []Entry{testEntry()}))
+
+	entries, err := Read(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Parsed 6 transactions", entries[0].Details)
+	assert.Equal(t, "abc1234", entries[0].CommitHash)
+	assert.Zero(t, entries[0].DurationMS)
+	assert.Equal(t, int64(42), entries[1].DurationMS)
 }
 
 func TestUnmarshalEntry_BadFieldCount(t *testing.T) {
 	_, err := UnmarshalEntry([]string{"one", "two"})
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "expected 6 fields")
+	assert.Contains(t, err.Error(), "expected 7 fields")
+
+	_, err = UnmarshalEntry([]string{"2025-01-15T10:30:00Z", "a", "log", "", "", "", "soon"})
+	assert.ErrorContains(t, err, "duration_ms")
 }
 
 func TestTimestampFormat(t *testing.T) {
@@ -122,3 +155,82 @@ func TestAppend_CreatesDir(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, info.IsDir())
 }
+
+func TestQuery(t *testing.T) {
+	dir := t.TempDir()
+	at := func(hours int) time.Time { return testTime.Add(time.Duration(hours) * time.Hour) }
+	require.NoError(t, Append(dir, []Entry{
+		{Timestamp: at(0), Agent: "ingest", Action: "log", Details: "start"},
+		{Timestamp: at(1), Agent: "ingest", Action: "git_commit", Details: "import", EntryID: "2025-01-0001"},
+		{Timestamp: at(2), Agent: "categorize", Action: "log", Details: "review", EntryID: "2025-01-0001"},
+		{Timestamp: at(3), Agent: "categorize", Action: "git_commit", Details: "rules"},
+	}))
+
+	details := func(f Filter) []string {
+		t.Helper()
+		entries, err := Query(dir, f)
+		require.NoError(t, err)
+		var got []string
+		for _, e := range entries {
+			got = append(got, e.Details)
+		}
+		return got
+	}
+
+	assert.Equal(t, []string{"start", "import", "review", "rules"}, details(Filter{}))
+	assert.Equal(t, []string{"review", "rules"}, details(Filter{Agent: "categorize"}))
+	assert.Equal(t, []string{"import", "rules"}, details(Filter{Action: "git_commit"}))
+	assert.Equal(t, []string{"import", "review"}, details(Filter{EntryID: "2025-01-0001"}))
+	assert.Equal(t, []string{"import", "review"}, details(Filter{Since: at(1), Until: at(3)}), "since inclusive, until exclusive")
+	assert.Equal(t, []string{"review"}, details(Filter{Agent: "categorize", EntryID: "2025-01-0001", Since: at(1)}))
+	assert.Empty(t, details(Filter{Agent: "ingest", Action: "git_commit", Since: at(2)}))
+}
+
+func TestQuery_NoLog(t *testing.T) {
+	entries, err := Query(t.TempDir(), Filter{Agent: "ingest"})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestRotate(t *testing.T) {
+	dir := t.TempDir()
+	first := testEntry()
+	first.Details = "first"
+	require.NoError(t, Append(dir, []Entry{first}))
+
+	rotated, err := Rotate(dir, 1<<20)
+	require.NoError(t, err)
+	assert.Empty(t, rotated, "under the threshold")
+
+	rotated, err = Rotate(dir, 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, rotated)
+	assert.Regexp(t, `agent-log\.\d{8}T\d{6}\.\d{3}Z\.csv$`, rotated)
+	_, err = os.Stat(filepath.Join(dir, "logs", "agent-log.csv"))
+	require.ErrorIs(t, err, os.ErrNotExist, "current log starts fresh")
+
+	second := testEntry()
+	second.Details = "second"
+	require.NoError(t, Append(dir, []Entry{second}))
+
+	current, err := Read(dir)
+	require.NoError(t, err)
+	require.Len(t, current, 1)
+	assert.Equal(t, "second", current[0].Details)
+
+	all, err := ReadAll(dir)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "first", all[0].Details)
+	assert.Equal(t, "second", all[1].Details)
+
+	queried, err := Query(dir, Filter{Agent: "categorize"})
+	require.NoError(t, err)
+	assert.Len(t, queried, 2, "Query sees the full history")
+}
+
+func TestRotate_NoLog(t *testing.T) {
+	rotated, err := Rotate(t.TempDir(), 0)
+	require.NoError(t, err)
+	assert.Empty(t, rotated)
+}
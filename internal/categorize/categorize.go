@@ -0,0 +1,167 @@
+// Package categorize suggests the account a bank transaction should be booked
+// to, combining the categorization rules with how the same vendor was booked
+// before.
+package categorize
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/cleared-dev/cleared/internal/model"
+	"github.com/cleared-dev/cleared/internal/rules"
+)
+
+// Rule is a categorization rule, as loaded from categorization-rules.yaml.
+type Rule = rules.Rule
+
+// DefaultBankAccount is the account a transaction with no BankAccountID moved
+// through (Business Checking), as for imports.
+const DefaultBankAccount = 1010
+
+// Confidence bounds. History alone stays below MaxHistoryConfidence so that a
+// vendor with no rule is never auto-confirmed at the default threshold.
+const (
+	// HistoryBoost is added to a rule's confidence for each prior booking of
+	// the vendor to the rule's account.
+	HistoryBoost = 0.01
+	// MaxConfidence caps every suggestion.
+	MaxConfidence = 0.99
+	// BaseHistoryConfidence is the confidence of a history-only suggestion
+	// backed by a single prior booking.
+	BaseHistoryConfidence = 0.5
+	// HistoryStep is added per additional prior booking to a history-only
+	// suggestion.
+	HistoryStep = 0.1
+	// MaxHistoryConfidence caps a history-only suggestion.
+	MaxHistoryConfidence = 0.9
+)
+
+// Suggestion is the proposed category for a transaction. A zero AccountID
+// means nothing matched; the caller picks its own default account.
+type Suggestion struct {
+	AccountID    int
+	Counterparty string
+	Confidence   float64
+	Evidence     string
+	Tags         string // semicolon-separated, as on a leg
+}
+
+// Categorize suggests an account for txn. A matching rule picks the account
+// and its confidence, boosted by HistoryBoost for every prior booking of the
+// vendor to the same account. With no rule, the account the vendor was most
+// often booked to is suggested, with a confidence that grows with the number
+// of bookings and shrinks when they disagree. Prior bookings are history legs
+// off txn's bank account, on the category side of the transaction (debits for
+// money out, credits for money in), whose counterparty is the rule's vendor
+// or whose description matches txn's ignoring digits and case. Pending-review
+// and voided entries are not counted. The suggestion is tagged RecurringTag
+// when history and txn together show the vendor recurring monthly.
+func Categorize(txn model.BankTransaction, rls []Rule, history []model.Leg) (Suggestion, error) {
+	rule, ruled := rules.Match(rls, txn.Description)
+	if ruled && rule.AccountID == 0 {
+		return Suggestion{}, fmt.Errorf("rule %q has no account", rule.VendorPattern)
+	}
+
+	counts, total := priorBookings(txn, rule.VendorName, history)
+	top := 0
+	for account, n := range counts {
+		if n > counts[top] || (n == counts[top] && account < top) {
+			top = account
+		}
+	}
+
+	var s Suggestion
+	switch {
+	case ruled:
+		s = Suggestion{
+			AccountID:    rule.AccountID,
+			Counterparty: rule.VendorName,
+			Confidence:   rule.Confidence,
+			Evidence:     "rule: " + rule.VendorPattern,
+		}
+		if n := counts[rule.AccountID]; n > 0 {
+			s.Confidence = min(rule.Confidence+HistoryBoost*float64(n), MaxConfidence)
+			s.Evidence += fmt.Sprintf("; %d prior %s", n, plural(n, "booking"))
+		}
+	case total > 0:
+		n := counts[top]
+		confidence := min(BaseHistoryConfidence+HistoryStep*float64(n-1), MaxHistoryConfidence)
+		s = Suggestion{
+			AccountID:  top,
+			Confidence: confidence * float64(n) / float64(total),
+			Evidence:   fmt.Sprintf("history: %d of %d prior %s to %d", n, total, plural(total, "booking"), top),
+		}
+	default:
+		s = Suggestion{Evidence: "no confident match"}
+	}
+
+	current := model.Leg{
+		Date:         txn.Date,
+		Description:  txn.Description,
+		Debit:        txn.Amount.Abs(),
+		Counterparty: rule.VendorName,
+	}
+	if DetectRecurring(append(slices.Clip(history), current))[vendorKey(current)] {
+		s.Tags = RecurringTag
+	}
+	return s, nil
+}
+
+// priorBookings counts the category-side legs of history entries for the
+// same vendor as txn, by account, and returns the counts and their total.
+func priorBookings(txn model.BankTransaction, vendor string, history []model.Leg) (map[int]int, int) {
+	key := descriptionKey(txn.Description)
+	bank := cmp.Or(txn.BankAccountID, DefaultBankAccount)
+	voided := make(map[string]bool)
+	for _, leg := range history {
+		if leg.Status == model.StatusVoided {
+			voided[leg.Reference] = true
+		}
+	}
+
+	counts := make(map[int]int)
+	total := 0
+	for _, leg := range history {
+		if leg.AccountID == bank || leg.Status == model.StatusVoided ||
+			leg.Status == model.StatusPendingReview || voided[leg.EntryGroup()] {
+			continue
+		}
+		categorySide := leg.Debit.IsPositive()
+		if txn.Amount.IsPositive() {
+			categorySide = leg.Credit.IsPositive()
+		}
+		if !categorySide {
+			continue
+		}
+		sameVendor := (vendor != "" && strings.EqualFold(leg.Counterparty, vendor)) ||
+			(key != "" && descriptionKey(leg.Description) == key)
+		if sameVendor {
+			counts[leg.AccountID]++
+			total++
+		}
+	}
+	return counts, total
+}
+
+// descriptionKey normalizes a bank description for comparison: upper case,
+// with digits dropped and runs of spaces collapsed, so "INVOICE 1042" and
+// "INVOICE 1043" compare equal.
+func descriptionKey(desc string) string {
+	desc = strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, desc)
+	return strings.Join(strings.Fields(desc), " ")
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
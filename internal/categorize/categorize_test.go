@@ -0,0 +1,86 @@
+package categorize
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+var testRules = []Rule{
+	{VendorPattern: "GITHUB*", VendorName: "GitHub", AccountID: 5020, Confidence: 0.95},
+}
+
+func txn(desc, amount string) model.BankTransaction {
+	return model.BankTransaction{
+		Date:        time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+		Description: desc,
+		Amount:      decimal.RequireFromString(amount),
+	}
+}
+
+// booking returns the two legs of a prior expense booked from checking.
+func booking(month int, desc, counterparty string, account int, status model.EntryStatus) []model.Leg {
+	id := fmt.Sprintf("2025-%02d-0001", month)
+	amount := decimal.RequireFromString("4.00")
+	date := time.Date(2025, time.Month(month), 3, 0, 0, 0, 0, time.UTC)
+	return []model.Leg{
+		{EntryID: id + "a", Date: date, AccountID: account, Description: desc, Debit: amount, Counterparty: counterparty, Status: status},
+		{EntryID: id + "b", Date: date, AccountID: 1010, Description: desc, Credit: amount, Counterparty: counterparty, Status: status},
+	}
+}
+
+func TestCategorize_RuleHit(t *testing.T) {
+	s, err := Categorize(txn("GITHUB *PRO SUBSCRIPTION", "-4.00"), testRules, nil)
+	require.NoError(t, err)
+	assert.Equal(t, Suggestion{AccountID: 5020, Counterparty: "GitHub", Confidence: 0.95, Evidence: "rule: GITHUB*"}, s)
+
+	var history []model.Leg
+	for m := 1; m <= 3; m++ {
+		history = append(history, booking(m, "GITHUB *PRO SUBSCRIPTION", "GitHub", 5020, model.StatusAutoConfirmed)...)
+	}
+	s, err = Categorize(txn("GITHUB *PRO SUBSCRIPTION", "-4.00"), testRules, history)
+	require.NoError(t, err)
+	assert.Equal(t, 5020, s.AccountID)
+	assert.InDelta(t, 0.98, s.Confidence, 1e-9, "repeat vendor boosts the rule")
+	assert.Equal(t, "rule: GITHUB*; 3 prior bookings", s.Evidence)
+
+	_, err = Categorize(txn("GITHUB", "-4.00"), []Rule{{VendorPattern: "GITHUB*"}}, nil)
+	require.Error(t, err, "rule without an account")
+}
+
+func TestCategorize_HistoryHit(t *testing.T) {
+	var history []model.Leg
+	history = append(history, booking(1, "USPS PO 1234", "", 5050, model.StatusUserCorrected)...)
+	history = append(history, booking(2, "USPS PO 5678", "", 5050, model.StatusUserConfirmed)...)
+	history = append(history, booking(3, "USPS PO 9012", "", 5030, model.StatusPendingReview)...)
+
+	s, err := Categorize(txn("USPS PO 3456", "-8.75"), testRules, history)
+	require.NoError(t, err)
+	assert.Equal(t, 5050, s.AccountID)
+	assert.InDelta(t, 0.6, s.Confidence, 1e-9, "two bookings, pending review ignored")
+	assert.Equal(t, "history: 2 of 2 prior bookings to 5050", s.Evidence)
+
+	history = append(history, booking(4, "USPS PO 7890", "", 5030, model.StatusUserConfirmed)...)
+	s, err = Categorize(txn("USPS PO 3456", "-8.75"), testRules, history)
+	require.NoError(t, err)
+	assert.Equal(t, 5050, s.AccountID)
+	assert.InDelta(t, 0.4, s.Confidence, 1e-9, "disagreeing bookings lower confidence")
+}
+
+func TestCategorize_NoMatch(t *testing.T) {
+	history := booking(1, "USPS PO 1234", "", 5050, model.StatusUserConfirmed)
+
+	s, err := Categorize(txn("AMZN MKTP US*ABC123", "-42.99"), testRules, history)
+	require.NoError(t, err)
+	assert.Equal(t, Suggestion{Evidence: "no confident match"}, s)
+
+	s, err = Categorize(txn("USPS PO 1234", "8.75"), testRules, history)
+	require.NoError(t, err)
+	assert.Zero(t, s.AccountID, "money in never matches expense bookings")
+}
@@ -0,0 +1,95 @@
+package categorize
+
+import (
+	"slices"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// RecurringTag is the tag Categorize adds for a recurring vendor.
+const RecurringTag = "recurring"
+
+// Recurrence thresholds: a vendor is recurring once it is charged in
+// RecurringMonths consecutive months, each month's total within
+// RecurringTolerance (as a fraction) of the month before.
+const (
+	RecurringMonths    = 3
+	RecurringTolerance = 0.1
+)
+
+// DetectRecurring reports the vendors in legs that recur monthly, keyed by
+// vendor: the leg's counterparty, or its description ignoring digits and
+// case when it has none. Each entry counts once, at its amount; a vendor's
+// entries are totalled per month. Voided entries are ignored.
+func DetectRecurring(legs []model.Leg) map[string]bool {
+	voided := make(map[string]bool)
+	for _, leg := range legs {
+		if leg.Status == model.StatusVoided {
+			voided[leg.Reference] = true
+		}
+	}
+
+	// vendor -> month index -> total
+	monthly := make(map[string]map[int]decimal.Decimal)
+	seen := make(map[string]bool)
+	for _, leg := range legs {
+		group := leg.EntryGroup()
+		if leg.Status == model.StatusVoided || voided[group] || (group != "" && seen[group]) {
+			continue
+		}
+		seen[group] = true
+		vendor := vendorKey(leg)
+		if vendor == "" {
+			continue
+		}
+		if monthly[vendor] == nil {
+			monthly[vendor] = make(map[int]decimal.Decimal)
+		}
+		month := leg.Date.Year()*12 + int(leg.Date.Month())
+		monthly[vendor][month] = monthly[vendor][month].Add(decimal.Max(leg.Debit, leg.Credit))
+	}
+
+	recurring := make(map[string]bool)
+	for vendor, totals := range monthly {
+		if isRecurring(totals) {
+			recurring[vendor] = true
+		}
+	}
+	return recurring
+}
+
+// isRecurring reports whether totals has a run of RecurringMonths
+// consecutive months with stable amounts.
+func isRecurring(totals map[int]decimal.Decimal) bool {
+	months := make([]int, 0, len(totals))
+	for m := range totals {
+		months = append(months, m)
+	}
+	slices.Sort(months)
+
+	tolerance := decimal.NewFromFloat(RecurringTolerance)
+	run := 1
+	for i := 1; i < len(months); i++ {
+		prev, cur := totals[months[i-1]], totals[months[i]]
+		stable := cur.Sub(prev).Abs().LessThanOrEqual(prev.Mul(tolerance))
+		if months[i] == months[i-1]+1 && stable {
+			run++
+		} else {
+			run = 1
+		}
+		if run >= RecurringMonths {
+			return true
+		}
+	}
+	return false
+}
+
+// vendorKey identifies the vendor of a leg for recurrence.
+func vendorKey(leg model.Leg) string {
+	if leg.Counterparty != "" {
+		return leg.Counterparty
+	}
+	return descriptionKey(leg.Description)
+}
@@ -0,0 +1,71 @@
+package categorize
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+func charge(id string, year, month int, desc, counterparty, amount string) model.Leg {
+	return model.Leg{
+		EntryID:      id + "a",
+		Date:         time.Date(year, time.Month(month), 12, 0, 0, 0, 0, time.UTC),
+		AccountID:    5020,
+		Description:  desc,
+		Debit:        decimal.RequireFromString(amount),
+		Counterparty: counterparty,
+		Status:       model.StatusAutoConfirmed,
+	}
+}
+
+func TestDetectRecurring(t *testing.T) {
+	legs := []model.Leg{
+		// Monthly across the year end, with a small price change.
+		charge("2024-11-0001", 2024, 11, "GITHUB *PRO", "GitHub", "4.00"),
+		charge("2024-12-0001", 2024, 12, "GITHUB *PRO", "GitHub", "4.00"),
+		charge("2025-01-0001", 2025, 1, "GITHUB *PRO", "GitHub", "4.20"),
+		// No counterparty: grouped by description, ignoring digits.
+		charge("2025-01-0002", 2025, 1, "HOSTING 1001", "", "20.00"),
+		charge("2025-02-0001", 2025, 2, "HOSTING 1002", "", "20.00"),
+		charge("2025-03-0001", 2025, 3, "HOSTING 1003", "", "20.00"),
+		// A one-off charge.
+		charge("2025-02-0002", 2025, 2, "AMZN MKTP", "", "42.99"),
+		// Three months, but the amount jumps.
+		charge("2025-01-0003", 2025, 1, "AWS", "Amazon Web Services", "10.00"),
+		charge("2025-02-0003", 2025, 2, "AWS", "Amazon Web Services", "90.00"),
+		charge("2025-03-0003", 2025, 3, "AWS", "Amazon Web Services", "12.00"),
+		// Three charges, but with a gap month.
+		charge("2025-01-0004", 2025, 1, "DROPBOX", "Dropbox", "15.00"),
+		charge("2025-02-0004", 2025, 2, "DROPBOX", "Dropbox", "15.00"),
+		charge("2025-04-0001", 2025, 4, "DROPBOX", "Dropbox", "15.00"),
+	}
+	// The other leg of an entry is not a second charge.
+	second := legs[0]
+	second.EntryID, second.AccountID, second.Debit, second.Credit = "2024-11-0001b", 1010, decimal.Zero, second.Debit
+	legs = append(legs, second)
+
+	assert.Equal(t, map[string]bool{"GitHub": true, "HOSTING": true}, DetectRecurring(legs))
+}
+
+func TestCategorize_RecurringTag(t *testing.T) {
+	history := []model.Leg{
+		charge("2025-02-0001", 2025, 2, "GITHUB *PRO SUBSCRIPTION", "GitHub", "4.00"),
+		charge("2025-03-0001", 2025, 3, "GITHUB *PRO SUBSCRIPTION", "GitHub", "4.00"),
+	}
+	s, err := Categorize(txn("GITHUB *PRO SUBSCRIPTION", "-4.00"), testRules, history)
+	require.NoError(t, err)
+	assert.Equal(t, RecurringTag, s.Tags, "third consecutive month")
+
+	s, err = Categorize(txn("GITHUB *PRO SUBSCRIPTION", "-4.00"), testRules, history[:1])
+	require.NoError(t, err)
+	assert.Empty(t, s.Tags)
+
+	s, err = Categorize(txn("AMZN MKTP US*ABC123", "-42.99"), testRules, history)
+	require.NoError(t, err)
+	assert.Empty(t, s.Tags, "one-off charge")
+}
@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cleared-dev/cleared/internal/accounts"
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+func newAccountsCommand() *cobra.Command {
+	accountsCmd := &cobra.Command{
+		Use:   "accounts",
+		Short: "Chart of accounts operations",
+	}
+	accountsCmd.AddCommand(newAccountsListCommand())
+	accountsCmd.AddCommand(newAccountsAddCommand())
+	accountsCmd.AddCommand(newAccountsArchiveCommand())
+	return accountsCmd
+}
+
+func newAccountsListCommand() *cobra.Command {
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the chart of accounts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			return runAccountsList(os.Stdout, absDir)
+		},
+	}
+
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+func runAccountsList(w io.Writer, repoRoot string) error {
+	accts, err := accounts.Load(repoRoot)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tName\tType\tTax Line")
+	for _, a := range accts.All() {
+		name := a.Name
+		if a.Archived {
+			name += " (archived)"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", a.ID, name, a.Type, a.TaxLine)
+	}
+	return tw.Flush()
+}
+
+func newAccountsAddCommand() *cobra.Command {
+	var acct model.Account
+	var acctType string
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add an account to the chart of accounts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			acct.Type = model.AccountType(acctType)
+			return runAccountsAdd(absDir, acct)
+		},
+	}
+
+	cmd.Flags().IntVar(&acct.ID, "id", 0, "account ID (required)")
+	cmd.Flags().StringVar(&acct.Name, "name", "", "account name (required)")
+	cmd.Flags().StringVar(&acctType, "type", "", "asset, liability, equity, revenue, or expense (required)")
+	cmd.Flags().StringVar(&acct.TaxLine, "tax-line", "", "tax form line, e.g. schedule_c_18")
+	cmd.Flags().IntVar(&acct.ParentID, "parent", 0, "parent account ID")
+	cmd.Flags().StringVar(&acct.Description, "description", "", "account description")
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+	_ = cmd.MarkFlagRequired("id")
+	_ = cmd.MarkFlagRequired("name")
+	_ = cmd.MarkFlagRequired("type")
+
+	return cmd
+}
+
+func runAccountsAdd(repoRoot string, acct model.Account) error {
+	accts, err := accounts.Load(repoRoot)
+	if err != nil {
+		return err
+	}
+	if err := accts.Add(acct); err != nil {
+		return err
+	}
+	if err := accts.Save(repoRoot); err != nil {
+		return err
+	}
+
+	fmt.Printf("Added account %d %s (%s)\n", acct.ID, acct.Name, acct.Type)
+	return nil
+}
+
+func newAccountsArchiveCommand() *cobra.Command {
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "archive <id>",
+		Short: "Close an account to new postings, keeping its history",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid account ID %q: %w", args[0], err)
+			}
+			return runAccountsArchive(absDir, id)
+		},
+	}
+
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+func runAccountsArchive(repoRoot string, id int) error {
+	accts, err := accounts.Load(repoRoot)
+	if err != nil {
+		return err
+	}
+	if err := accts.Archive(id); err != nil {
+		return err
+	}
+	if err := accts.Save(repoRoot); err != nil {
+		return err
+	}
+
+	a, _ := accts.Get(id)
+	fmt.Printf("Archived account %d %s\n", a.ID, a.Name)
+	return nil
+}
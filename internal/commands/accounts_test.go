@@ -0,0 +1,75 @@
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountsList(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	out, err := runCleared(t, "accounts", "list", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Tax Line")
+	assert.Regexp(t, `1010\s+Business Checking\s+asset`, out)
+	assert.Regexp(t, `5020\s+Software & SaaS\s+expense\s+schedule_c_18`, out)
+}
+
+func TestAccountsAdd(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	out, err := runCleared(t, "accounts", "add", "--repo", dir,
+		"--id", "5090", "--name", "Travel", "--type", "expense", "--tax-line", "schedule_c_24a")
+	require.NoError(t, err, out)
+
+	out, err = runCleared(t, "accounts", "list", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Regexp(t, `5090\s+Travel\s+expense\s+schedule_c_24a`, out)
+}
+
+func TestAccountsAdd_Rejects(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	out, err := runCleared(t, "accounts", "add", "--repo", dir, "--id", "1010", "--name", "Other Checking", "--type", "asset")
+	require.Error(t, err)
+	assert.Contains(t, out, "account 1010 already exists (Business Checking)")
+
+	out, err = runCleared(t, "accounts", "add", "--repo", dir, "--id", "6000", "--name", "Sales", "--type", "income")
+	require.Error(t, err)
+	assert.Contains(t, out, `unknown account type "income"`)
+
+	out, err = runCleared(t, "accounts", "list", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.NotContains(t, out, "Other Checking")
+	assert.NotContains(t, out, "Sales")
+}
+
+func TestAccountsArchive(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	out, err := runCleared(t, "accounts", "archive", "1020", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Archived account 1020 Business Savings")
+
+	out, err = runCleared(t, "accounts", "list", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Regexp(t, `1020\s+Business Savings \(archived\)\s+asset`, out)
+
+	out, err = runCleared(t, "accounts", "archive", "1020", "--repo", dir)
+	require.Error(t, err)
+	assert.Contains(t, out, "already archived")
+}
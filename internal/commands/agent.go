@@ -1,13 +1,23 @@
 package commands
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/cleared-dev/cleared/internal/agentlog"
+	"github.com/cleared-dev/cleared/internal/config"
+	"github.com/cleared-dev/cleared/internal/gitops"
 	"github.com/cleared-dev/cleared/internal/sandbox"
 )
 
@@ -17,73 +27,271 @@ func newAgentCommand() *cobra.Command {
 		Short: "Agent operations",
 	}
 	agentCmd.AddCommand(newAgentRunCommand())
+	agentCmd.AddCommand(newAgentListCommand())
 	return agentCmd
 }
 
+func newAgentListCommand() *cobra.Command {
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the agents in the repository",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			return runAgentList(os.Stdout, absDir)
+		},
+	}
+
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+// runAgentList prints each agents/*.py by the name agent run takes, with the
+// description from its docstring.
+func runAgentList(w io.Writer, repoRoot string) error {
+	files, err := os.ReadDir(filepath.Join(repoRoot, "agents"))
+	if err != nil {
+		return fmt.Errorf("reading agents: %w", err)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Name\tDescription")
+	for _, f := range files {
+		name, ok := strings.CutSuffix(f.Name(), ".py")
+		if !ok || f.IsDir() {
+			continue
+		}
+		script, err := os.ReadFile(agentPath(repoRoot, name))
+		if err != nil {
+			return fmt.Errorf("reading agent %s: %w", name, err)
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", name, sandbox.ParseAgentMeta(string(script)).Description)
+	}
+	return tw.Flush()
+}
+
 func newAgentRunCommand() *cobra.Command {
-	var dryRun bool
+	var dryRun, allowDirty, branch bool
 	var repoDir string
 
 	cmd := &cobra.Command{
-		Use:   "run <name>",
-		Short: "Run an agent script",
-		Args:  cobra.ExactArgs(1),
+		Use:   "run <name>... [-- --key value...]",
+		Short: "Run one or more agent scripts",
+		Long: "Run agent scripts in order, sharing one sandbox bridge. Stops at the first failure.\n\n" +
+			"Arguments after -- are passed to every script, which reads them as a dict from ctx_args().\n\n" +
+			"With --branch the run commits on a new branch, agent/<name>/<timestamp>, to review and merge.",
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			names, scriptArgs := args, []string(nil)
+			if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+				names, scriptArgs = args[:dash], args[dash:]
+			}
+			if len(names) == 0 {
+				return errors.New("no agent named before --")
+			}
+			params, err := parseScriptArgs(scriptArgs)
+			if err != nil {
+				return err
+			}
+
 			absDir, err := filepath.Abs(repoDir)
 			if err != nil {
 				return fmt.Errorf("resolving path: %w", err)
 			}
-			return runAgent(absDir, args[0], dryRun)
+			if !dryRun {
+				if err := checkClean(absDir, allowDirty); err != nil {
+					return err
+				}
+			}
+			if !branch {
+				return runAgents(absDir, names, params, dryRun)
+			}
+			if dryRun {
+				return errors.New("--branch has nothing to commit with --dry-run")
+			}
+			name := runBranch(names, time.Now())
+			if err := gitops.CheckoutBranch(absDir, name); err != nil {
+				return fmt.Errorf("creating run branch: %w", err)
+			}
+			if err := runAgents(absDir, names, params, false); err != nil {
+				return fmt.Errorf("run failed; branch %s is left checked out with the partial run: %w", name, err)
+			}
+			fmt.Printf("Run committed on branch %s\n", name)
+			return nil
 		},
 	}
 
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "run without making changes")
+	cmd.Flags().BoolVar(&allowDirty, "allow-dirty", false, "run even if the repository has uncommitted changes")
+	cmd.Flags().BoolVar(&branch, "branch", false, "commit on a new agent/<name>/<timestamp> branch")
 	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
 
 	return cmd
 }
 
-func runAgent(repoRoot, name string, dryRun bool) error {
-	// Read agent script.
-	scriptPath := filepath.Join(repoRoot, "agents", name+".py")
-	script, err := os.ReadFile(scriptPath)
+// runBranch names the branch a --branch run commits on, e.g.
+// agent/ingest/20250103-060000, or agent/ingest+categorize/... for several.
+func runBranch(names []string, now time.Time) string {
+	return fmt.Sprintf("agent/%s/%s", strings.Join(names, "+"), now.UTC().Format("20060102-150405"))
+}
+
+// parseScriptArgs turns "--key value", "--key=value" and bare "--flag"
+// arguments into the dict ctx_args returns. Bare flags are true.
+func parseScriptArgs(args []string) (map[string]any, error) {
+	params := map[string]any{}
+	for i := 0; i < len(args); i++ {
+		key, ok := strings.CutPrefix(args[i], "--")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("unexpected script argument %q, want --key value", args[i])
+		}
+		if k, v, found := strings.Cut(key, "="); found {
+			params[k] = v
+			continue
+		}
+		if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+			params[key] = args[i+1]
+			i++
+			continue
+		}
+		params[key] = true
+	}
+	return params, nil
+}
+
+// checkClean refuses to run agents over uncommitted changes, which their
+// git_commit calls would sweep into the agent's commits wherever the agent
+// writes the same files. New files in import/ are the agents' input and the
+// agent log is theirs to write, so neither counts. With allowDirty it only
+// warns.
+func checkClean(repoRoot string, allowDirty bool) error {
+	changed, err := gitops.ChangedPaths(repoRoot)
 	if err != nil {
-		return fmt.Errorf("reading agent %s: %w", name, err)
+		return fmt.Errorf("checking for uncommitted changes: %w", err)
+	}
+	changed = slices.DeleteFunc(changed, func(p string) bool {
+		return strings.HasPrefix(p, "import/") || strings.HasPrefix(p, "logs/")
+	})
+	if len(changed) == 0 {
+		return nil
+	}
+
+	list := strings.Join(changed, ", ")
+	if !allowDirty {
+		return fmt.Errorf("repository has uncommitted changes (%s); commit them or pass --allow-dirty", list)
+	}
+	fmt.Fprintf(os.Stderr, "warning: running with uncommitted changes (%s); agent commits may include them\n", list)
+	return nil
+}
+
+// runAgents starts one bridge and runs each named agent on it in turn.
+func runAgents(repoRoot string, names []string, params map[string]any, dryRun bool) error {
+	// Fail fast on a missing agent before paying for bridge startup.
+	for _, name := range names {
+		if _, err := os.Stat(agentPath(repoRoot, name)); err != nil {
+			return fmt.Errorf("reading agent %s: %w", name, err)
+		}
 	}
 
-	// Start bridge.
-	bridge, err := sandbox.NewBridge()
+	cfg, err := config.Load(filepath.Join(repoRoot, "cleared.yaml"))
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if !dryRun {
+		maxSize := cmp.Or(cfg.Logs.AgentLogMaxBytes, agentlog.DefaultMaxSize)
+		if _, err := agentlog.Rotate(repoRoot, maxSize); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+
+	bridge, err := sandbox.NewBridge(sandbox.WithReadyTimeout(sandbox.DefaultReadyTimeout))
 	if err != nil {
 		return fmt.Errorf("starting bridge: %w", err)
 	}
 	defer bridge.Shutdown()
 
+	for _, name := range names {
+		if err := runAgent(bridge, repoRoot, name, params, dryRun); err != nil {
+			return err
+		}
+	}
+	printMetrics(bridge.Metrics())
+	return nil
+}
+
+// printMetrics writes a per-primitive call summary to stderr, slowest first.
+func printMetrics(metrics map[string]sandbox.PrimitiveMetrics) {
+	if len(metrics) == 0 {
+		return
+	}
+	names := slices.SortedFunc(maps.Keys(metrics), func(a, b string) int {
+		return cmp.Or(cmp.Compare(metrics[b].Duration, metrics[a].Duration), cmp.Compare(a, b))
+	})
+
+	fmt.Fprintln(os.Stderr, "primitive calls:")
+	for _, name := range names {
+		m := metrics[name]
+		fmt.Fprintf(os.Stderr, "  %-26s %5d  %s\n", name, m.Calls, m.Duration.Round(time.Microsecond))
+	}
+}
+
+func agentPath(repoRoot, name string) string {
+	return filepath.Join(repoRoot, "agents", name+".py")
+}
+
+// runAgent runs one agent on an already-running bridge, registering a fresh
+// runtime's primitives over any left by a previous run.
+func runAgent(bridge *sandbox.Bridge, repoRoot, name string, params map[string]any, dryRun bool) error {
+	// Read agent script.
+	script, err := os.ReadFile(agentPath(repoRoot, name))
+	if err != nil {
+		return fmt.Errorf("reading agent %s: %w", name, err)
+	}
+
 	// Create runtime and register primitives.
 	rt, err := sandbox.NewRuntime(repoRoot, name, dryRun)
 	if err != nil {
 		return fmt.Errorf("creating runtime: %w", err)
 	}
 	rt.Register(bridge)
+	rt.SetLogSink(printLogEntry)
+	rt.SetArgs(params)
 
 	// Run script.
-	externals := bridge.PrimitiveNames()
-	result, err := bridge.RunScript(string(script), externals)
+	externals, err := sandbox.ParseAgentMeta(string(script)).Externals(bridge.PrimitiveNames())
 	if err != nil {
-		return fmt.Errorf("agent %s failed: %w", name, err)
-	}
-
-	// Print result.
-	if result != nil {
-		fmt.Printf("%v\n", result)
+		return fmt.Errorf("agent %s: %w", name, err)
 	}
+	result, err := bridge.RunScript(string(script), externals)
 
-	// Write agent log.
+	// Write agent log, even for a failed run: it holds the bridge's stderr.
 	entries := rt.AgentLog()
 	if len(entries) > 0 {
 		if err := agentlog.Append(repoRoot, entries); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: failed to write agent log: %v\n", err)
 		}
 	}
+	if err != nil {
+		return fmt.Errorf("agent %s failed: %w", name, err)
+	}
 
+	// Print result.
+	if result != nil {
+		fmt.Printf("%v\n", result)
+	}
 	return nil
 }
+
+// printLogEntry echoes an agent log entry to stderr as the agent runs.
+func printLogEntry(e agentlog.Entry) {
+	if e.Action == "log" {
+		fmt.Fprintf(os.Stderr, "  [%s] %s\n", e.Agent, e.Details)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "  [%s] %s: %s\n", e.Agent, e.Action, e.Details)
+}
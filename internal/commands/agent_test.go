@@ -18,6 +18,33 @@ func requireUV(t *testing.T) {
 	}
 }
 
+func TestAgent_Help(t *testing.T) {
+	out, err := runCleared(t, "agent", "--help")
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Agent operations")
+	assert.Contains(t, out, "run")
+}
+
+func TestAgentList(t *testing.T) {
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	agents := filepath.Join(dir, "agents")
+	require.NoError(t, os.WriteFile(filepath.Join(agents, "ingest.py"),
+		[]byte("\"\"\"\nname: Daily Ingest\ndescription: Import new bank transactions\n\"\"\"\nctx_log('hi')\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(agents, "categorize.py"),
+		[]byte("# agents/categorize.py\n\"\"\"Categorize pending entries.\"\"\"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(agents, "README.md"), []byte("not an agent\n"), 0o644))
+
+	out, err := runCleared(t, "agent", "list", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Regexp(t, `categorize\s+Categorize pending entries\.`, out)
+	assert.Regexp(t, `ingest\s+Import new bank transactions`, out)
+	assert.NotContains(t, out, "README")
+	assert.Less(t, strings.Index(out, "categorize"), strings.Index(out, "ingest"), "sorted by name")
+}
+
 func TestAgentRun_Ingest(t *testing.T) {
 	requireUV(t)
 
@@ -40,6 +67,7 @@ func TestAgentRun_Ingest(t *testing.T) {
 	require.NoError(t, err)
 	err = os.WriteFile(filepath.Join(dir, "agents", "ingest.py"), agentData, 0o644)
 	require.NoError(t, err)
+	commitRepo(t, dir)
 
 	// Run agent.
 	out, err := runCleared(t, "agent", "run", "ingest", "--repo", dir)
@@ -56,10 +84,12 @@ func TestAgentRun_Ingest(t *testing.T) {
 	// Header + 12 legs (6 transactions * 2 legs each).
 	assert.Len(t, lines, 13, "expected header + 12 legs")
 
-	// Verify CSV moved to processed.
+	// Verify CSV moved to processed, alongside the manifest.
 	processedFiles, err := os.ReadDir(filepath.Join(dir, "import", "processed"))
 	require.NoError(t, err)
-	assert.Len(t, processedFiles, 1, "one file should be in processed/")
+	require.Len(t, processedFiles, 2, "the import and the manifest should be in processed/")
+	assert.FileExists(t, filepath.Join(dir, "import", "processed", "chase_checking.csv"))
+	assert.FileExists(t, filepath.Join(dir, "import", "processed", "manifest.csv"))
 
 	// Verify no CSVs left in import/.
 	importFiles, err := os.ReadDir(filepath.Join(dir, "import"))
@@ -85,6 +115,148 @@ func TestAgentRun_Ingest(t *testing.T) {
 	require.NoError(t, err, "agent-log.csv should exist")
 }
 
+func TestAgentRun_DryRun(t *testing.T) {
+	requireUV(t)
+
+	dir := t.TempDir()
+
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	csvData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "chase_checking.csv"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "import", "chase_checking.csv"), csvData, 0o644))
+
+	agentData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "ingest.py"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "agents", "ingest.py"), agentData, 0o644))
+	commitRepo(t, dir)
+
+	headBefore := gitHead(t, dir)
+
+	out, err := runCleared(t, "agent", "run", "ingest", "--repo", dir, "--dry-run")
+	require.NoError(t, err, "agent run failed: %s", out)
+
+	_, err = os.Stat(filepath.Join(dir, "2025", "01", "journal.csv"))
+	assert.ErrorIs(t, err, os.ErrNotExist, "dry run must not write journal.csv")
+
+	_, err = os.Stat(filepath.Join(dir, "import", "chase_checking.csv"))
+	require.NoError(t, err, "dry run must not move imported files")
+
+	assert.Equal(t, headBefore, gitHead(t, dir), "dry run must not commit")
+}
+
+func TestAgentRun_DryRunKeepsAgentLog(t *testing.T) {
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "agents", "hello.py"), []byte("ctx_log('hi')\n"), 0o644))
+	commitRepo(t, dir)
+	logPath := filepath.Join(dir, "logs", "agent-log.csv")
+	seeded := "timestamp,agent,action,details,entry_id,commit_hash\n2025-01-03T06:00:00Z,ingest,log,Parsed 6 transactions,,\n"
+	require.NoError(t, os.WriteFile(logPath, []byte(seeded), 0o644))
+	t.Setenv("CLEARED_LOGS_AGENT_LOG_MAX_BYTES", "10")
+
+	// Without uv the bridge fails to start; either way the log is not rotated.
+	_, _ = runCleared(t, "agent", "run", "hello", "--repo", dir, "--dry-run")
+
+	rotated, err := filepath.Glob(filepath.Join(dir, "logs", "agent-log.*.csv"))
+	require.NoError(t, err)
+	assert.Empty(t, rotated, "dry run must not rotate the agent log")
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), seeded), "the log is still in place")
+}
+
+func gitHead(t *testing.T, dir string) string {
+	t.Helper()
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	return strings.TrimSpace(string(out))
+}
+
+// commitRepo commits everything in dir, e.g. agents seeded by a test, so
+// agent run sees a clean tree.
+func commitRepo(t *testing.T, dir string) {
+	t.Helper()
+	for _, args := range [][]string{{"add", "-A"}, {"commit", "-q", "-m", "test: seed"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+}
+
+func TestAgentRun_DirtyRepo(t *testing.T) {
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "agents", "hello.py"), []byte("ctx_log('hi')\n"), 0o644))
+
+	out, err := runCleared(t, "agent", "run", "hello", "--repo", dir)
+	require.Error(t, err, "dirty tree is refused")
+	assert.Contains(t, out, "uncommitted changes (agents/hello.py)")
+	assert.Contains(t, out, "--allow-dirty")
+}
+
+func TestAgentRun_AllowDirty(t *testing.T) {
+	requireUV(t)
+
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "agents", "hello.py"), []byte("ctx_log('hi')\n"), 0o644))
+
+	out, err := runCleared(t, "agent", "run", "hello", "--repo", dir, "--allow-dirty")
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "warning: running with uncommitted changes")
+
+	// Committed, and with only an import file and the agent log changed, the
+	// tree counts as clean.
+	commitRepo(t, dir)
+	csvData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "chase_checking.csv"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "import", "chase_checking.csv"), csvData, 0o644))
+	out, err = runCleared(t, "agent", "run", "hello", "--repo", dir)
+	require.NoError(t, err, out)
+	out, err = runCleared(t, "agent", "run", "hello", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.NotContains(t, out, "warning")
+}
+
+func TestAgentRun_ScriptArgs(t *testing.T) {
+	requireUV(t)
+
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "agents", "echo.py"),
+		[]byte(`a = ctx_args()
+a["month"] + " " + str(a["force"])
+`), 0o644))
+	commitRepo(t, dir)
+
+	out, err := runCleared(t, "agent", "run", "echo", "--repo", dir, "--", "--month", "2025-01", "--force")
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "2025-01 True")
+}
+
+func TestAgentRun_BadScriptArgs(t *testing.T) {
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	out, err := runCleared(t, "agent", "run", "echo", "--repo", dir, "--", "2025-01")
+	require.Error(t, err)
+	assert.Contains(t, out, `unexpected script argument "2025-01"`)
+
+	out, err = runCleared(t, "agent", "run", "--repo", dir, "--", "--month", "2025-01")
+	require.Error(t, err)
+	assert.Contains(t, out, "no agent named before --")
+}
+
 func TestAgentRun_MissingAgent(t *testing.T) {
 	dir := t.TempDir()
 
@@ -96,3 +268,74 @@ func TestAgentRun_MissingAgent(t *testing.T) {
 	_, err = runCleared(t, "agent", "run", "nonexistent", "--repo", dir)
 	require.Error(t, err, "should fail for missing agent")
 }
+
+func TestAgentRun_Multiple(t *testing.T) {
+	requireUV(t)
+
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "agents", "one.py"), []byte(`ctx_log("one ran")
+1`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "agents", "two.py"), []byte(`ctx_log("two ran")
+2`), 0o644))
+	commitRepo(t, dir)
+
+	out, err := runCleared(t, "agent", "run", "one", "two", "--repo", dir)
+	require.NoError(t, err, "agent run failed: %s", out)
+
+	logData, err := os.ReadFile(filepath.Join(dir, "logs", "agent-log.csv"))
+	require.NoError(t, err)
+	assert.Contains(t, string(logData), "one ran")
+	assert.Contains(t, string(logData), "two ran")
+}
+
+func TestAgentRun_Branch(t *testing.T) {
+	requireUV(t)
+
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "agents", "ingest.py"), []byte(`journal_add_double(
+    date="2025-01-03", description="GitHub", debit_account=5020, credit_account=1010,
+    amount="4.00", status="auto-confirmed")
+git_commit("import: 1 transaction")
+`), 0o644))
+	commitRepo(t, dir)
+
+	gitOut := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		require.NoError(t, err, "git %v", args)
+		return strings.TrimSpace(string(out))
+	}
+	main, mainHead := gitOut("rev-parse", "--abbrev-ref", "HEAD"), gitOut("rev-parse", "HEAD")
+
+	out, err := runCleared(t, "agent", "run", "ingest", "--branch", "--repo", dir)
+	require.NoError(t, err, out)
+	branch := gitOut("rev-parse", "--abbrev-ref", "HEAD")
+	assert.Regexp(t, `^agent/ingest/\d{8}-\d{6}$`, branch)
+	assert.Contains(t, out, "Run committed on branch "+branch)
+
+	assert.Equal(t, "import: 1 transaction", gitOut("log", "-1", "--format=%s", branch), "the commit lands on the run branch")
+	assert.Equal(t, mainHead, gitOut("rev-parse", main), "main is untouched")
+
+	out, err = runCleared(t, "agent", "run", "ingest", "--branch", "--dry-run", "--repo", dir)
+	require.Error(t, err, out)
+}
+
+func TestAgentRun_BranchFailure(t *testing.T) {
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "agents", "broken.py"), []byte("raise ValueError('boom')\n"), 0o644))
+	commitRepo(t, dir)
+
+	// Fails in the script, or without uv when the bridge starts.
+	out, err := runCleared(t, "agent", "run", "broken", "--branch", "--repo", dir)
+	require.Error(t, err)
+	assert.NotContains(t, out, "Run committed")
+	assert.Regexp(t, `run failed; branch agent/broken/\d{8}-\d{6} is left checked out with the partial run`, out)
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cleared-dev/cleared/internal/accounts"
+	"github.com/cleared-dev/cleared/internal/journal"
+	"github.com/cleared-dev/cleared/internal/report"
+)
+
+func newBalanceCommand() *cobra.Command {
+	var asOf string
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "balance",
+		Short: "Show account balances (trial balance)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+
+			date := time.Now()
+			if asOf != "" {
+				if date, err = time.Parse("2006-01-02", asOf); err != nil {
+					return fmt.Errorf("invalid --as-of date: %w", err)
+				}
+			}
+			return runBalance(os.Stdout, absDir, date, jsonOutput(cmd))
+		},
+	}
+
+	cmd.Flags().StringVar(&asOf, "as-of", "", "balance date, YYYY-MM-DD (default today)")
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+// balanceResult is the --json shape of cleared balance.
+type balanceResult struct {
+	AsOf     string       `json:"as_of"`
+	Balances []balanceRow `json:"balances"`
+}
+
+type balanceRow struct {
+	AccountID int    `json:"account_id"`
+	Name      string `json:"name"`
+	Balance   string `json:"balance"` // fixed two decimals, e.g. "3301.76"
+}
+
+func runBalance(w io.Writer, repoRoot string, asOf time.Time, asJSON bool) error {
+	accts, err := accounts.Load(repoRoot)
+	if err != nil {
+		return err
+	}
+	reports := report.NewService(journal.NewService(repoRoot, accts), accts)
+
+	balances, err := reports.TrialBalance(asOf)
+	if err != nil {
+		return fmt.Errorf("computing balances: %w", err)
+	}
+
+	ids := make([]int, 0, len(balances))
+	for id := range balances {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	result := balanceResult{AsOf: asOf.Format("2006-01-02"), Balances: make([]balanceRow, len(ids))}
+	for i, id := range ids {
+		name := "(unknown)"
+		if a, ok := accts.Get(id); ok {
+			name = a.Name
+		}
+		result.Balances[i] = balanceRow{AccountID: id, Name: name, Balance: balances[id].StringFixed(2)}
+	}
+	if asJSON {
+		return writeJSON(w, result)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID\tAccount\t%12s\n", "Balance")
+	for _, row := range result.Balances {
+		fmt.Fprintf(tw, "%d\t%s\t%12s\n", row.AccountID, row.Name, row.Balance)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,53 @@
+package commands_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalance(t *testing.T) {
+	dir := newJournalRepo(t)
+
+	out, err := runCleared(t, "balance", "--repo", dir, "--as-of", "2025-01-31")
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Business Checking")
+	assert.Contains(t, out, "3301.76")
+	assert.Contains(t, out, "Software & SaaS")
+	assert.Contains(t, out, "146.50")
+	assert.NotContains(t, out, "Credit Card")
+}
+
+func TestBalance_JSON(t *testing.T) {
+	dir := newJournalRepo(t)
+
+	out, err := runClearedStdout(t, "balance", "--json", "--repo", dir, "--as-of", "2025-01-31")
+	require.NoError(t, err, out)
+
+	var result struct {
+		AsOf     string `json:"as_of"`
+		Balances []struct {
+			AccountID int    `json:"account_id"`
+			Name      string `json:"name"`
+			Balance   string `json:"balance"`
+		} `json:"balances"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &result), out)
+	assert.Equal(t, "2025-01-31", result.AsOf)
+	require.NotEmpty(t, result.Balances)
+	assert.Equal(t, 1010, result.Balances[0].AccountID)
+	assert.Equal(t, "Business Checking", result.Balances[0].Name)
+	assert.Equal(t, "3301.76", result.Balances[0].Balance)
+}
+
+func TestBalance_InvalidDate(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	_, err = runCleared(t, "balance", "--repo", dir, "--as-of", "31/01/2025")
+	require.Error(t, err)
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cleared-dev/cleared/internal/accounts"
+	"github.com/cleared-dev/cleared/internal/gitops"
+	"github.com/cleared-dev/cleared/internal/journal"
+)
+
+func newCloseCommand() *cobra.Command {
+	var month string
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "close",
+		Short: "Close a month with a git tag",
+		Long: `Close checks that a finished month's journal passes validation and is
+committed, then tags HEAD close-YYYY-MM. Later entries dated in a closed
+month are refused; pass --reopen to cleared journal add to book one anyway.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			m, err := time.Parse("2006-01", month)
+			if err != nil {
+				return fmt.Errorf("invalid --month: %w", err)
+			}
+			return runClose(os.Stdout, absDir, m, time.Now())
+		},
+	}
+
+	cmd.Flags().StringVar(&month, "month", "", "month to close, YYYY-MM (required)")
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+	_ = cmd.MarkFlagRequired("month")
+
+	return cmd
+}
+
+// runClose tags HEAD as the close of month once the month has ended, its
+// journal validates, and it has no uncommitted changes.
+func runClose(w io.Writer, repoRoot string, month, now time.Time) error {
+	name := month.Format("2006-01")
+	if !month.AddDate(0, 1, 0).Before(now) {
+		return fmt.Errorf("%s has not ended yet", name)
+	}
+	accts, err := accounts.Load(repoRoot)
+	if err != nil {
+		return fmt.Errorf("loading accounts: %w", err)
+	}
+
+	year, mon := month.Year(), int(month.Month())
+	legs, err := journal.NewService(repoRoot, accts).ReadMonth(year, mon)
+	if err != nil {
+		return err
+	}
+	if violations := journal.ValidateLegs(legs, accts, year, mon); len(violations) > 0 {
+		return fmt.Errorf("%s does not validate (%d violations); run cleared verify", name, len(violations))
+	}
+	changed, err := gitops.ChangedPaths(repoRoot)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(changed, journal.MonthFile(year, mon)) {
+		return fmt.Errorf("%s has uncommitted changes; commit them before closing", journal.MonthFile(year, mon))
+	}
+
+	tag := gitops.CloseTag(month)
+	if err := gitops.Tag(repoRoot, tag, "close: "+name); err != nil {
+		return fmt.Errorf("tagging: %w", err)
+	}
+	fmt.Fprintf(w, "Closed %s: %d legs, tagged %s\n", name, len(legs), tag)
+	return nil
+}
@@ -0,0 +1,68 @@
+package commands_test
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// journalAdd books a manual 12.00 domain renewal on date.
+func journalAdd(t *testing.T, dir, date string, extra ...string) (string, error) {
+	t.Helper()
+	args := append([]string{"journal", "add", "--repo", dir, "--date", date, "--debit-account", "5020",
+		"--credit-account", "1010", "--amount", "12.00", "--description", "Domain renewal"}, extra...)
+	return runCleared(t, args...)
+}
+
+func TestClose_Tags(t *testing.T) {
+	dir := newImportRepo(t)
+	out, err := runCleared(t, "import", "--repo", dir)
+	require.NoError(t, err, out)
+
+	out, err = runCleared(t, "close", "--month", "2025-01", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Closed 2025-01: 12 legs, tagged close-2025-01")
+
+	rev := exec.Command("git", "rev-parse", "close-2025-01^{commit}", "HEAD")
+	rev.Dir = dir
+	hashes, err := rev.Output()
+	require.NoError(t, err)
+	lines := strings.Fields(string(hashes))
+	assert.Equal(t, lines[1], lines[0], "HEAD is tagged")
+
+	out, err = runCleared(t, "close", "--month", "2025-01", "--repo", dir)
+	require.Error(t, err, "a month is closed once")
+	assert.Contains(t, out, "close-2025-01")
+
+	out, err = runCleared(t, "close", "--month", "2025-13", "--repo", dir)
+	require.Error(t, err)
+	assert.Contains(t, out, "invalid --month")
+
+	out, err = runCleared(t, "close", "--month", "2999-01", "--repo", dir)
+	require.Error(t, err)
+	assert.Contains(t, out, "2999-01 has not ended yet")
+}
+
+func TestClose_RefusesLaterPostings(t *testing.T) {
+	dir := newImportRepo(t)
+	out, err := journalAdd(t, dir, "2025-01-02")
+	require.NoError(t, err, out)
+	out, err = runCleared(t, "close", "--month", "2025-01", "--repo", dir)
+	require.NoError(t, err, out)
+
+	out, err = journalAdd(t, dir, "2025-01-31")
+	require.Error(t, err)
+	assert.Contains(t, out, "month is closed: 2025-01; pass --reopen")
+	out, err = runCleared(t, "import", "--repo", dir)
+	require.Error(t, err, "imports are refused too")
+	assert.Contains(t, out, "month is closed: 2025-01")
+
+	out, err = journalAdd(t, dir, "2025-01-31", "--reopen")
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Booked 2025-01-0002")
+	out, err = journalAdd(t, dir, "2025-02-01")
+	require.NoError(t, err, out)
+}
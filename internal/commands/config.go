@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/cleared-dev/cleared/internal/config"
+)
+
+func newConfigCommand() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Read and change cleared.yaml",
+	}
+	configCmd.AddCommand(newConfigGetCommand())
+	configCmd.AddCommand(newConfigSetCommand())
+	return configCmd
+}
+
+func newConfigGetCommand() *cobra.Command {
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print a config value, e.g. thresholds.auto_confirm",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			return runConfigGet(os.Stdout, absDir, args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+// runConfigGet prints a scalar as-is and a section as YAML.
+func runConfigGet(w io.Writer, repoRoot, key string) error {
+	cfg, err := config.Load(filepath.Join(repoRoot, "cleared.yaml"))
+	if err != nil {
+		return err
+	}
+
+	switch v := cfg.Get(key).(type) {
+	case nil:
+		return fmt.Errorf("unknown config key %q", key)
+	case map[string]any, []any:
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("marshaling %s: %w", key, err)
+		}
+		_, err = w.Write(data)
+		return err
+	default:
+		_, err := fmt.Fprintln(w, v)
+		return err
+	}
+}
+
+func newConfigSetCommand() *cobra.Command {
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Change a config value, e.g. thresholds.auto_confirm 0.9",
+		Long: `Change one value in cleared.yaml. The whole config is validated before it
+is saved, so an out-of-range threshold or unknown entity type is rejected
+and the file is left unchanged.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			return runConfigSet(os.Stdout, absDir, args[0], args[1])
+		},
+	}
+
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+func runConfigSet(w io.Writer, repoRoot, key, value string) error {
+	path := filepath.Join(repoRoot, "cleared.yaml")
+	// Overrides from the environment must not be written into the file.
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Set(key, value); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if err := config.Save(path, cfg); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Set %s = %v\n", key, cfg.Get(key))
+	return nil
+}
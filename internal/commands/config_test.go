@@ -0,0 +1,74 @@
+package commands_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cleared-dev/cleared/internal/config"
+)
+
+func TestConfigGet(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	out, err := runClearedStdout(t, "config", "get", "thresholds.auto_confirm", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Equal(t, "0.95\n", out)
+
+	out, err = runClearedStdout(t, "config", "get", "business", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "name: Test Corp\n")
+	assert.Contains(t, out, "entity_type: llc_single_member\n")
+
+	out, err = runCleared(t, "config", "get", "thresholds.nope", "--repo", dir)
+	require.Error(t, err)
+	assert.Contains(t, out, `unknown config key "thresholds.nope"`)
+}
+
+func TestConfigSet(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	out, err := runCleared(t, "config", "set", "thresholds.auto_confirm", "0.9", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Set thresholds.auto_confirm = 0.9")
+
+	cfg, err := config.Load(filepath.Join(dir, "cleared.yaml"))
+	require.NoError(t, err)
+	assert.InDelta(t, 0.9, cfg.Thresholds.AutoConfirm, 0.001)
+
+	out, err = runCleared(t, "config", "set", "thresholds.auto_confirm", "1.5", "--repo", dir)
+	require.Error(t, err)
+	assert.Contains(t, out, "thresholds.auto_confirm must be between 0 and 1, got 1.5")
+
+	cfg, err = config.Load(filepath.Join(dir, "cleared.yaml"))
+	require.NoError(t, err)
+	assert.InDelta(t, 0.9, cfg.Thresholds.AutoConfirm, 0.001, "rejected value is not saved")
+}
+
+func TestConfigSet_IgnoresEnvOverrides(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	t.Setenv("CLEARED_GIT_AUTHOR_EMAIL", "ci@example.com")
+	out, err := runClearedStdout(t, "config", "get", "git.author_email", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Equal(t, "ci@example.com\n", out)
+
+	out, err = runCleared(t, "config", "set", "business.name", "Renamed", "--repo", dir)
+	require.NoError(t, err, out)
+
+	cfg, err := config.LoadFile(filepath.Join(dir, "cleared.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", cfg.Business.Name)
+	assert.Equal(t, "agent@cleared.dev", cfg.Git.AuthorEmail, "the override is not saved")
+}
@@ -0,0 +1,201 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+
+	"github.com/cleared-dev/cleared/internal/gitops"
+	"github.com/cleared-dev/cleared/internal/journal"
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// journalFilePattern matches the journal month files, as Service.Months
+// globs them.
+const journalFilePattern = "[0-9][0-9][0-9][0-9]/[0-9][0-9]/journal.csv"
+
+func newDiffCommand() *cobra.Command {
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "diff <rev> [<to-rev>]",
+		Short: "Summarize the journal entries changed since a commit",
+		Long: `Diff compares the journal at <rev> with <to-rev> (default HEAD) and lists
+the entries added, changed, or removed, with their dates, amounts, and
+accounts. Revisions are anything git accepts, e.g. HEAD~1 or a commit hash.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			to := "HEAD"
+			if len(args) == 2 {
+				to = args[1]
+			}
+			return runDiff(os.Stdout, absDir, args[0], to, jsonOutput(cmd))
+		},
+	}
+
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+// diffEntry is one changed entry in cleared diff, and its --json shape.
+type diffEntry struct {
+	Change         string            `json:"change"` // added, changed, or removed
+	EntryID        string            `json:"entry_id"`
+	Date           string            `json:"date"` // YYYY-MM-DD
+	Description    string            `json:"description"`
+	Amount         string            `json:"amount"` // total debits, fixed two decimals
+	DebitAccounts  []int             `json:"debit_accounts"`
+	CreditAccounts []int             `json:"credit_accounts"`
+	Status         model.EntryStatus `json:"status"`
+}
+
+// runDiff reads each journal month that differs between from and to at both
+// revisions and prints the entries whose legs differ.
+func runDiff(w io.Writer, repoRoot, from, to string, asJSON bool) error {
+	files, err := gitops.DiffFiles(repoRoot, from, to)
+	if err != nil {
+		return err
+	}
+
+	entries := []diffEntry{}
+	for _, f := range files {
+		if ok, _ := path.Match(journalFilePattern, f.Path); !ok {
+			continue
+		}
+		var before, after []model.Leg
+		if f.Change != gitops.FileAdded {
+			if before, err = readLegsAt(repoRoot, from, f.Path); err != nil {
+				return err
+			}
+		}
+		if f.Change != gitops.FileDeleted {
+			if after, err = readLegsAt(repoRoot, to, f.Path); err != nil {
+				return err
+			}
+		}
+		entries = append(entries, diffEntries(before, after)...)
+	}
+
+	if asJSON {
+		return writeJSON(w, entries)
+	}
+
+	counts := make(map[string]int)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Change\tEntry\tDate\tAmount\tAccounts\tStatus\tDescription")
+	for _, e := range entries {
+		counts[e.Change]++
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s/%s\t%s\t%s\n", e.Change, e.EntryID, e.Date, e.Amount,
+			joinInts(e.DebitAccounts), joinInts(e.CreditAccounts), e.Status, e.Description)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%d added, %d changed, %d removed\n", counts["added"], counts["changed"], counts["removed"])
+	return nil
+}
+
+// readLegsAt reads the journal file at p as of rev.
+func readLegsAt(repoRoot, rev, p string) ([]model.Leg, error) {
+	data, err := gitops.ShowFile(repoRoot, rev, p)
+	if err != nil {
+		return nil, err
+	}
+	legs, err := journal.ReadLegs(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s at %s: %w", p, rev, err)
+	}
+	return legs, nil
+}
+
+// diffEntries compares one month's legs before and after, entry by entry,
+// in entry order.
+func diffEntries(before, after []model.Leg) []diffEntry {
+	old, oldIDs := groupLegs(before)
+	cur, curIDs := groupLegs(after)
+
+	var entries []diffEntry
+	for _, id := range curIDs {
+		prev, existed := old[id]
+		switch {
+		case !existed:
+			entries = append(entries, summarizeEntry("added", id, cur[id]))
+		case !sameLegs(prev, cur[id]):
+			entries = append(entries, summarizeEntry("changed", id, cur[id]))
+		}
+	}
+	for _, id := range oldIDs {
+		if _, ok := cur[id]; !ok {
+			entries = append(entries, summarizeEntry("removed", id, old[id]))
+		}
+	}
+	slices.SortStableFunc(entries, func(a, b diffEntry) int { return strings.Compare(a.EntryID, b.EntryID) })
+	return entries
+}
+
+// groupLegs groups legs by entry, returning the entry ids in file order.
+func groupLegs(legs []model.Leg) (map[string][]model.Leg, []string) {
+	groups := make(map[string][]model.Leg)
+	var ids []string
+	for _, leg := range legs {
+		id := leg.EntryGroup()
+		if _, ok := groups[id]; !ok {
+			ids = append(ids, id)
+		}
+		groups[id] = append(groups[id], leg)
+	}
+	return groups, ids
+}
+
+func sameLegs(a, b []model.Leg) bool {
+	return slices.EqualFunc(a, b, func(x, y model.Leg) bool {
+		return slices.Equal(journal.MarshalLeg(x), journal.MarshalLeg(y))
+	})
+}
+
+func summarizeEntry(change, id string, legs []model.Leg) diffEntry {
+	e := diffEntry{
+		Change:         change,
+		EntryID:        id,
+		Date:           legs[0].Date.Format("2006-01-02"),
+		Description:    legs[0].Description,
+		DebitAccounts:  []int{},
+		CreditAccounts: []int{},
+		Status:         legs[0].Status,
+	}
+	total := decimal.Zero
+	for _, leg := range legs {
+		if leg.Debit.IsPositive() {
+			total = total.Add(leg.Debit)
+			e.DebitAccounts = append(e.DebitAccounts, leg.AccountID)
+		}
+		if leg.Credit.IsPositive() {
+			e.CreditAccounts = append(e.CreditAccounts, leg.AccountID)
+		}
+	}
+	e.Amount = total.StringFixed(2)
+	return e
+}
+
+func joinInts(ns []int) string {
+	s := make([]string, len(ns))
+	for i, n := range ns {
+		s[i] = strconv.Itoa(n)
+	}
+	return strings.Join(s, ",")
+}
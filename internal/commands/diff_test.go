@@ -0,0 +1,72 @@
+package commands_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const februaryCSV = `Details,Posting Date,Description,Amount,Type,Balance,Check or Slip #
+DEBIT,02/03/2025,GITHUB *PRO SUBSCRIPTION,-4.00,ACH_DEBIT,8729.86,
+DEBIT,02/05/2025,AWS *SERVICES,-131.20,ACH_DEBIT,8598.66,
+`
+
+// newDiffRepo returns a repo with two import commits: January's statement,
+// then February's.
+func newDiffRepo(t *testing.T) string {
+	t.Helper()
+	dir := newImportRepo(t)
+	out, err := runCleared(t, "import", "--repo", dir)
+	require.NoError(t, err, out)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "import", "chase_february.csv"), []byte(februaryCSV), 0o644))
+	out, err = runCleared(t, "import", "--repo", dir)
+	require.NoError(t, err, out)
+	return dir
+}
+
+func TestDiff_ListsNewEntries(t *testing.T) {
+	dir := newDiffRepo(t)
+
+	out, err := runCleared(t, "diff", "HEAD~1", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Regexp(t, `added\s+2025-02-0001\s+2025-02-03\s+4.00\s+5020/1010\s+auto-confirmed\s+GITHUB \*PRO SUBSCRIPTION`, out)
+	assert.Regexp(t, `added\s+2025-02-0002\s+2025-02-05\s+131.20\s+5020/1010`, out)
+	assert.NotContains(t, out, "2025-01-", "January is unchanged")
+	assert.Contains(t, out, "2 added, 0 changed, 0 removed")
+
+	out, err = runCleared(t, "diff", "HEAD~2", "HEAD~1", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "6 added, 0 changed, 0 removed", "the first import")
+	assert.Regexp(t, `added\s+2025-01-0004\s+2025-01-15\s+3500.00\s+1010/4010`, out)
+}
+
+func TestDiff_JSON(t *testing.T) {
+	dir := newDiffRepo(t)
+
+	out, err := runClearedStdout(t, "diff", "HEAD~2", "--repo", dir, "--json")
+	require.NoError(t, err, out)
+	var entries []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &entries))
+	require.Len(t, entries, 8, "both imports")
+	assert.Equal(t, map[string]any{
+		"change":          "added",
+		"entry_id":        "2025-02-0002",
+		"date":            "2025-02-05",
+		"description":     "AWS *SERVICES",
+		"amount":          "131.20",
+		"debit_accounts":  []any{5020.0},
+		"credit_accounts": []any{1010.0},
+		"status":          "auto-confirmed",
+	}, entries[7])
+
+	out, err = runClearedStdout(t, "diff", "HEAD", "--repo", dir, "--json")
+	require.NoError(t, err, out)
+	assert.JSONEq(t, `[]`, out)
+
+	_, err = runCleared(t, "diff", "no-such-rev", "--repo", dir)
+	require.Error(t, err)
+}
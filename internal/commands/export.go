@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cleared-dev/cleared/internal/accounts"
+	"github.com/cleared-dev/cleared/internal/export"
+	"github.com/cleared-dev/cleared/internal/journal"
+)
+
+func newExportCommand() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the journal for other accounting tools",
+	}
+	exportCmd.AddCommand(newExportQuickBooksCommand())
+	return exportCmd
+}
+
+func newExportQuickBooksCommand() *cobra.Command {
+	var year int
+	var output string
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "quickbooks",
+		Short: "Write a year's journal as a QuickBooks journal entry import CSV",
+		Long: `Write every leg booked in --year as a QuickBooks Online journal entry
+import. Accounts are named as in the chart of accounts, so create matching
+accounts in QuickBooks first. The file goes to exports/ (gitignored) unless
+--output is given; use --output - for stdout.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			if output == "" {
+				output = filepath.Join(absDir, "exports", fmt.Sprintf("quickbooks-%d.csv", year))
+			}
+			return runExportQuickBooks(os.Stdout, absDir, year, output)
+		},
+	}
+
+	cmd.Flags().IntVar(&year, "year", time.Now().Year(), "calendar year to export")
+	cmd.Flags().StringVar(&output, "output", "", "output file (default exports/quickbooks-<year>.csv)")
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+func runExportQuickBooks(w io.Writer, repoRoot string, year int, output string) error {
+	accts, err := accounts.Load(repoRoot)
+	if err != nil {
+		return err
+	}
+	legs, err := journal.NewService(repoRoot, accts).ReadRange(
+		time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC),
+	)
+	if err != nil {
+		return err
+	}
+
+	if output == "-" {
+		return export.ToQuickBooksCSV(legs, accts, w)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+		return fmt.Errorf("creating export dir: %w", err)
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("creating export file: %w", err)
+	}
+	defer f.Close()
+	if err := export.ToQuickBooksCSV(legs, accts, f); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing export file: %w", err)
+	}
+
+	fmt.Fprintf(w, "Exported %d legs to %s\n", len(legs), output)
+	return nil
+}
@@ -0,0 +1,34 @@
+package commands_test
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportQuickBooks(t *testing.T) {
+	dir := newJournalRepo(t)
+
+	out, err := runCleared(t, "export", "quickbooks", "--year", "2025", "--repo", dir)
+	require.NoError(t, err, out)
+	path := filepath.Join(dir, "exports", "quickbooks-2025.csv")
+	assert.Contains(t, out, "Exported 12 legs to "+path)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 13)
+	assert.Equal(t, "Journal No.", rows[0][0])
+	assert.Equal(t, "Software & SaaS", rows[1][2], "accounts are named from the chart")
+
+	out, err = runClearedStdout(t, "export", "quickbooks", "--year", "2024", "--output", "-", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Equal(t, 1, strings.Count(out, "\n"), "an empty year exports only the header")
+}
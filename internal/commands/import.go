@@ -0,0 +1,255 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+
+	"github.com/cleared-dev/cleared/internal/accounts"
+	"github.com/cleared-dev/cleared/internal/categorize"
+	"github.com/cleared-dev/cleared/internal/config"
+	"github.com/cleared-dev/cleared/internal/gitops"
+	"github.com/cleared-dev/cleared/internal/importer"
+	"github.com/cleared-dev/cleared/internal/journal"
+	"github.com/cleared-dev/cleared/internal/model"
+	"github.com/cleared-dev/cleared/internal/rules"
+)
+
+// Accounts booked when no rule categorizes a transaction with enough
+// confidence, matching the stock ingest agent.
+const (
+	defaultBankAccount    = 1010 // Business Checking
+	defaultExpenseAccount = 5030 // Office Supplies
+	defaultRevenueAccount = 4010 // Service Revenue
+)
+
+func newImportCommand() *cobra.Command {
+	var format string
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "import [file]",
+		Short: "Import bank files without an agent",
+		Long: `Parse bank files, categorize each transaction with the categorization
+rules and how the same vendor was booked before, book it to the journal, and
+commit with an "import:" prefix.
+
+With no file, every file in import/ is imported and moved to
+import/processed/. Transactions already in the journal are skipped.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			file := ""
+			if len(args) > 0 {
+				if file, err = filepath.Abs(args[0]); err != nil {
+					return fmt.Errorf("resolving path: %w", err)
+				}
+			}
+			return runImport(os.Stdout, absDir, file, format)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "bank format, e.g. chase (default auto-detect)")
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+// bankImporter books bank transactions directly through the journal service,
+// tallying what it booked.
+type bankImporter struct {
+	cfg       *config.Config
+	journal   *journal.Service
+	rules     []rules.Rule
+	history   []model.Leg // journal as of the start of the current file
+	threshold decimal.Decimal
+
+	imported, confirmed, review, duplicates int
+}
+
+func runImport(w io.Writer, repoRoot, file, format string) error {
+	cfg, err := config.Load(filepath.Join(repoRoot, "cleared.yaml"))
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	accts, err := accounts.Load(repoRoot)
+	if err != nil {
+		return fmt.Errorf("loading accounts: %w", err)
+	}
+	rls, err := rules.Load(repoRoot)
+	if err != nil {
+		return err
+	}
+
+	importDir := filepath.Join(repoRoot, "import")
+	var paths []string
+	if file != "" {
+		paths = []string{file}
+	} else {
+		files, err := importer.Scan(repoRoot)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			paths = append(paths, f.Path)
+		}
+	}
+	if len(paths) == 0 {
+		fmt.Fprintln(w, "No new files to import")
+		return nil
+	}
+
+	jrnl := journal.NewService(repoRoot, accts)
+	jrnl.SetCurrency(cfg.Business.Currency)
+	closed, err := gitops.ClosedMonths(repoRoot)
+	if err != nil {
+		return err
+	}
+	jrnl.SetClosedMonths(closed)
+	imp := &bankImporter{
+		cfg:       cfg,
+		journal:   jrnl,
+		rules:     rls,
+		threshold: decimal.NewFromFloat(cfg.Thresholds.AutoConfirm),
+	}
+	reg, err := importer.DefaultRegistry(cfg.ImportFormats...)
+	if err != nil {
+		return err
+	}
+	reg.SetRounding(importer.Rounding(cfg.Import.Rounding))
+
+	moved := 0
+	for _, path := range paths {
+		entryIDs, err := imp.importFile(reg, path, format)
+		if err != nil {
+			return err
+		}
+		// Only files staged in import/ are moved; an explicit path elsewhere
+		// is left where it is.
+		if filepath.Dir(path) == importDir {
+			if err := importer.MarkProcessed(repoRoot, filepath.Base(path)); err != nil {
+				return err
+			}
+			if err := importer.RecordManifest(repoRoot, filepath.Base(path), entryIDs); err != nil {
+				return err
+			}
+			moved++
+		}
+	}
+
+	if imp.imported > 0 || moved > 0 {
+		msg := fmt.Sprintf("import: %d transactions from %d files", imp.imported, len(paths))
+		if _, err := gitops.CommitAll(repoRoot, msg, cfg.Git.AuthorName, cfg.Git.AuthorEmail); err != nil {
+			return fmt.Errorf("committing: %w", err)
+		}
+	}
+
+	fmt.Fprintf(w, "Imported %d transactions: %d auto-confirmed, %d for review", imp.imported, imp.confirmed, imp.review)
+	if imp.duplicates > 0 {
+		fmt.Fprintf(w, " (%d already booked)", imp.duplicates)
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+// importFile books a file's new transactions and returns their entry IDs.
+func (imp *bankImporter) importFile(reg *importer.Registry, path, format string) ([]string, error) {
+	txns, err := reg.ParseFileFormat(path, format, imp.cfg.BankAccounts)
+	if err != nil {
+		return nil, err
+	}
+	fresh, err := importer.Deduplicate(txns, imp.journal)
+	if err != nil {
+		return nil, err
+	}
+	imp.duplicates += len(txns) - len(fresh)
+	if imp.history, err = readHistory(imp.journal); err != nil {
+		return nil, err
+	}
+
+	entryIDs := make([]string, 0, len(fresh))
+	for _, txn := range fresh {
+		entryID, err := imp.book(txn, filepath.Base(path))
+		if err != nil {
+			return nil, fmt.Errorf("booking %s %q: %w", txn.Date.Format("2006-01-02"), txn.Description, err)
+		}
+		entryIDs = append(entryIDs, entryID)
+	}
+	return entryIDs, nil
+}
+
+// readHistory reads every leg in the journal, oldest first.
+func readHistory(jrnl *journal.Service) ([]model.Leg, error) {
+	months, err := jrnl.Months()
+	if err != nil || len(months) == 0 {
+		return nil, err
+	}
+	return jrnl.ReadRange(months[0], months[len(months)-1].AddDate(0, 1, -1))
+}
+
+// book records one transaction. A suggestion at or above the auto-confirm
+// threshold picks the category account; anything else is booked for review
+// to the suggested account, or the default one when nothing matched. Returns
+// the new entry's ID.
+func (imp *bankImporter) book(txn model.BankTransaction, sourceFile string) (string, error) {
+	bank := txn.BankAccountID
+	if bank == 0 {
+		bank = defaultBankAccount
+	}
+
+	suggestion, err := categorize.Categorize(txn, imp.rules, imp.history)
+	if err != nil {
+		return "", err
+	}
+	params := journal.AddDoubleParams{
+		Date:        txn.Date,
+		Description: txn.Description,
+		Amount:      txn.Amount.Abs(),
+		Reference:   txn.Reference,
+		Notes:       txn.Notes,
+		Tags:        suggestion.Tags,
+		SourceFile:  sourceFile,
+	}
+
+	category := defaultExpenseAccount
+	if txn.Amount.IsPositive() {
+		category = defaultRevenueAccount
+	}
+	confidence := decimal.NewFromFloat(suggestion.Confidence)
+	if suggestion.AccountID != 0 && confidence.GreaterThanOrEqual(imp.threshold) {
+		category = suggestion.AccountID
+		params.Counterparty = suggestion.Counterparty
+		params.Confidence = confidence
+		params.Status = model.StatusAutoConfirmed
+		imp.confirmed++
+	} else {
+		if suggestion.AccountID != 0 {
+			category = suggestion.AccountID
+			params.Counterparty = suggestion.Counterparty
+			params.Confidence = confidence
+		}
+		params.Status = model.StatusPendingReview
+		imp.review++
+	}
+	params.Evidence = suggestion.Evidence
+
+	if txn.Amount.IsNegative() {
+		params.DebitAccount, params.CreditAccount = category, bank
+	} else {
+		params.DebitAccount, params.CreditAccount = bank, category
+	}
+
+	entryID, err := imp.journal.AddDouble(params)
+	if err != nil {
+		return "", err
+	}
+	imp.imported++
+	return entryID, nil
+}
@@ -0,0 +1,191 @@
+package commands_test
+
+import (
+	"encoding/csv"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cleared-dev/cleared/internal/importer"
+)
+
+// ingestRules mirrors the inline categorization in testdata/ingest.py.
+const ingestRules = `rules:
+  - vendor_pattern: GITHUB*
+    vendor_name: GitHub
+    account_id: 5020
+    confidence: 0.98
+  - vendor_pattern: AWS*
+    vendor_name: Amazon Web Services
+    account_id: 5020
+    confidence: 0.96
+  - vendor_pattern: DROPBOX*
+    vendor_name: Dropbox
+    account_id: 5020
+    confidence: 0.95
+`
+
+func newImportRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rules", "categorization-rules.yaml"), []byte(ingestRules), 0o644))
+
+	csvData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "chase_checking.csv"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "import", "chase_checking.csv"), csvData, 0o644))
+	return dir
+}
+
+// newJournalRepo initializes a project holding the 12-leg testdata journal
+// in 2025/01, uncommitted.
+func newJournalRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+	copyTestJournal(t, dir)
+	return dir
+}
+
+// copyTestJournal copies the testdata journal into dir as 2025/01.
+func copyTestJournal(t *testing.T, dir string) {
+	t.Helper()
+	journalData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "journal.csv"))
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "2025", "01"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2025", "01", "journal.csv"), journalData, 0o644))
+}
+
+func readJournalRows(t *testing.T, path string) [][]string {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	return rows[1:]
+}
+
+func TestImport_MatchesAgentPath(t *testing.T) {
+	dir := newImportRepo(t)
+
+	out, err := runCleared(t, "import", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Imported 6 transactions: 3 auto-confirmed, 3 for review")
+
+	// Same shape as TestAgentRun_Ingest: 6 transactions, 12 legs.
+	rows := readJournalRows(t, filepath.Join(dir, "2025", "01", "journal.csv"))
+	require.Len(t, rows, 12)
+
+	// entry_id, date, account_id, ..., debit, credit, counterparty, ..., status
+	type leg struct{ id, account, debit, credit, counterparty, status string }
+	got := make([]leg, len(rows))
+	for i, r := range rows {
+		got[i] = leg{r[0], r[2], r[4], r[5], r[6], r[9]}
+	}
+	assert.Equal(t, leg{"2025-01-0001a", "5020", "4.00", "", "GitHub", "auto-confirmed"}, got[0])
+	assert.Equal(t, leg{"2025-01-0001b", "1010", "", "4.00", "GitHub", "auto-confirmed"}, got[1])
+	assert.Equal(t, leg{"2025-01-0002a", "5020", "127.50", "", "Amazon Web Services", "auto-confirmed"}, got[2])
+	assert.Equal(t, leg{"2025-01-0004a", "1010", "3500.00", "", "", "pending-review"}, got[6], "income debits the bank")
+	assert.Equal(t, leg{"2025-01-0004b", "4010", "", "3500.00", "", "pending-review"}, got[7])
+	assert.Equal(t, leg{"2025-01-0005a", "5030", "42.99", "", "", "pending-review"}, got[8], "unmatched expense")
+
+	_, err = os.Stat(filepath.Join(dir, "import", "processed", "chase_checking.csv"))
+	require.NoError(t, err, "file moved to processed/")
+
+	log := exec.Command("git", "log", "-1", "--format=%s")
+	log.Dir = dir
+	subject, err := log.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "import: 6 transactions from 1 files", strings.TrimSpace(string(subject)))
+
+	status := exec.Command("git", "status", "--porcelain")
+	status.Dir = dir
+	dirty, err := status.Output()
+	require.NoError(t, err)
+	assert.Empty(t, string(dirty), "everything committed")
+
+	out, err = runCleared(t, "import", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "No new files to import")
+}
+
+func TestImport_RecordsManifest(t *testing.T) {
+	dir := newImportRepo(t)
+
+	out, err := runCleared(t, "import", "--repo", dir)
+	require.NoError(t, err, out)
+
+	entryIDs, err := importer.EntriesForFile(dir, "chase_checking.csv")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"2025-01-0001", "2025-01-0002", "2025-01-0003",
+		"2025-01-0004", "2025-01-0005", "2025-01-0006",
+	}, entryIDs)
+
+	file, ok, err := importer.SourceFile(dir, "2025-01-0005b")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "chase_checking.csv", file)
+
+	status := exec.Command("git", "status", "--porcelain")
+	status.Dir = dir
+	dirty, err := status.Output()
+	require.NoError(t, err)
+	assert.Empty(t, string(dirty), "manifest committed with the import")
+}
+
+func TestImport_RoundsAmounts(t *testing.T) {
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+	csvData := "Details,Posting Date,Description,Amount,Type,Balance,Check or Slip #\n" +
+		"CREDIT,01/31/2025,INTEREST PAYMENT,1.005,ACH_CREDIT,101.01,\n" +
+		"DEBIT,01/31/2025,SERVICE FEE,-2.50,ACH_DEBIT,98.51,\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "import", "chase.csv"), []byte(csvData), 0o644))
+
+	out, err := runCleared(t, "import", "--repo", dir)
+	require.NoError(t, err, out)
+
+	rows := readJournalRows(t, filepath.Join(dir, "2025", "01", "journal.csv"))
+	require.Len(t, rows, 4)
+	assert.Equal(t, "1.00", rows[0][4], "banker's rounding by default")
+	assert.Equal(t, "original amount 1.005", rows[0][13])
+	assert.Equal(t, "2.50", rows[2][4])
+	assert.Empty(t, rows[2][13])
+	assert.NotEmpty(t, rows[0][15], "created_at")
+	assert.Equal(t, "chase.csv", rows[0][16], "source_file")
+}
+
+func TestImport_ExplicitFileSkipsBooked(t *testing.T) {
+	dir := newImportRepo(t)
+	src := filepath.Join("..", "..", "testdata", "chase_checking.csv")
+
+	out, err := runCleared(t, "import", src, "--format", "chase", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Imported 6 transactions")
+	_, err = os.Stat(src)
+	require.NoError(t, err, "files outside import/ are left in place")
+
+	// The staged copy holds the same rows, so nothing new is booked.
+	out, err = runCleared(t, "import", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Imported 0 transactions")
+	assert.Contains(t, out, "(6 already booked)")
+	assert.Len(t, readJournalRows(t, filepath.Join(dir, "2025", "01", "journal.csv")), 12)
+}
+
+func TestImport_UnknownFormat(t *testing.T) {
+	dir := newImportRepo(t)
+
+	out, err := runCleared(t, "import", "--format", "wells", "--repo", dir)
+	require.Error(t, err)
+	assert.Contains(t, out, `unknown format "wells"`)
+}
@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -15,11 +17,19 @@ import (
 func newInitCommand() *cobra.Command {
 	var name string
 	var entityType string
+	var force, dryRun bool
 
 	cmd := &cobra.Command{
 		Use:   "init [directory]",
 		Short: "Initialize a new Cleared project",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Create the project layout, config, default chart of accounts, and an
+initial git commit.
+
+A directory that already has a cleared.yaml is refused. With --force the
+config is rewritten, but the existing chart of accounts, rules, and
+.gitignore are kept. --dry-run prints what would be created without
+writing anything or running git.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			dir := "."
 			if len(args) > 0 {
@@ -31,20 +41,68 @@ func newInitCommand() *cobra.Command {
 				return fmt.Errorf("resolving path: %w", err)
 			}
 
-			return runInit(absDir, name, entityType)
+			return runInit(os.Stdout, absDir, name, entityType, force, dryRun)
 		},
 	}
 
 	cmd.Flags().StringVar(&name, "name", "", "business name (required)")
 	_ = cmd.MarkFlagRequired("name")
 	cmd.Flags().StringVar(&entityType, "entity-type", "llc_single_member", "entity type")
+	cmd.Flags().BoolVar(&force, "force", false, "reinitialize an existing project, keeping its chart of accounts")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print what would be created without writing anything")
 
 	return cmd
 }
 
-func runInit(dir, name, entityType string) error {
-	// Create directory structure.
-	dirs := []string{
+func runInit(w io.Writer, dir, name, entityType string, force, dryRun bool) error {
+	plan, err := planInit(dir, name, entityType, force)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		plan.print(w)
+		return nil
+	}
+	return plan.apply(w)
+}
+
+// initPlan is everything cleared init will create, worked out before
+// anything is written so --dry-run can show it.
+type initPlan struct {
+	dir      string
+	name     string
+	cfg      *config.Config
+	existing bool     // reinitializing a project that has a cleared.yaml
+	dirs     []string // repo-relative directories that don't exist yet
+	files    []initFile
+	gitInit  bool
+}
+
+// initFile is one repo-relative file to write.
+type initFile struct {
+	path  string
+	write func(path string) error
+}
+
+func planInit(dir, name, entityType string, force bool) (*initPlan, error) {
+	// Reject a bad --entity-type before writing anything.
+	cfg := config.Default(name, entityType)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	plan := &initPlan{
+		dir:      dir,
+		name:     name,
+		cfg:      cfg,
+		existing: fileExists(filepath.Join(dir, "cleared.yaml")),
+		gitInit:  !gitops.IsRepo(dir),
+	}
+	if plan.existing && !force {
+		return nil, fmt.Errorf("%s is already a Cleared project; pass --force to reinitialize it", dir)
+	}
+
+	for _, d := range []string{
 		"accounts",
 		"rules",
 		"agents",
@@ -54,53 +112,97 @@ func runInit(dir, name, entityType string) error {
 		"logs",
 		"import",
 		filepath.Join("import", "processed"),
-	}
-	for _, d := range dirs {
-		if err := os.MkdirAll(filepath.Join(dir, d), 0o755); err != nil {
-			return fmt.Errorf("creating directory %s: %w", d, err)
+	} {
+		if !fileExists(filepath.Join(dir, d)) {
+			plan.dirs = append(plan.dirs, d)
 		}
 	}
 
-	// Write cleared.yaml.
-	cfg := config.Default(name, entityType)
-	if err := config.Save(filepath.Join(dir, "cleared.yaml"), cfg); err != nil {
-		return fmt.Errorf("writing config: %w", err)
+	plan.files = append(plan.files, initFile{"cleared.yaml", func(path string) error {
+		return config.Save(path, cfg)
+	}})
+	// A reinitialized project keeps its own chart, rules, and .gitignore,
+	// which may hold accounts and history the defaults don't.
+	plan.addIfMissing(filepath.Join("accounts", "chart-of-accounts.csv"), func(string) error {
+		return accounts.NewService(accounts.DefaultChart(entityType)).Save(dir)
+	})
+	plan.addIfMissing(filepath.Join("rules", "categorization-rules.yaml"), writeContent("rules: []\n"))
+	plan.addIfMissing(".gitignore", writeContent("receipts/\nexports/\nqueue/\n.cleared-cache/\n"))
+	plan.addIfMissing(filepath.Join("import", ".gitkeep"), writeContent(""))
+	return plan, nil
+}
+
+func (p *initPlan) addIfMissing(path string, write func(string) error) {
+	if !fileExists(filepath.Join(p.dir, path)) {
+		p.files = append(p.files, initFile{path, write})
 	}
+}
 
-	// Write chart of accounts.
-	chart := accounts.DefaultChart(entityType)
-	svc := accounts.NewService(chart)
-	if err := svc.Save(dir); err != nil {
-		return fmt.Errorf("writing chart of accounts: %w", err)
+func writeContent(content string) func(string) error {
+	return func(path string) error {
+		return os.WriteFile(path, []byte(content), 0o644)
 	}
+}
 
-	// Write empty categorization rules.
-	rulesContent := "rules: []\n"
-	if err := os.WriteFile(filepath.Join(dir, "rules", "categorization-rules.yaml"), []byte(rulesContent), 0o644); err != nil {
-		return fmt.Errorf("writing rules: %w", err)
+func (p *initPlan) commitMessage() string {
+	if p.existing {
+		return "init: Reinitialize " + p.name
 	}
+	return "init: Initialize " + p.name
+}
 
-	// Write .gitignore.
-	gitignore := "receipts/\nexports/\nqueue/\n.cleared-cache/\n"
-	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte(gitignore), 0o644); err != nil {
-		return fmt.Errorf("writing .gitignore: %w", err)
+// print lists the plan for --dry-run.
+func (p *initPlan) print(w io.Writer) {
+	fmt.Fprintf(w, "Would initialize Cleared project at %s\n", p.dir)
+	for _, d := range p.dirs {
+		fmt.Fprintf(w, "  create %s/\n", d)
 	}
+	for _, f := range p.files {
+		fmt.Fprintf(w, "  write  %s\n", f.path)
+	}
+	if p.gitInit {
+		fmt.Fprintln(w, "  git init")
+	}
+	fmt.Fprintf(w, "  git commit %q\n", p.commitMessage())
+}
 
-	// Write import/.gitkeep.
-	if err := os.WriteFile(filepath.Join(dir, "import", ".gitkeep"), []byte{}, 0o644); err != nil {
-		return fmt.Errorf("writing .gitkeep: %w", err)
+// apply creates the directories and files, then commits them.
+func (p *initPlan) apply(w io.Writer) error {
+	for _, d := range p.dirs {
+		if err := os.MkdirAll(filepath.Join(p.dir, d), 0o755); err != nil {
+			return fmt.Errorf("creating directory %s: %w", d, err)
+		}
+	}
+	for _, f := range p.files {
+		if err := f.write(filepath.Join(p.dir, f.path)); err != nil {
+			return fmt.Errorf("writing %s: %w", f.path, err)
+		}
 	}
 
 	// Initialize git and create initial commit.
-	if err := gitops.Init(dir); err != nil {
-		return fmt.Errorf("git init: %w", err)
+	if p.gitInit {
+		if err := gitops.Init(p.dir); err != nil {
+			return fmt.Errorf("git init: %w", err)
+		}
+	}
+	hash, err := gitops.CommitAll(p.dir, p.commitMessage(), p.cfg.Git.AuthorName, p.cfg.Git.AuthorEmail)
+	if errors.Is(err, gitops.ErrNothingToCommit) {
+		fmt.Fprintf(w, "Reinitialized Cleared project at %s (no changes)\n", p.dir)
+		return nil
 	}
-
-	hash, err := gitops.CommitAll(dir, "init: Initialize "+name, cfg.Git.AuthorName, cfg.Git.AuthorEmail)
 	if err != nil {
 		return fmt.Errorf("initial commit: %w", err)
 	}
 
-	fmt.Printf("Initialized Cleared project at %s (%s)\n", dir, hash)
+	verb := "Initialized"
+	if p.existing {
+		verb = "Reinitialized"
+	}
+	fmt.Fprintf(w, "%s Cleared project at %s (%s)\n", verb, p.dir, hash)
 	return nil
 }
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -39,6 +39,13 @@ func runCleared(t *testing.T, args ...string) (string, error) {
 	return string(out), err
 }
 
+// runClearedStdout is runCleared without stderr mixed in, for parsing --json.
+func runClearedStdout(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	out, err := exec.Command(binaryPath, args...).Output()
+	return string(out), err
+}
+
 func TestInit_CreatesStructure(t *testing.T) {
 	dir := t.TempDir()
 	_, err := runCleared(t, "init", dir, "--name", "Test Biz")
@@ -153,3 +160,107 @@ func TestInit_DefaultEntityType(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, accts, 11)
 }
+
+func TestInit_UnknownEntityType(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "biz")
+	out, err := runCleared(t, "init", dir, "--name", "Test Biz", "--entity-type", "c_corp")
+	require.Error(t, err)
+	assert.Contains(t, out, `business.entity_type "c_corp" is not recognized`)
+	assert.NoDirExists(t, dir, "nothing is written for a bad entity type")
+}
+
+func TestInit_RefusesExistingProject(t *testing.T) {
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Biz")
+	require.NoError(t, err)
+	before, err := os.ReadFile(filepath.Join(dir, "cleared.yaml"))
+	require.NoError(t, err)
+
+	out, err := runCleared(t, "init", dir, "--name", "Other Biz")
+	require.Error(t, err)
+	assert.Contains(t, out, "already a Cleared project")
+	assert.Contains(t, out, "--force")
+
+	after, err := os.ReadFile(filepath.Join(dir, "cleared.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after), "config is untouched")
+
+	log := exec.Command("git", "rev-list", "--count", "HEAD")
+	log.Dir = dir
+	count, err := log.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "1\n", string(count), "no second commit")
+}
+
+func TestInit_ForceKeepsChart(t *testing.T) {
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Biz")
+	require.NoError(t, err)
+	out, err := runCleared(t, "accounts", "add", "--id", "5090", "--name", "Travel", "--type", "expense", "--repo", dir)
+	require.NoError(t, err, out)
+
+	out, err = runCleared(t, "init", dir, "--name", "Renamed Biz", "--entity-type", "s_corp", "--force")
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Reinitialized")
+
+	data, err := os.ReadFile(filepath.Join(dir, "cleared.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Renamed Biz")
+
+	f, err := os.Open(filepath.Join(dir, "accounts", "chart-of-accounts.csv"))
+	require.NoError(t, err)
+	defer f.Close()
+	accts, err := accountsCSV.ReadAccounts(f)
+	require.NoError(t, err)
+	assert.Len(t, accts, 12, "existing chart kept, not replaced by the s_corp defaults")
+	assert.Equal(t, 5090, accts[len(accts)-1].ID)
+
+	log := exec.Command("git", "log", "--format=%s", "-1")
+	log.Dir = dir
+	subject, err := log.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "init: Reinitialize Renamed Biz\n", string(subject))
+}
+
+func TestInit_DryRun(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "biz")
+	out, err := runCleared(t, "init", dir, "--name", "Test Biz", "--dry-run")
+	require.NoError(t, err, out)
+
+	assert.NoDirExists(t, dir, "dry run writes nothing")
+	assert.Contains(t, out, "Would initialize Cleared project at "+dir)
+	for _, d := range []string{"accounts/", "rules/", "logs/", "import/", filepath.Join("import", "processed") + "/"} {
+		assert.Contains(t, out, "create "+d)
+	}
+	for _, f := range []string{
+		"cleared.yaml",
+		filepath.Join("accounts", "chart-of-accounts.csv"),
+		filepath.Join("rules", "categorization-rules.yaml"),
+		".gitignore",
+		filepath.Join("import", ".gitkeep"),
+	} {
+		assert.Contains(t, out, "write  "+f)
+	}
+	assert.Contains(t, out, "git init")
+	assert.Contains(t, out, `git commit "init: Initialize Test Biz"`)
+}
+
+func TestInit_DryRunForce(t *testing.T) {
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Biz")
+	require.NoError(t, err)
+
+	_, err = runCleared(t, "init", dir, "--name", "Test Biz", "--dry-run")
+	require.Error(t, err, "the existing-project check applies to dry runs too")
+
+	out, err := runCleared(t, "init", dir, "--name", "Renamed Biz", "--force", "--dry-run")
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "write  cleared.yaml")
+	assert.NotContains(t, out, "chart-of-accounts.csv", "the existing chart is kept")
+	assert.NotContains(t, out, "create ")
+	assert.NotContains(t, out, "git init")
+
+	data, err := os.ReadFile(filepath.Join(dir, "cleared.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Test Biz", "config untouched")
+}
@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+
+	"github.com/cleared-dev/cleared/internal/accounts"
+	"github.com/cleared-dev/cleared/internal/config"
+	"github.com/cleared-dev/cleared/internal/gitops"
+	"github.com/cleared-dev/cleared/internal/journal"
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+func newJournalCommand() *cobra.Command {
+	journalCmd := &cobra.Command{
+		Use:   "journal",
+		Short: "Journal operations",
+	}
+	journalCmd.AddCommand(newJournalAddCommand())
+	return journalCmd
+}
+
+func newJournalAddCommand() *cobra.Command {
+	var date, amount string
+	var params journal.AddDoubleParams
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Book a manual double-entry and commit it",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			if params.Date, err = time.Parse("2006-01-02", date); err != nil {
+				return fmt.Errorf("invalid --date: %w", err)
+			}
+			if params.Amount, err = decimal.NewFromString(amount); err != nil {
+				return fmt.Errorf("invalid --amount %q: %w", amount, err)
+			}
+			return runJournalAdd(absDir, params)
+		},
+	}
+
+	cmd.Flags().StringVar(&date, "date", "", "entry date, YYYY-MM-DD (required)")
+	cmd.Flags().IntVar(&params.DebitAccount, "debit-account", 0, "account to debit (required)")
+	cmd.Flags().IntVar(&params.CreditAccount, "credit-account", 0, "account to credit (required)")
+	cmd.Flags().StringVar(&amount, "amount", "", "positive amount, e.g. 125.00 (required)")
+	cmd.Flags().StringVar(&params.Description, "description", "", "entry description (required)")
+	cmd.Flags().StringVar(&params.Counterparty, "counterparty", "", "vendor or customer")
+	cmd.Flags().StringVar(&params.Reference, "reference", "", "external reference")
+	cmd.Flags().StringVar(&params.Notes, "notes", "", "free-form notes")
+	cmd.Flags().BoolVar(&params.Reopen, "reopen", false, "allow booking into a month closed with cleared close")
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+	for _, f := range []string{"date", "debit-account", "credit-account", "amount", "description"} {
+		_ = cmd.MarkFlagRequired(f)
+	}
+
+	return cmd
+}
+
+// runJournalAdd books a user-confirmed entry after checking the accounts and
+// amount, then commits it.
+func runJournalAdd(repoRoot string, params journal.AddDoubleParams) error {
+	cfg, err := config.Load(filepath.Join(repoRoot, "cleared.yaml"))
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	accts, err := accounts.Load(repoRoot)
+	if err != nil {
+		return fmt.Errorf("loading accounts: %w", err)
+	}
+
+	for _, id := range []int{params.DebitAccount, params.CreditAccount} {
+		if !accts.Exists(id) {
+			return fmt.Errorf("unknown account %d", id)
+		}
+	}
+	if params.DebitAccount == params.CreditAccount {
+		return errors.New("debit and credit accounts must differ")
+	}
+	if !params.Amount.IsPositive() {
+		return fmt.Errorf("amount must be positive, got %s", params.Amount)
+	}
+
+	params.Status = model.StatusUserConfirmed
+	params.Confidence = decimal.NewFromInt(1)
+	params.Evidence = "manual entry"
+
+	jrnl := journal.NewService(repoRoot, accts)
+	jrnl.SetCurrency(cfg.Business.Currency)
+	closed, err := gitops.ClosedMonths(repoRoot)
+	if err != nil {
+		return err
+	}
+	jrnl.SetClosedMonths(closed)
+	entryID, err := jrnl.AddDouble(params)
+	if errors.Is(err, journal.ErrMonthClosed) {
+		return fmt.Errorf("%w; pass --reopen to book into it anyway", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("correct: %s %s", entryID, params.Description)
+	if _, err := gitops.CommitAll(repoRoot, msg, cfg.Git.AuthorName, cfg.Git.AuthorEmail); err != nil {
+		return fmt.Errorf("committing: %w", err)
+	}
+
+	fmt.Printf("Booked %s: %s %s -> %d/%d\n", entryID, params.Description,
+		params.Amount.StringFixed(2), params.DebitAccount, params.CreditAccount)
+	for _, w := range jrnl.SignWarnings(entryID, params) {
+		fmt.Printf("Warning: %s\n", w)
+	}
+	return nil
+}
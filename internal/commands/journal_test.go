@@ -0,0 +1,75 @@
+package commands_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournalAdd(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	out, err := runCleared(t, "journal", "add", "--repo", dir,
+		"--date", "2025-03-31", "--debit-account", "5010", "--credit-account", "1010",
+		"--amount", "250.00", "--description", "Q1 accountant fee")
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Booked 2025-03-0001")
+
+	rows := readJournalRows(t, filepath.Join(dir, "2025", "03", "journal.csv"))
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{"2025-03-0001a", "2025-03-31", "5010", "Q1 accountant fee", "250.00", ""}, rows[0][:6])
+	assert.Equal(t, []string{"2025-03-0001b", "2025-03-31", "1010", "Q1 accountant fee", "", "250.00"}, rows[1][:6])
+	assert.Equal(t, "user-confirmed", rows[0][9])
+
+	out, err = runCleared(t, "verify", "--repo", dir)
+	require.NoError(t, err, "manual entry balances: %s", out)
+
+	log := exec.Command("git", "log", "-1", "--format=%s")
+	log.Dir = dir
+	subject, err := log.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "correct: 2025-03-0001 Q1 accountant fee", strings.TrimSpace(string(subject)))
+}
+
+func TestJournalAdd_SignWarning(t *testing.T) {
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	out, err := runCleared(t, "journal", "add", "--repo", dir,
+		"--date", "2025-03-31", "--debit-account", "1010", "--credit-account", "5020",
+		"--amount", "4.00", "--description", "GitHub refund")
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Booked 2025-03-0001")
+	assert.Contains(t, out, "Warning: check 10 [2025-03-0001b]: expense account 5020")
+	require.Len(t, readJournalRows(t, filepath.Join(dir, "2025", "03", "journal.csv")), 2, "booked anyway")
+}
+
+func TestJournalAdd_Rejects(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	add := func(debit, amount string) string {
+		t.Helper()
+		out, err := runCleared(t, "journal", "add", "--repo", dir,
+			"--date", "2025-03-31", "--debit-account", debit, "--credit-account", "1010",
+			"--amount", amount, "--description", "bad")
+		require.Error(t, err)
+		return out
+	}
+	assert.Contains(t, add("9999", "10.00"), "unknown account 9999")
+	assert.Contains(t, add("5010", "-10.00"), "amount must be positive")
+	assert.Contains(t, add("5010", "0"), "amount must be positive")
+	assert.Contains(t, add("5010", "ten"), "invalid --amount")
+
+	assert.NoFileExists(t, filepath.Join(dir, "2025", "03", "journal.csv"))
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cleared-dev/cleared/internal/agentlog"
+)
+
+func newLogCommand() *cobra.Command {
+	var agent string
+	var since string
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: "Show the agent log",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+
+			var sinceTime time.Time
+			if since != "" {
+				if sinceTime, err = time.Parse("2006-01-02", since); err != nil {
+					return fmt.Errorf("invalid --since date: %w", err)
+				}
+			}
+			return runLog(os.Stdout, absDir, agent, sinceTime, jsonOutput(cmd))
+		},
+	}
+
+	cmd.Flags().StringVar(&agent, "agent", "", "only show entries from this agent")
+	cmd.Flags().StringVar(&since, "since", "", "only show entries on or after this date, YYYY-MM-DD")
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+// logRow is one entry in the --json output of cleared log.
+type logRow struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Agent      string    `json:"agent"`
+	Action     string    `json:"action"`
+	Details    string    `json:"details"`
+	EntryID    string    `json:"entry_id,omitempty"`
+	CommitHash string    `json:"commit_hash,omitempty"`
+	DurationMS int64     `json:"duration_ms,omitempty"`
+}
+
+func runLog(w io.Writer, repoRoot, agent string, since time.Time, asJSON bool) error {
+	entries, err := agentlog.Query(repoRoot, agentlog.Filter{Agent: agent, Since: since})
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		rows := make([]logRow, len(entries))
+		for i, e := range entries {
+			rows[i] = logRow(e)
+		}
+		return writeJSON(w, rows)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Timestamp\tAgent\tAction\tEntry\tDetails")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			e.Timestamp.Format(time.RFC3339), e.Agent, e.Action, e.EntryID, e.Details)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,64 @@
+package commands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const seededLog = `timestamp,agent,action,details,entry_id,commit_hash
+2025-01-03T06:00:00Z,ingest,log,Parsed 6 transactions,,
+2025-01-03T06:00:01Z,ingest,journal_add_double,GitHub,2025-01-0001,
+2025-01-20T09:30:00Z,categorize,log,Recategorized AWS,2025-01-0002,
+2025-02-01T06:00:00Z,ingest,log,No new files to import,,
+`
+
+func newLogRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logs", "agent-log.csv"), []byte(seededLog), 0o644))
+	return dir
+}
+
+func TestLog(t *testing.T) {
+	dir := newLogRepo(t)
+
+	out, err := runCleared(t, "log", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Parsed 6 transactions")
+	assert.Contains(t, out, "Recategorized AWS")
+	assert.Contains(t, out, "No new files to import")
+}
+
+func TestLog_AgentFilter(t *testing.T) {
+	dir := newLogRepo(t)
+
+	out, err := runCleared(t, "log", "--repo", dir, "--agent", "categorize")
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Recategorized AWS")
+	assert.NotContains(t, out, "Parsed 6 transactions")
+	assert.NotContains(t, out, "No new files")
+}
+
+func TestLog_SinceFilter(t *testing.T) {
+	dir := newLogRepo(t)
+
+	out, err := runCleared(t, "log", "--repo", dir, "--since", "2025-01-20")
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Recategorized AWS", "same day counts")
+	assert.Contains(t, out, "No new files to import")
+	assert.NotContains(t, out, "Parsed 6 transactions")
+
+	out, err = runCleared(t, "log", "--repo", dir, "--since", "2025-01-20", "--agent", "ingest")
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "No new files to import")
+	assert.NotContains(t, out, "Recategorized AWS")
+
+	_, err = runCleared(t, "log", "--repo", dir, "--since", "Jan 20")
+	require.Error(t, err)
+}
@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// jsonFlag is the root persistent flag that switches commands to JSON output.
+const jsonFlag = "json"
+
+// jsonOutput reports whether --json was given.
+func jsonOutput(cmd *cobra.Command) bool {
+	on, _ := cmd.Flags().GetBool(jsonFlag)
+	return on
+}
+
+// writeJSON writes v as indented JSON followed by a newline.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
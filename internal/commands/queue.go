@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cleared-dev/cleared/internal/queue"
+)
+
+func newQueueCommand() *cobra.Command {
+	queueCmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Review queue operations",
+	}
+	queueCmd.AddCommand(newQueueListCommand())
+	return queueCmd
+}
+
+func newQueueListCommand() *cobra.Command {
+	var all bool
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List entries agents flagged for review",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			return runQueueList(os.Stdout, absDir, all, jsonOutput(cmd))
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "include resolved items")
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+func runQueueList(w io.Writer, repoRoot string, all, asJSON bool) error {
+	items, err := queue.Load(repoRoot)
+	if err != nil {
+		return err
+	}
+	shown := []queue.Item{}
+	for _, it := range items {
+		if all || it.Pending() {
+			shown = append(shown, it)
+		}
+	}
+
+	if asJSON {
+		return writeJSON(w, shown)
+	}
+	if len(shown) == 0 {
+		fmt.Fprintln(w, "Review queue is empty")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tEntry\tReason\tConfidence\tAgent\tCreated\tResolution")
+	for _, it := range shown {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%.2f\t%s\t%s\t%s\n", it.ID, it.EntryID, it.Reason,
+			it.Confidence, it.Agent, it.CreatedAt.Format("2006-01-02"), it.Resolution)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,42 @@
+package commands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const seededQueue = `[
+  {"id": "q001", "entry_id": "2025-01-0003", "description": "STAPLES #1234", "confidence": 0.4,
+   "agent": "ingest", "created": "2025-01-03T06:00:00Z"},
+  {"id": "q002", "entry_id": "2025-01-0004", "description": "SQ *COFFEE", "confidence": 0,
+   "agent": "ingest", "created": "2025-01-03T06:00:01Z",
+   "resolution": "confirmed", "resolved_at": "2025-01-04T09:00:00Z"}
+]
+`
+
+func TestQueueList(t *testing.T) {
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	out, err := runCleared(t, "queue", "list", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Review queue is empty")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "queue"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "queue", "pending.json"), []byte(seededQueue), 0o644))
+
+	out, err = runCleared(t, "queue", "list", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Regexp(t, `q001\s+2025-01-0003\s+STAPLES #1234\s+0.40\s+ingest`, out)
+	assert.NotContains(t, out, "SQ *COFFEE", "resolved items are hidden")
+
+	out, err = runCleared(t, "queue", "list", "--all", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "SQ *COFFEE")
+	assert.Contains(t, out, "confirmed")
+}
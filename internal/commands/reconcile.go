@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cleared-dev/cleared/internal/accounts"
+	"github.com/cleared-dev/cleared/internal/config"
+	"github.com/cleared-dev/cleared/internal/importer"
+	"github.com/cleared-dev/cleared/internal/journal"
+	"github.com/cleared-dev/cleared/internal/model"
+	"github.com/cleared-dev/cleared/internal/reconcile"
+)
+
+func newReconcileCommand() *cobra.Command {
+	var file, month, format string
+	var account int
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Reconcile a bank statement against the journal for a month",
+		Long: `Match every line of a bank statement to a booking on the bank account, and
+every booking to a statement line. Exits non-zero if anything is unmatched.
+
+The account is taken from the bank_accounts mapping in cleared.yaml, then
+--account, then Business Checking (1010).`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			m, err := time.Parse("2006-01", month)
+			if err != nil {
+				return fmt.Errorf("invalid --month: %w", err)
+			}
+			return runReconcile(os.Stdout, absDir, file, format, m, account)
+		},
+	}
+
+	cmd.Flags().StringVar(&file, "file", "", "bank statement file (required)")
+	cmd.Flags().StringVar(&month, "month", "", "month to reconcile, YYYY-MM (required)")
+	cmd.Flags().StringVar(&format, "format", "", "bank format, e.g. chase (default auto-detect)")
+	cmd.Flags().IntVar(&account, "account", 0, "bank account ID when cleared.yaml has no mapping for the file")
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+	_ = cmd.MarkFlagRequired("file")
+	_ = cmd.MarkFlagRequired("month")
+
+	return cmd
+}
+
+func runReconcile(w io.Writer, repoRoot, file, format string, month time.Time, account int) error {
+	cfg, err := config.Load(filepath.Join(repoRoot, "cleared.yaml"))
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	accts, err := accounts.Load(repoRoot)
+	if err != nil {
+		return fmt.Errorf("loading accounts: %w", err)
+	}
+
+	reg, err := importer.DefaultRegistry(cfg.ImportFormats...)
+	if err != nil {
+		return err
+	}
+	reg.SetRounding(importer.Rounding(cfg.Import.Rounding))
+	txns, err := reg.ParseFileFormat(file, format, cfg.BankAccounts)
+	if err != nil {
+		return err
+	}
+	var statement []model.BankTransaction
+	for _, txn := range txns {
+		if txn.Date.Year() == month.Year() && txn.Date.Month() == month.Month() {
+			statement = append(statement, txn)
+			account = cmp.Or(txn.BankAccountID, account)
+		}
+	}
+	account = cmp.Or(account, defaultBankAccount)
+	if !accts.Exists(account) {
+		return fmt.Errorf("unknown account %d", account)
+	}
+
+	legs, err := journal.NewService(repoRoot, accts).ReadByAccount(month.Year(), int(month.Month()), account)
+	if err != nil {
+		return err
+	}
+	result := reconcile.Reconcile(statement, legs)
+
+	name := ""
+	if a, ok := accts.Get(account); ok {
+		name = a.Name
+	}
+	fmt.Fprintf(w, "%d %s, %s: %d statement lines, %d matched\n",
+		account, name, month.Format("2006-01"), len(statement), len(result.Matched))
+	if len(result.UnmatchedStatement) > 0 {
+		fmt.Fprintln(w, "Statement lines with no booking:")
+		for _, txn := range result.UnmatchedStatement {
+			fmt.Fprintf(w, "  %s  %10s  %s\n", txn.Date.Format("2006-01-02"), txn.Amount.StringFixed(2), txn.Description)
+		}
+	}
+	if len(result.UnmatchedLegs) > 0 {
+		fmt.Fprintln(w, "Bookings not on the statement:")
+		for _, leg := range result.UnmatchedLegs {
+			fmt.Fprintf(w, "  %s  %s  %10s  %s\n", leg.EntryID, leg.Date.Format("2006-01-02"),
+				leg.Debit.Sub(leg.Credit).StringFixed(2), leg.Description)
+		}
+	}
+
+	if !result.Reconciled() {
+		return errors.New("statement does not reconcile")
+	}
+	fmt.Fprintln(w, "Reconciled")
+	return nil
+}
@@ -0,0 +1,44 @@
+package commands_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcile(t *testing.T) {
+	dir := newImportRepo(t)
+	out, err := runCleared(t, "import", "--repo", dir)
+	require.NoError(t, err, out)
+
+	src := filepath.Join("..", "..", "testdata", "chase_checking.csv")
+	out, err = runCleared(t, "reconcile", "--file", src, "--month", "2025-01", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "1010 Business Checking, 2025-01: 6 statement lines, 6 matched")
+	assert.Contains(t, out, "Reconciled")
+
+	csvData, err := os.ReadFile(src)
+	require.NoError(t, err)
+	lines := strings.SplitAfter(string(csvData), "\n")
+
+	// A statement line nobody booked, and one booking dropped from the statement.
+	extra := strings.Join(lines, "") + "DEBIT,01/28/2025,STAPLES 0042,-19.99,DEBIT_CARD,8713.87,\n"
+	stmt := filepath.Join(t.TempDir(), "statement.csv")
+	require.NoError(t, os.WriteFile(stmt, []byte(extra), 0o644))
+	out, err = runCleared(t, "reconcile", "--file", stmt, "--month", "2025-01", "--repo", dir)
+	require.Error(t, err)
+	assert.Contains(t, out, "Statement lines with no booking:")
+	assert.Contains(t, out, "STAPLES 0042")
+
+	missing := lines[0] + strings.Join(lines[2:], "")
+	require.NoError(t, os.WriteFile(stmt, []byte(missing), 0o644))
+	out, err = runCleared(t, "reconcile", "--file", stmt, "--month", "2025-01", "--repo", dir)
+	require.Error(t, err)
+	assert.Contains(t, out, "Bookings not on the statement:")
+	assert.Contains(t, out, "2025-01-0001b")
+	assert.Contains(t, out, "statement does not reconcile")
+}
@@ -0,0 +1,385 @@
+package commands
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+
+	"github.com/cleared-dev/cleared/internal/accounts"
+	"github.com/cleared-dev/cleared/internal/config"
+	"github.com/cleared-dev/cleared/internal/journal"
+	"github.com/cleared-dev/cleared/internal/report"
+)
+
+func newReportCommand() *cobra.Command {
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Financial reports",
+	}
+	reportCmd.AddCommand(newReportPnLCommand())
+	reportCmd.AddCommand(newReportLedgerCommand())
+	reportCmd.AddCommand(newReportBalanceSheetCommand())
+	return reportCmd
+}
+
+func newReportPnLCommand() *cobra.Command {
+	var from, to string
+	var year int
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "pnl",
+		Short: "Profit and loss (income statement) for a period",
+		Long: `Profit and loss for a fiscal year, or between --from and --to.
+
+The fiscal year follows fiscal.year_start in cleared.yaml and is named by
+the calendar year it ends in: with a 07-01 start, --year 2026 covers
+2025-07-01 to 2026-06-30. --from and --to override either end.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+
+			var fromDate, toDate time.Time
+			if from != "" {
+				if fromDate, err = time.Parse("2006-01-02", from); err != nil {
+					return fmt.Errorf("invalid --from date: %w", err)
+				}
+			}
+			if to != "" {
+				if toDate, err = time.Parse("2006-01-02", to); err != nil {
+					return fmt.Errorf("invalid --to date: %w", err)
+				}
+			}
+			return runPnL(os.Stdout, absDir, year, fromDate, toDate, jsonOutput(cmd))
+		},
+	}
+
+	cmd.Flags().IntVar(&year, "year", 0, "fiscal year (default the current one)")
+	cmd.Flags().StringVar(&from, "from", "", "start date, YYYY-MM-DD (default start of the fiscal year)")
+	cmd.Flags().StringVar(&to, "to", "", "end date, YYYY-MM-DD (default end of the fiscal year)")
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+// pnlResult is the --json shape of cleared report pnl. Amounts are strings
+// with two decimals so no precision is lost.
+type pnlResult struct {
+	From          string     `json:"from"`
+	To            string     `json:"to"`
+	Revenue       []pnlTotal `json:"revenue"`
+	Expenses      []pnlTotal `json:"expenses"`
+	TotalRevenue  string     `json:"total_revenue"`
+	TotalExpenses string     `json:"total_expenses"`
+	NetIncome     string     `json:"net_income"`
+}
+
+// pnlTotal is one account row. Amount is the account's own activity; Total
+// adds its sub-accounts', and Depth is its nesting under other rows.
+type pnlTotal struct {
+	AccountID int    `json:"account_id"`
+	Name      string `json:"name"`
+	Amount    string `json:"amount"`
+	Total     string `json:"total"`
+	Depth     int    `json:"depth"`
+}
+
+func pnlTotals(rows []report.AccountTotal) []pnlTotal {
+	totals := make([]pnlTotal, len(rows))
+	for i, a := range rows {
+		totals[i] = pnlTotal{
+			AccountID: a.AccountID,
+			Name:      a.Name,
+			Amount:    a.Amount.StringFixed(2),
+			Total:     a.Total.StringFixed(2),
+			Depth:     a.Depth,
+		}
+	}
+	return totals
+}
+
+// runPnL reports fiscal year year (0 for the current one), with a non-zero
+// from or to replacing that end of the year.
+func runPnL(w io.Writer, repoRoot string, year int, from, to time.Time, asJSON bool) error {
+	cfg, err := config.Load(filepath.Join(repoRoot, "cleared.yaml"))
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	accts, err := accounts.Load(repoRoot)
+	if err != nil {
+		return err
+	}
+	reports := report.NewService(journal.NewService(repoRoot, accts), accts)
+	reports.SetFiscalYear(cfg.FiscalYear())
+
+	if year == 0 {
+		year = cfg.FiscalYear().Of(time.Now())
+	}
+	start, end := reports.FiscalBounds(year)
+	from = cmp.Or(from, start)
+	to = cmp.Or(to, end)
+	if to.Before(from) {
+		return fmt.Errorf("--to %s is before --from %s", to.Format("2006-01-02"), from.Format("2006-01-02"))
+	}
+
+	pnl, err := reports.ProfitAndLoss(from, to)
+	if err != nil {
+		return fmt.Errorf("computing profit and loss: %w", err)
+	}
+
+	if asJSON {
+		return writeJSON(w, pnlResult{
+			From:          from.Format("2006-01-02"),
+			To:            to.Format("2006-01-02"),
+			Revenue:       pnlTotals(pnl.Revenue),
+			Expenses:      pnlTotals(pnl.Expenses),
+			TotalRevenue:  pnl.TotalRevenue.StringFixed(2),
+			TotalExpenses: pnl.TotalExpenses.StringFixed(2),
+			NetIncome:     pnl.NetIncome.StringFixed(2),
+		})
+	}
+
+	fmt.Fprintf(w, "Profit and loss, %s to %s\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	printSection(w, "Revenue", pnl.Revenue, pnl.TotalRevenue)
+	printSection(w, "Expenses", pnl.Expenses, pnl.TotalExpenses)
+	fmt.Fprintf(w, "\n%-36s%12s\n", "Net income", pnl.NetIncome.StringFixed(2))
+	return nil
+}
+
+func printSection(w io.Writer, title string, rows []report.AccountTotal, total decimal.Decimal) {
+	fmt.Fprintf(w, "\n%s\n", title)
+	for _, a := range rows {
+		// Sub-accounts are indented under their parent, whose row shows the
+		// subtree total.
+		name := strings.Repeat("  ", a.Depth) + a.Name
+		fmt.Fprintf(w, "  %-6d%-28s%12s\n", a.AccountID, name, a.Total.StringFixed(2))
+	}
+	fmt.Fprintf(w, "%-36s%12s\n", "Total "+strings.ToLower(title), total.StringFixed(2))
+}
+
+func newReportLedgerCommand() *cobra.Command {
+	var from, to string
+	var account int
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "ledger",
+		Short: "General ledger: each account's legs with a running balance",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+
+			fromDate, err := time.Parse("2006-01-02", from)
+			if err != nil {
+				return fmt.Errorf("invalid --from date: %w", err)
+			}
+			toDate, err := time.Parse("2006-01-02", to)
+			if err != nil {
+				return fmt.Errorf("invalid --to date: %w", err)
+			}
+			if toDate.Before(fromDate) {
+				return fmt.Errorf("--to %s is before --from %s", to, from)
+			}
+			return runLedger(os.Stdout, absDir, account, fromDate, toDate, jsonOutput(cmd))
+		},
+	}
+
+	year := time.Now().Year()
+	cmd.Flags().IntVar(&account, "account", 0, "only this account (default all accounts with activity)")
+	cmd.Flags().StringVar(&from, "from", fmt.Sprintf("%d-01-01", year), "start date, YYYY-MM-DD")
+	cmd.Flags().StringVar(&to, "to", fmt.Sprintf("%d-12-31", year), "end date, YYYY-MM-DD")
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+// ledgerAccount is one account in the --json output of cleared report
+// ledger.
+type ledgerAccount struct {
+	AccountID int          `json:"account_id"`
+	Name      string       `json:"name"`
+	Opening   string       `json:"opening"`
+	Lines     []ledgerLine `json:"lines"`
+}
+
+type ledgerLine struct {
+	Date        string `json:"date"`
+	EntryID     string `json:"entry_id"`
+	Description string `json:"description"`
+	Amount      string `json:"amount"`
+	Balance     string `json:"balance"`
+}
+
+func runLedger(w io.Writer, repoRoot string, account int, from, to time.Time, asJSON bool) error {
+	accts, err := accounts.Load(repoRoot)
+	if err != nil {
+		return err
+	}
+	if account != 0 && !accts.Exists(account) {
+		return fmt.Errorf("unknown account %d", account)
+	}
+
+	jrnl := journal.NewService(repoRoot, accts)
+	ledger, err := report.GeneralLedger(jrnl, accts, from, to)
+	if err != nil {
+		return fmt.Errorf("computing general ledger: %w", err)
+	}
+
+	ids := slices.Sorted(maps.Keys(ledger))
+	if account != 0 {
+		ids = []int{account}
+	}
+	result := make([]ledgerAccount, 0, len(ids))
+	for _, id := range ids {
+		acct, _ := accts.Get(id)
+		opening := report.Opening(ledger[id])
+		if len(ledger[id]) == 0 {
+			// No activity in the range: the opening balance is still shown.
+			balances, err := report.NewService(jrnl, accts).TrialBalance(from.AddDate(0, 0, -1))
+			if err != nil {
+				return fmt.Errorf("computing opening balance: %w", err)
+			}
+			opening = balances[id]
+		}
+		la := ledgerAccount{
+			AccountID: id,
+			Name:      acct.Name,
+			Opening:   opening.StringFixed(2),
+			Lines:     make([]ledgerLine, len(ledger[id])),
+		}
+		for i, l := range ledger[id] {
+			la.Lines[i] = ledgerLine{
+				Date:        l.Leg.Date.Format("2006-01-02"),
+				EntryID:     l.Leg.EntryID,
+				Description: l.Leg.Description,
+				Amount:      l.Amount.StringFixed(2),
+				Balance:     l.Balance.StringFixed(2),
+			}
+		}
+		result = append(result, la)
+	}
+	if asJSON {
+		return writeJSON(w, result)
+	}
+
+	fmt.Fprintf(w, "General ledger, %s to %s\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	for _, la := range result {
+		fmt.Fprintf(w, "\n%d %s\n", la.AccountID, la.Name)
+		fmt.Fprintf(w, "  %-10s  %-14s  %-30s%12s%12s\n", "", "", "Opening balance", "", la.Opening)
+		for _, l := range la.Lines {
+			fmt.Fprintf(w, "  %-10s  %-14s  %-30s%12s%12s\n", l.Date, l.EntryID, l.Description, l.Amount, l.Balance)
+		}
+	}
+	return nil
+}
+
+func newReportBalanceSheetCommand() *cobra.Command {
+	var asOf string
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "balance-sheet",
+		Short: "Assets, liabilities, and equity as of a date",
+		Long: `Show every asset, liability, and equity balance as of a date, with net
+income to date reported under equity. Exits non-zero if assets do not equal
+liabilities plus equity.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+
+			asOfDate, err := time.Parse("2006-01-02", asOf)
+			if err != nil {
+				return fmt.Errorf("invalid --as-of date: %w", err)
+			}
+			return runBalanceSheet(os.Stdout, absDir, asOfDate, jsonOutput(cmd))
+		},
+	}
+
+	cmd.Flags().StringVar(&asOf, "as-of", time.Now().Format("2006-01-02"), "balance date, YYYY-MM-DD")
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+// balanceSheetResult is the --json shape of cleared report balance-sheet.
+type balanceSheetResult struct {
+	AsOf             string     `json:"as_of"`
+	Assets           []pnlTotal `json:"assets"`
+	Liabilities      []pnlTotal `json:"liabilities"`
+	Equity           []pnlTotal `json:"equity"`
+	TotalAssets      string     `json:"total_assets"`
+	TotalLiabilities string     `json:"total_liabilities"`
+	NetIncome        string     `json:"net_income"`
+	TotalEquity      string     `json:"total_equity"`
+	Balanced         bool       `json:"balanced"`
+	Discrepancy      string     `json:"discrepancy"`
+}
+
+func runBalanceSheet(w io.Writer, repoRoot string, asOf time.Time, asJSON bool) error {
+	accts, err := accounts.Load(repoRoot)
+	if err != nil {
+		return err
+	}
+	reports := report.NewService(journal.NewService(repoRoot, accts), accts)
+
+	bs, err := reports.BalanceSheet(asOf)
+	if err != nil {
+		return fmt.Errorf("computing balance sheet: %w", err)
+	}
+
+	if asJSON {
+		if err := writeJSON(w, balanceSheetResult{
+			AsOf:             asOf.Format("2006-01-02"),
+			Assets:           pnlTotals(bs.Assets),
+			Liabilities:      pnlTotals(bs.Liabilities),
+			Equity:           pnlTotals(bs.Equity),
+			TotalAssets:      bs.TotalAssets.StringFixed(2),
+			TotalLiabilities: bs.TotalLiabilities.StringFixed(2),
+			NetIncome:        bs.NetIncome.StringFixed(2),
+			TotalEquity:      bs.TotalEquity.StringFixed(2),
+			Balanced:         bs.Balanced(),
+			Discrepancy:      bs.Discrepancy.StringFixed(2),
+		}); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(w, "Balance sheet as of %s\n", asOf.Format("2006-01-02"))
+		printSection(w, "Assets", bs.Assets, bs.TotalAssets)
+		printSection(w, "Liabilities", bs.Liabilities, bs.TotalLiabilities)
+		fmt.Fprintf(w, "\nEquity\n")
+		for _, a := range bs.Equity {
+			name := strings.Repeat("  ", a.Depth) + a.Name
+			fmt.Fprintf(w, "  %-6d%-28s%12s\n", a.AccountID, name, a.Total.StringFixed(2))
+		}
+		fmt.Fprintf(w, "  %-6s%-28s%12s\n", "", "Net income to date", bs.NetIncome.StringFixed(2))
+		fmt.Fprintf(w, "%-36s%12s\n", "Total equity", bs.TotalEquity.StringFixed(2))
+		fmt.Fprintf(w, "\n%-36s%12s\n", "Liabilities and equity", bs.TotalLiabilities.Add(bs.TotalEquity).StringFixed(2))
+		if !bs.Balanced() {
+			fmt.Fprintf(w, "%-36s%12s\n", "OUT OF BALANCE by", bs.Discrepancy.StringFixed(2))
+		}
+	}
+
+	if !bs.Balanced() {
+		return fmt.Errorf("balance sheet does not balance: assets differ from liabilities plus equity by %s",
+			bs.Discrepancy.StringFixed(2))
+	}
+	return nil
+}
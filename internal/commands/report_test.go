@@ -0,0 +1,88 @@
+package commands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportPnL(t *testing.T) {
+	dir := newJournalRepo(t)
+
+	out, err := runCleared(t, "report", "pnl", "--from", "2025-01-01", "--to", "2025-03-31", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Service Revenue")
+	assert.Contains(t, out, "3500.00")
+	assert.Contains(t, out, "198.24")
+	assert.Contains(t, out, "3301.76")
+}
+
+func TestReportPnL_BadRange(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	_, err = runCleared(t, "report", "pnl", "--from", "2025-03-01", "--to", "2025-01-01", "--repo", dir)
+	require.Error(t, err)
+}
+
+func TestReportLedger(t *testing.T) {
+	dir := newJournalRepo(t)
+
+	out, err := runCleared(t, "report", "ledger", "--account", "1010", "--from", "2025-01-01", "--to", "2025-01-31", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "1010 Business Checking")
+	assert.Regexp(t, `2025-01-15\s+2025-01-004a\s+Acme Consulting Invoice 1042\s+3500.00\s+3353.50`, out)
+	assert.NotContains(t, out, "Service Revenue", "only the requested account")
+
+	out, err = runCleared(t, "report", "ledger", "--account", "1020", "--from", "2025-01-01", "--to", "2025-01-31", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Regexp(t, `Opening balance\s+0.00`, out, "an account without activity still prints")
+
+	_, err = runCleared(t, "report", "ledger", "--account", "9999", "--repo", dir)
+	require.Error(t, err)
+}
+
+func TestReportBalanceSheet(t *testing.T) {
+	dir := newJournalRepo(t)
+	path := filepath.Join(dir, "2025", "01", "journal.csv")
+
+	out, err := runCleared(t, "report", "balance-sheet", "--as-of", "2025-01-31", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Regexp(t, `1010\s+Business Checking\s+3301.76`, out)
+	assert.Regexp(t, `Net income to date\s+3301.76`, out)
+	assert.Regexp(t, `Liabilities and equity\s+3301.76`, out)
+	assert.NotContains(t, out, "OUT OF BALANCE")
+
+	// A leg with no offsetting side is flagged, not hidden.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString("2025-01-099a,2025-01-20,1010,Stray deposit,25.00,,,,,user-confirmed,,,,\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	out, err = runCleared(t, "report", "balance-sheet", "--as-of", "2025-01-31", "--repo", dir)
+	require.Error(t, err)
+	assert.Regexp(t, `OUT OF BALANCE by\s+25.00`, out)
+}
+
+func TestReportPnL_FiscalYear(t *testing.T) {
+	dir := newJournalRepo(t)
+	_, err := runCleared(t, "config", "set", "fiscal.year_start", "07-01", "--repo", dir)
+	require.NoError(t, err)
+
+	// January 2025 falls in fiscal 2025, July 2024 to June 2025.
+	out, err := runCleared(t, "report", "pnl", "--year", "2025", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Profit and loss, 2024-07-01 to 2025-06-30")
+	assert.Regexp(t, `Net income\s+3301.76`, out)
+
+	out, err = runCleared(t, "report", "pnl", "--year", "2026", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "Profit and loss, 2025-07-01 to 2026-06-30")
+	assert.Regexp(t, `Net income\s+0.00`, out)
+}
@@ -19,9 +19,26 @@ func NewRootCommand() *cobra.Command {
 		},
 		SilenceUsage: true,
 	}
+	rootCmd.PersistentFlags().Bool(jsonFlag, false, "emit JSON instead of text (verify, balance, report, log, diff)")
 
 	rootCmd.AddCommand(newInitCommand())
 	rootCmd.AddCommand(newAgentCommand())
+	rootCmd.AddCommand(newAccountsCommand())
+	rootCmd.AddCommand(newImportCommand())
+	rootCmd.AddCommand(newJournalCommand())
+	rootCmd.AddCommand(newLogCommand())
+	rootCmd.AddCommand(newBalanceCommand())
+	rootCmd.AddCommand(newReportCommand())
+	rootCmd.AddCommand(newTaxCommand())
+	rootCmd.AddCommand(newVerifyCommand())
+	rootCmd.AddCommand(newReconcileCommand())
+	rootCmd.AddCommand(newConfigCommand())
+	rootCmd.AddCommand(newExportCommand())
+	rootCmd.AddCommand(newQueueCommand())
+	rootCmd.AddCommand(newServeCommand())
+	rootCmd.AddCommand(newUndoCommand())
+	rootCmd.AddCommand(newDiffCommand())
+	rootCmd.AddCommand(newCloseCommand())
 
 	return rootCmd
 }
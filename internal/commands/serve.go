@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cleared-dev/cleared/internal/sandbox"
+)
+
+// serveShutdownTimeout bounds how long cleared serve waits for requests in
+// flight when interrupted.
+const serveShutdownTimeout = 5 * time.Second
+
+func newServeCommand() *cobra.Command {
+	var addr string
+	var dryRun bool
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the agent primitives over HTTP JSON-RPC",
+		Long: `Expose the same primitives agent scripts call (journal_query, accounts_list,
+config_get, ...) as JSON-RPC 2.0 over HTTP. POST one request per call, e.g.
+
+  {"jsonrpc": "2.0", "method": "journal_query", "params": {"year": 2025, "month": 1}, "id": 1}
+
+params is an array of positional arguments or an object of keyword
+arguments. Requests must be sent with Content-Type application/json, and
+browsers may only call from the server's own origin. The server listens on
+127.0.0.1 unless --addr names another interface; it has no authentication,
+so only expose it on networks you trust. Writes are validated as they are for agents, and --dry-run keeps
+them in memory. Logged actions are appended to the agent log as "serve".`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+			return runServe(ctx, os.Stdout, absDir, addr, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:8080", "address to listen on")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "validate writes without making changes")
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+// runServe serves repoRoot's primitives on addr until ctx is done.
+func runServe(ctx context.Context, w io.Writer, repoRoot, addr string, dryRun bool) error {
+	rt, err := sandbox.NewRuntime(repoRoot, "serve", dryRun)
+	if err != nil {
+		return fmt.Errorf("creating runtime: %w", err)
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listening: %w", err)
+	}
+	srv := &http.Server{Handler: sandbox.NewServer(rt), ReadHeaderTimeout: 10 * time.Second}
+	fmt.Fprintf(w, "Serving primitives for %s on %s\n", repoRoot, ln.Addr())
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
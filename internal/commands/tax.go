@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+
+	"github.com/cleared-dev/cleared/internal/accounts"
+	"github.com/cleared-dev/cleared/internal/config"
+	"github.com/cleared-dev/cleared/internal/journal"
+	"github.com/cleared-dev/cleared/internal/report"
+)
+
+func newTaxCommand() *cobra.Command {
+	taxCmd := &cobra.Command{
+		Use:   "tax",
+		Short: "Tax reports",
+	}
+	taxCmd.AddCommand(newTaxScheduleCCommand())
+	return taxCmd
+}
+
+func newTaxScheduleCCommand() *cobra.Command {
+	var year int
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "schedule-c",
+		Short: "Summarize expenses by Schedule C line",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			return runScheduleC(os.Stdout, absDir, year)
+		},
+	}
+
+	cmd.Flags().IntVar(&year, "year", 0, "fiscal year, named by the calendar year it ends in (default the current one)")
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+func runScheduleC(w io.Writer, repoRoot string, year int) error {
+	cfg, err := config.Load(filepath.Join(repoRoot, "cleared.yaml"))
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	accts, err := accounts.Load(repoRoot)
+	if err != nil {
+		return err
+	}
+	reports := report.NewService(journal.NewService(repoRoot, accts), accts)
+	fy := cfg.FiscalYear()
+	reports.SetFiscalYear(fy)
+	if year == 0 {
+		year = fy.Of(time.Now())
+	}
+
+	lines, err := reports.ScheduleC(year)
+	if err != nil {
+		return fmt.Errorf("computing schedule C: %w", err)
+	}
+
+	if fy.IsCalendar() {
+		fmt.Fprintf(w, "Schedule C expenses, %d\n\n", year)
+	} else {
+		from, to := reports.FiscalBounds(year)
+		fmt.Fprintf(w, "Schedule C expenses, fiscal %d (%s to %s)\n\n", year, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	total := decimal.Zero
+	for _, l := range lines {
+		fmt.Fprintf(tw, "%s\t%12s\n", l.Line, l.Amount.StringFixed(2))
+		total = total.Add(l.Amount)
+	}
+	fmt.Fprintf(tw, "Total\t%12s\n", total.StringFixed(2))
+	return tw.Flush()
+}
@@ -0,0 +1,18 @@
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaxScheduleC(t *testing.T) {
+	dir := newJournalRepo(t)
+
+	out, err := runCleared(t, "tax", "schedule-c", "--year", "2025", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "schedule_c_18")
+	assert.Contains(t, out, "198.24")
+	assert.NotContains(t, out, "unmapped")
+}
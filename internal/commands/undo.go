@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cleared-dev/cleared/internal/config"
+	"github.com/cleared-dev/cleared/internal/gitops"
+)
+
+func newUndoCommand() *cobra.Command {
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Revert the last agent or import commit",
+		Long: `Undo creates a new commit reverting HEAD, so a bad agent run or import
+can be backed out while keeping it in the history. HEAD must be an import or
+agent commit, i.e. its message starts with "import:" or one of
+git.commit_prefixes other than "init:".`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			return runUndo(os.Stdout, absDir)
+		},
+	}
+
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+// runUndo reverts HEAD after checking that it is an agent or import commit.
+func runUndo(w io.Writer, repoRoot string) error {
+	cfg, err := config.Load(filepath.Join(repoRoot, "cleared.yaml"))
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	head, err := gitops.Head(repoRoot)
+	if err != nil {
+		return err
+	}
+
+	allowed := cfg.Git.CommitPrefixes
+	if len(allowed) == 0 {
+		allowed = gitops.DefaultCommitPrefixes
+	}
+	prefix := gitops.CommitPrefix(head.Message)
+	if prefix == "init" || (prefix != "import" && !slices.Contains(allowed, prefix)) {
+		return fmt.Errorf("HEAD %s %q is not an agent or import commit", gitops.ShortHash(head.Hash), head.Subject())
+	}
+	if err := checkRevertOpen(repoRoot, head); err != nil {
+		return err
+	}
+
+	hash, err := gitops.RevertLast(repoRoot)
+	if err != nil {
+		return fmt.Errorf("reverting: %w", err)
+	}
+	fmt.Fprintf(w, "Reverted %s %q as %s\n", gitops.ShortHash(head.Hash), head.Subject(), hash)
+	return nil
+}
+
+// checkRevertOpen refuses to revert head when it changed the journal of a
+// month closed with cleared close.
+func checkRevertOpen(repoRoot string, head gitops.CommitInfo) error {
+	closed, err := gitops.ClosedMonths(repoRoot)
+	if err != nil || len(closed) == 0 {
+		return err
+	}
+	files, err := gitops.DiffFiles(repoRoot, head.Hash+"^", head.Hash)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if ok, _ := path.Match(journalFilePattern, f.Path); !ok {
+			continue
+		}
+		month, err := time.Parse("2006/01", path.Dir(f.Path))
+		if err != nil {
+			continue
+		}
+		if slices.ContainsFunc(closed, month.Equal) {
+			return fmt.Errorf("HEAD %s changes %s, but %s is closed", gitops.ShortHash(head.Hash), f.Path, month.Format("2006-01"))
+		}
+	}
+	return nil
+}
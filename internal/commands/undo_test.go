@@ -0,0 +1,71 @@
+package commands_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUndo_RevertsImport(t *testing.T) {
+	dir := newImportRepo(t)
+	out, err := journalAdd(t, dir, "2025-01-02")
+	require.NoError(t, err, out)
+	journalPath := filepath.Join(dir, "2025", "01", "journal.csv")
+	before, err := os.ReadFile(journalPath)
+	require.NoError(t, err)
+
+	out, err = runCleared(t, "import", "--repo", dir, filepath.Join(dir, "import", "chase_checking.csv"))
+	require.NoError(t, err, out)
+	require.Len(t, readJournalRows(t, journalPath), 14)
+
+	out, err = runCleared(t, "undo", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, `"import: 6 transactions from 1 files"`)
+
+	after, err := os.ReadFile(journalPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after), "journal is back to its state before the import")
+
+	log := exec.Command("git", "log", "--format=%s")
+	log.Dir = dir
+	subjects, err := log.Output()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`Revert "import: 6 transactions from 1 files"`,
+		"import: 6 transactions from 1 files",
+	}, strings.Split(strings.TrimSpace(string(subjects)), "\n")[:2], "the import stays in history")
+
+	out, err = runCleared(t, "undo", "--repo", dir)
+	require.Error(t, err, "a revert is not an agent commit")
+	assert.Contains(t, out, "is not an agent or import commit")
+}
+
+func TestUndo_RefusesInit(t *testing.T) {
+	dir := t.TempDir()
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	out, err := runCleared(t, "undo", "--repo", dir)
+	require.Error(t, err)
+	assert.Contains(t, out, "is not an agent or import commit")
+	_, err = os.Stat(filepath.Join(dir, "cleared.yaml"))
+	require.NoError(t, err, "init is not reverted")
+}
+
+func TestUndo_RefusesClosedMonth(t *testing.T) {
+	dir := newImportRepo(t)
+	out, err := runCleared(t, "import", "--repo", dir)
+	require.NoError(t, err, out)
+	out, err = runCleared(t, "close", "--month", "2025-01", "--repo", dir)
+	require.NoError(t, err, out)
+
+	out, err = runCleared(t, "undo", "--repo", dir)
+	require.Error(t, err)
+	assert.Contains(t, out, "changes 2025/01/journal.csv, but 2025-01 is closed")
+	require.Len(t, readJournalRows(t, filepath.Join(dir, "2025", "01", "journal.csv")), 12, "nothing reverted")
+}
@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cleared-dev/cleared/internal/accounts"
+	"github.com/cleared-dev/cleared/internal/journal"
+)
+
+func newVerifyCommand() *cobra.Command {
+	var repoDir string
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check every journal month against the invariants",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absDir, err := filepath.Abs(repoDir)
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+			return runVerify(os.Stdout, absDir, jsonOutput(cmd))
+		},
+	}
+
+	cmd.Flags().StringVar(&repoDir, "repo", ".", "repository directory")
+
+	return cmd
+}
+
+// verifyResult is the --json shape of cleared verify.
+type verifyResult struct {
+	Passed bool          `json:"passed"`
+	Months []verifyMonth `json:"months"`
+}
+
+type verifyMonth struct {
+	Month      string            `json:"month"` // YYYY-MM
+	Legs       int               `json:"legs"`
+	Violations []verifyViolation `json:"violations"`
+}
+
+type verifyViolation struct {
+	Invariant   int    `json:"invariant"`
+	EntryID     string `json:"entry_id"`
+	Description string `json:"description"`
+}
+
+// runVerify validates each YYYY/MM/journal.csv and prints violations grouped
+// by month. It returns an error when any month fails.
+func runVerify(w io.Writer, repoRoot string, asJSON bool) error {
+	accts, err := accounts.Load(repoRoot)
+	if err != nil {
+		return err
+	}
+	jrnl := journal.NewService(repoRoot, accts)
+
+	months, err := jrnl.Months()
+	if err != nil {
+		return err
+	}
+
+	result := verifyResult{Passed: true, Months: make([]verifyMonth, 0, len(months))}
+	violations, failed := 0, 0
+	for _, m := range months {
+		year, month := m.Year(), int(m.Month())
+		legs, err := jrnl.ReadMonth(year, month)
+		if err != nil {
+			return err
+		}
+
+		vm := verifyMonth{Month: fmt.Sprintf("%04d-%02d", year, month), Legs: len(legs), Violations: []verifyViolation{}}
+		for _, ve := range journal.ValidateLegs(legs, accts, year, month) {
+			vm.Violations = append(vm.Violations, verifyViolation{Invariant: ve.Invariant, EntryID: ve.EntryID, Description: ve.Description})
+		}
+		if len(vm.Violations) > 0 {
+			violations += len(vm.Violations)
+			failed++
+			result.Passed = false
+		}
+		result.Months = append(result.Months, vm)
+	}
+
+	if asJSON {
+		if err := writeJSON(w, result); err != nil {
+			return err
+		}
+	} else {
+		printVerify(w, result)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("verification failed: %d violations in %d of %d months", violations, failed, len(months))
+	}
+	return nil
+}
+
+func printVerify(w io.Writer, result verifyResult) {
+	for _, m := range result.Months {
+		if len(m.Violations) == 0 {
+			fmt.Fprintf(w, "%s: ok (%d legs)\n", m.Month, m.Legs)
+			continue
+		}
+		fmt.Fprintf(w, "%s: %d violations\n", m.Month, len(m.Violations))
+		for _, v := range m.Violations {
+			fmt.Fprintf(w, "  invariant %d [%s]: %s\n", v.Invariant, v.EntryID, v.Description)
+		}
+	}
+	if result.Passed {
+		fmt.Fprintf(w, "All %d months pass\n", len(result.Months))
+	}
+}
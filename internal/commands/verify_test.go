@@ -0,0 +1,89 @@
+package commands_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := runCleared(t, "init", dir, "--name", "Test Corp")
+	require.NoError(t, err)
+
+	out, err := runCleared(t, "verify", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "All 0 months pass")
+
+	copyTestJournal(t, dir)
+
+	out, err = runCleared(t, "verify", "--repo", dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "2025-01: ok (12 legs)")
+}
+
+func TestVerify_UnbalancedMonth(t *testing.T) {
+	dir := newJournalRepo(t)
+	journalData, err := os.ReadFile(filepath.Join(dir, "2025", "01", "journal.csv"))
+	require.NoError(t, err)
+
+	// Knock the AWS entry's credit leg out of balance.
+	unbalanced := strings.Replace(string(journalData), ",,127.50,", ",,127.05,", 1)
+	require.NotEqual(t, string(journalData), unbalanced)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "2025", "02"), 0o755))
+	unbalanced = strings.ReplaceAll(unbalanced, "2025-01-", "2025-02-")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2025", "02", "journal.csv"), []byte(unbalanced), 0o644))
+
+	out, err := runCleared(t, "verify", "--repo", dir)
+	require.Error(t, err, "unbalanced month must exit non-zero")
+	assert.Contains(t, out, "2025-01: ok")
+	assert.Contains(t, out, "2025-02: 1 violations")
+	assert.Contains(t, out, "invariant 1 [2025-02-002]")
+	assert.Contains(t, out, "verification failed")
+}
+
+type verifyJSON struct {
+	Passed bool `json:"passed"`
+	Months []struct {
+		Month      string `json:"month"`
+		Legs       int    `json:"legs"`
+		Violations []struct {
+			Invariant int    `json:"invariant"`
+			EntryID   string `json:"entry_id"`
+		} `json:"violations"`
+	} `json:"months"`
+}
+
+func TestVerify_JSON(t *testing.T) {
+	dir := newJournalRepo(t)
+	journalData, err := os.ReadFile(filepath.Join(dir, "2025", "01", "journal.csv"))
+	require.NoError(t, err)
+
+	out, err := runClearedStdout(t, "verify", "--json", "--repo", dir)
+	require.NoError(t, err, out)
+
+	var result verifyJSON
+	require.NoError(t, json.Unmarshal([]byte(out), &result), out)
+	assert.True(t, result.Passed)
+	require.Len(t, result.Months, 1)
+	assert.Equal(t, "2025-01", result.Months[0].Month)
+	assert.Equal(t, 12, result.Months[0].Legs)
+	assert.Empty(t, result.Months[0].Violations)
+
+	unbalanced := strings.Replace(string(journalData), ",,127.50,", ",,127.05,", 1)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2025", "01", "journal.csv"), []byte(unbalanced), 0o644))
+
+	out, err = runClearedStdout(t, "verify", "--json", "--repo", dir)
+	require.Error(t, err, "failures still exit non-zero")
+	require.NoError(t, json.Unmarshal([]byte(out), &result), out)
+	assert.False(t, result.Passed)
+	require.Len(t, result.Months[0].Violations, 1)
+	assert.Equal(t, 1, result.Months[0].Violations[0].Invariant)
+	assert.Equal(t, "2025-01-002", result.Months[0].Violations[0].EntryID)
+}
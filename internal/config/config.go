@@ -1,55 +1,144 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"slices"
+	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/cleared-dev/cleared/internal/fiscal"
+	"github.com/cleared-dev/cleared/internal/gitops"
 )
 
 // Config represents the top-level cleared.yaml configuration.
 type Config struct {
-	Business     BusinessConfig   `yaml:"business"`
-	Fiscal       FiscalConfig     `yaml:"fiscal"`
-	BankAccounts []BankAccount    `yaml:"bank_accounts,omitempty"`
-	Thresholds   ThresholdsConfig `yaml:"thresholds"`
-	Git          GitConfig        `yaml:"git"`
+	Business      BusinessConfig   `yaml:"business"`
+	Fiscal        FiscalConfig     `yaml:"fiscal"`
+	BankAccounts  []BankAccount    `yaml:"bank_accounts,omitempty"`
+	ImportFormats []ImportFormat   `yaml:"import_formats,omitempty"`
+	Import        ImportConfig     `yaml:"import,omitempty"`
+	Thresholds    ThresholdsConfig `yaml:"thresholds"`
+	Git           GitConfig        `yaml:"git"`
+	Logs          LogsConfig       `yaml:"logs,omitempty"`
 }
 
+// EntityTypes lists the recognized business.entity_type values, each with
+// its own default chart of accounts.
+var EntityTypes = []string{"llc_single_member", "sole_proprietor", "s_corp", "partnership"}
+
 // BusinessConfig identifies the business entity.
 type BusinessConfig struct {
-	Name       string `yaml:"name"`
-	EntityType string `yaml:"entity_type"`
+	Name       string `yaml:"name" env:"CLEARED_BUSINESS_NAME"`
+	EntityType string `yaml:"entity_type" env:"CLEARED_BUSINESS_ENTITY_TYPE"`
+	// Currency is the ISO 4217 code new journal legs are booked in, e.g.
+	// "CAD". Empty leaves legs untagged.
+	Currency string `yaml:"currency,omitempty" env:"CLEARED_BUSINESS_CURRENCY"`
 }
 
 // FiscalConfig defines the fiscal year boundaries.
 type FiscalConfig struct {
-	YearStart string `yaml:"year_start"` // "MM-DD" format, e.g. "01-01"
+	YearStart string `yaml:"year_start" env:"CLEARED_FISCAL_YEAR_START"` // "MM-DD" format, e.g. "01-01"
 }
 
-// BankAccount maps a bank feed to a chart-of-accounts entry.
+// BankAccount maps a bank feed to a chart-of-accounts entry. Imported files
+// are matched by FilePattern first, then by Format.
 type BankAccount struct {
-	Name      string `yaml:"name"`
-	Type      string `yaml:"type"`
-	LastFour  string `yaml:"last_four"`
-	AccountID int    `yaml:"account_id"`
+	Name        string `yaml:"name"`
+	Type        string `yaml:"type"`
+	LastFour    string `yaml:"last_four"`
+	AccountID   int    `yaml:"account_id"`
+	FilePattern string `yaml:"file_pattern,omitempty"` // glob on the import file name, e.g. "chase-*.csv"
+	Format      string `yaml:"format,omitempty"`       // importer format name, e.g. "chase"
+}
+
+// ImportFormat describes a user-defined bank CSV layout. Columns are matched
+// by header name. Either AmountColumn or both DebitColumn and CreditColumn
+// must be set.
+type ImportFormat struct {
+	Name              string `yaml:"name"`
+	DateColumn        string `yaml:"date_column"`
+	DateLayout        string `yaml:"date_layout"` // Go time layout, e.g. "01/02/2006"
+	DescriptionColumn string `yaml:"description_column"`
+	AmountColumn      string `yaml:"amount_column,omitempty"`
+	DebitColumn       string `yaml:"debit_column,omitempty"`
+	CreditColumn      string `yaml:"credit_column,omitempty"`
+	ReferenceColumn   string `yaml:"reference_column,omitempty"`
+	TypeColumn        string `yaml:"type_column,omitempty"`
+}
+
+// RoundingModes lists the recognized import.rounding values.
+var RoundingModes = []string{"bankers", "half_up", "none"}
+
+// ImportConfig controls how bank files are read.
+type ImportConfig struct {
+	// Rounding is how amounts with more than two decimal places are
+	// rounded to cents: "bankers" (the default), "half_up", or "none".
+	Rounding string `yaml:"rounding,omitempty" env:"CLEARED_IMPORT_ROUNDING"`
 }
 
 // ThresholdsConfig controls agent auto-confirmation behavior.
 type ThresholdsConfig struct {
-	AutoConfirm float64 `yaml:"auto_confirm"`
-	ReviewFlag  float64 `yaml:"review_flag"`
+	AutoConfirm float64 `yaml:"auto_confirm" env:"CLEARED_THRESHOLDS_AUTO_CONFIRM"`
+	ReviewFlag  float64 `yaml:"review_flag" env:"CLEARED_THRESHOLDS_REVIEW_FLAG"`
+}
+
+// LogsConfig controls the agent log.
+type LogsConfig struct {
+	// AgentLogMaxBytes is the size past which logs/agent-log.csv is rotated.
+	// Zero uses agentlog.DefaultMaxSize.
+	AgentLogMaxBytes int64 `yaml:"agent_log_max_bytes,omitempty" env:"CLEARED_LOGS_AGENT_LOG_MAX_BYTES"`
 }
 
 // GitConfig controls git integration.
 type GitConfig struct {
-	AutoCommit  bool   `yaml:"auto_commit"`
-	AuthorName  string `yaml:"author_name"`
-	AuthorEmail string `yaml:"author_email"`
+	AutoCommit  bool   `yaml:"auto_commit" env:"CLEARED_GIT_AUTO_COMMIT"`
+	AuthorName  string `yaml:"author_name" env:"CLEARED_GIT_AUTHOR_NAME"`
+	AuthorEmail string `yaml:"author_email" env:"CLEARED_GIT_AUTHOR_EMAIL"`
+	// FullHashes records full 40-character commit hashes in the agent log
+	// instead of the short form.
+	FullHashes bool `yaml:"full_hashes" env:"CLEARED_GIT_FULL_HASHES"`
+	// CommitPrefixes are the prefixes an agent's commit messages may start
+	// with, as in "import: 6 transactions". Empty allows
+	// gitops.DefaultCommitPrefixes.
+	CommitPrefixes []string `yaml:"commit_prefixes,omitempty"`
 }
 
-// Load reads a cleared.yaml file from disk.
+// Load reads a cleared.yaml file from disk, then applies environment
+// overrides: each field tagged env:"CLEARED_..." takes the value of that
+// variable when it is set, e.g. CLEARED_GIT_AUTHOR_EMAIL. The result is
+// validated.
 func Load(path string) (*Config, error) {
+	cfg, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyEnv(reflect.ValueOf(cfg).Elem()); err != nil {
+		return nil, fmt.Errorf("applying environment overrides: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// LoadFile is Load without environment overrides, for callers that edit the
+// file and save it back.
+func LoadFile(path string) (*Config, error) {
+	cfg, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func read(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading config: %w", err)
@@ -61,6 +150,89 @@ func Load(path string) (*Config, error) {
 	return &cfg, nil
 }
 
+// Validate checks values that parse but would misbehave later: thresholds
+// must lie in [0,1] with auto_confirm at least review_flag, year_start must
+// be a real MM-DD date, currency must be a three-letter code, and
+// entity_type must be one of EntityTypes, import.rounding one of
+// RoundingModes, each git.commit_prefixes entry a lowercase word, and each
+// import_formats entry named, with no two names equal ignoring case. Empty
+// year_start, currency, entity_type and rounding are allowed. All problems
+// are reported together.
+func (c *Config) Validate() error {
+	var errs []error
+	for _, t := range []struct {
+		key   string
+		value float64
+	}{
+		{"thresholds.auto_confirm", c.Thresholds.AutoConfirm},
+		{"thresholds.review_flag", c.Thresholds.ReviewFlag},
+	} {
+		if t.value < 0 || t.value > 1 {
+			errs = append(errs, fmt.Errorf("%s must be between 0 and 1, got %g", t.key, t.value))
+		}
+	}
+	if c.Thresholds.AutoConfirm < c.Thresholds.ReviewFlag {
+		errs = append(errs, fmt.Errorf("thresholds.auto_confirm (%g) must be at least thresholds.review_flag (%g)",
+			c.Thresholds.AutoConfirm, c.Thresholds.ReviewFlag))
+	}
+	if _, err := fiscal.Parse(c.Fiscal.YearStart); err != nil {
+		errs = append(errs, fmt.Errorf("fiscal.year_start %w", err))
+	}
+	if c.Business.Currency != "" && !validCurrency(c.Business.Currency) {
+		errs = append(errs, fmt.Errorf("business.currency %q is not a three-letter ISO 4217 code", c.Business.Currency))
+	}
+	if c.Business.EntityType != "" && !slices.Contains(EntityTypes, c.Business.EntityType) {
+		errs = append(errs, fmt.Errorf("business.entity_type %q is not recognized (want %s)",
+			c.Business.EntityType, strings.Join(EntityTypes, ", ")))
+	}
+	if c.Import.Rounding != "" && !slices.Contains(RoundingModes, c.Import.Rounding) {
+		errs = append(errs, fmt.Errorf("import.rounding %q is not recognized (want %s)",
+			c.Import.Rounding, strings.Join(RoundingModes, ", ")))
+	}
+	formats := make(map[string]bool)
+	for i, f := range c.ImportFormats {
+		name := strings.ToLower(f.Name)
+		switch {
+		case name == "":
+			errs = append(errs, fmt.Errorf("import_formats[%d] has no name", i))
+		case formats[name]:
+			errs = append(errs, fmt.Errorf("import_formats name %q is used twice", f.Name))
+		}
+		formats[name] = true
+	}
+	for _, p := range c.Git.CommitPrefixes {
+		if !gitops.ValidPrefix(p) {
+			errs = append(errs, fmt.Errorf("git.commit_prefixes entry %q must be a lowercase word", p))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FiscalYear returns the fiscal year described by fiscal.year_start. Load
+// has already validated it; an invalid value falls back to the calendar
+// year.
+func (c *Config) FiscalYear() fiscal.Year {
+	y, err := fiscal.Parse(c.Fiscal.YearStart)
+	if err != nil {
+		return fiscal.Calendar
+	}
+	return y
+}
+
+// validCurrency reports whether code looks like an ISO 4217 code: three
+// uppercase ASCII letters.
+func validCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
 // Save writes a Config to a YAML file.
 func Save(path string, cfg *Config) error {
 	data, err := yaml.Marshal(cfg)
@@ -79,6 +251,7 @@ func Default(businessName, entityType string) *Config {
 		Business: BusinessConfig{
 			Name:       businessName,
 			EntityType: entityType,
+			Currency:   "USD",
 		},
 		Fiscal: FiscalConfig{
 			YearStart: "01-01",
@@ -35,11 +35,40 @@ func TestRoundTrip(t *testing.T) {
 	assert.Equal(t, 1010, got.BankAccounts[0].AccountID)
 }
 
+func TestImportFormatsRoundTrip(t *testing.T) {
+	cfg := Default("Test Biz", "llc_single_member")
+	cfg.ImportFormats = []ImportFormat{
+		{
+			Name:              "credit_union",
+			DateColumn:        "Posted",
+			DateLayout:        "01/02/2006",
+			DescriptionColumn: "Memo",
+			DebitColumn:       "Debit",
+			CreditColumn:      "Credit",
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "cleared.yaml")
+	require.NoError(t, Save(path, cfg))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "import_formats:")
+	assert.Contains(t, string(data), "debit_column: Debit")
+	assert.NotContains(t, string(data), "amount_column")
+
+	got, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, got.ImportFormats, 1)
+	assert.Equal(t, cfg.ImportFormats[0], got.ImportFormats[0])
+}
+
 func TestDefaults(t *testing.T) {
 	cfg := Default("My Company", "llc_single_member")
 
 	assert.Equal(t, "My Company", cfg.Business.Name)
 	assert.Equal(t, "llc_single_member", cfg.Business.EntityType)
+	assert.Equal(t, "USD", cfg.Business.Currency)
 	assert.Equal(t, "01-01", cfg.Fiscal.YearStart)
 	assert.InDelta(t, 0.95, cfg.Thresholds.AutoConfirm, 0.001)
 	assert.InDelta(t, 0.70, cfg.Thresholds.ReviewFlag, 0.001)
@@ -70,3 +99,94 @@ func TestYAMLFormat(t *testing.T) {
 	assert.Contains(t, contents, "year_start: 01-01")
 	assert.Contains(t, contents, "auto_commit: true")
 }
+
+func TestValidate(t *testing.T) {
+	for _, et := range EntityTypes {
+		assert.NoError(t, Default("Test Biz", et).Validate(), et)
+	}
+	assert.NoError(t, (&Config{}).Validate(), "an empty config means the defaults")
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   string
+	}{
+		{"auto_confirm above 1", func(c *Config) { c.Thresholds.AutoConfirm = 1.5 }, "thresholds.auto_confirm must be between 0 and 1, got 1.5"},
+		{"negative review_flag", func(c *Config) { c.Thresholds.ReviewFlag = -0.1 }, "thresholds.review_flag must be between 0 and 1, got -0.1"},
+		{"auto_confirm below review_flag", func(c *Config) { c.Thresholds.AutoConfirm = 0.5 }, "thresholds.auto_confirm (0.5) must be at least thresholds.review_flag (0.7)"},
+		{"impossible year_start", func(c *Config) { c.Fiscal.YearStart = "13-40" }, `fiscal.year_start "13-40" is not a valid MM-DD date`},
+		{"unpadded year_start", func(c *Config) { c.Fiscal.YearStart = "1-1" }, `fiscal.year_start "1-1" is not a valid MM-DD date`},
+		{"unknown entity_type", func(c *Config) { c.Business.EntityType = "c_corp" }, `business.entity_type "c_corp" is not recognized`},
+		{"unknown rounding", func(c *Config) { c.Import.Rounding = "up" }, `import.rounding "up" is not recognized`},
+		{"bad commit prefix", func(c *Config) { c.Git.CommitPrefixes = []string{"import", "Month End"} }, `git.commit_prefixes entry "Month End" must be a lowercase word`},
+		{"unnamed import format", func(c *Config) { c.ImportFormats = []ImportFormat{{DateColumn: "Date"}} }, "import_formats[0] has no name"},
+		{"duplicate import format", func(c *Config) {
+			c.ImportFormats = []ImportFormat{{Name: "credit_union"}, {Name: "Credit_Union"}}
+		}, `import_formats name "Credit_Union" is used twice`},
+		{"lowercase currency", func(c *Config) { c.Business.Currency = "cad" }, `business.currency "cad" is not a three-letter ISO 4217 code`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default("Test Biz", "llc_single_member")
+			tt.modify(cfg)
+			err := cfg.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cleared.yaml")
+	cfg := Default("Test Biz", "llc_single_member")
+	cfg.Thresholds.AutoConfirm = 1.5
+	cfg.Fiscal.YearStart = "13-40"
+	require.NoError(t, Save(path, cfg))
+
+	_, err := Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid config")
+	assert.Contains(t, err.Error(), "thresholds.auto_confirm must be between 0 and 1")
+	assert.Contains(t, err.Error(), "fiscal.year_start")
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cleared.yaml")
+	require.NoError(t, Save(path, Default("Test Biz", "llc_single_member")))
+
+	t.Setenv("CLEARED_GIT_AUTHOR_EMAIL", "ci@example.com")
+	t.Setenv("CLEARED_GIT_AUTO_COMMIT", "false")
+	t.Setenv("CLEARED_THRESHOLDS_AUTO_CONFIRM", "0.99")
+	t.Setenv("CLEARED_LOGS_AGENT_LOG_MAX_BYTES", "2048")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "ci@example.com", cfg.Git.AuthorEmail)
+	assert.False(t, cfg.Git.AutoCommit)
+	assert.InDelta(t, 0.99, cfg.Thresholds.AutoConfirm, 0.001)
+	assert.Equal(t, int64(2048), cfg.Logs.AgentLogMaxBytes)
+
+	// Unset variables leave file values alone.
+	assert.Equal(t, "Cleared Agent", cfg.Git.AuthorName)
+	assert.Equal(t, "Test Biz", cfg.Business.Name)
+	assert.InDelta(t, 0.70, cfg.Thresholds.ReviewFlag, 0.001)
+
+	fileOnly, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "agent@cleared.dev", fileOnly.Git.AuthorEmail, "LoadFile ignores the environment")
+}
+
+func TestLoad_EnvOverrideInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cleared.yaml")
+	require.NoError(t, Save(path, Default("Test Biz", "llc_single_member")))
+
+	t.Setenv("CLEARED_THRESHOLDS_REVIEW_FLAG", "high")
+	_, err := Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `CLEARED_THRESHOLDS_REVIEW_FLAG: "high" is not a number`)
+
+	t.Setenv("CLEARED_THRESHOLDS_REVIEW_FLAG", "1.5")
+	_, err = Load(path)
+	require.Error(t, err, "overridden values are validated too")
+	assert.Contains(t, err.Error(), "thresholds.review_flag must be between 0 and 1")
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Get resolves a dotted path such as "thresholds.auto_confirm" or
+// "bank_accounts.0.account_id" against the config's yaml field names. Slices
+// are indexed by number. Structs and slices come back as plain maps and
+// lists. Unknown paths return nil.
+func (c *Config) Get(path string) any {
+	v, ok := lookup(reflect.ValueOf(c).Elem(), path)
+	if !ok {
+		return nil
+	}
+	return plainValue(v)
+}
+
+// Set parses value into the scalar field at path, using the same dotted
+// paths as Get. Strings are stored as given; bools, integers and floats
+// must parse as their type. Set does not validate the result; call Validate
+// before saving.
+func (c *Config) Set(path, value string) error {
+	v, ok := lookup(reflect.ValueOf(c).Elem(), path)
+	if !ok {
+		return fmt.Errorf("unknown config key %q", path)
+	}
+	if k := v.Kind(); k == reflect.Struct || k == reflect.Slice {
+		return fmt.Errorf("%s is a section, not a single value", path)
+	}
+	if err := setScalar(v, value); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// applyEnv overrides fields tagged env:"NAME" with the value of each set
+// environment variable, recursing into nested sections. Lists are not
+// overridable.
+func applyEnv(v reflect.Value) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := v.Field(i)
+		if f.Kind() == reflect.Struct {
+			if err := applyEnv(f); err != nil {
+				return err
+			}
+			continue
+		}
+		name := t.Field(i).Tag.Get("env")
+		if name == "" {
+			continue
+		}
+		if value, ok := os.LookupEnv(name); ok {
+			if err := setScalar(f, value); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// setScalar parses value into v according to v's kind.
+func setScalar(v reflect.Value, value string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("cannot set a %s value", v.Kind())
+	}
+	return nil
+}
+
+// lookup walks path from v, returning the addressed value.
+func lookup(v reflect.Value, path string) (reflect.Value, bool) {
+	if path == "" {
+		return reflect.Value{}, false
+	}
+	for _, seg := range strings.Split(path, ".") {
+		switch v.Kind() {
+		case reflect.Struct:
+			f, ok := yamlField(v, seg)
+			if !ok {
+				return reflect.Value{}, false
+			}
+			v = f
+		case reflect.Slice:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= v.Len() {
+				return reflect.Value{}, false
+			}
+			v = v.Index(i)
+		default:
+			return reflect.Value{}, false
+		}
+	}
+	return v, true
+}
+
+// yamlField returns the field of struct v whose yaml tag name is name.
+func yamlField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := range t.NumField() {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if tag == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// plainValue converts a config value into maps, lists, and scalars, keying
+// struct fields by their yaml names.
+func plainValue(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Struct:
+		m := make(map[string]any, v.NumField())
+		t := v.Type()
+		for i := range t.NumField() {
+			if tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ","); tag != "" && tag != "-" {
+				m[tag] = plainValue(v.Field(i))
+			}
+		}
+		return m
+	case reflect.Slice:
+		list := make([]any, v.Len())
+		for i := range list {
+			list[i] = plainValue(v.Index(i))
+		}
+		return list
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	cfg := &Config{
+		Business: BusinessConfig{
+			Name:       "Test Corp",
+			EntityType: "llc_single_member",
+		},
+		Fiscal: FiscalConfig{
+			YearStart: "01-01",
+		},
+		Thresholds: ThresholdsConfig{
+			AutoConfirm: 0.95,
+			ReviewFlag:  0.70,
+		},
+		Git: GitConfig{
+			AutoCommit:  true,
+			AuthorName:  "Cleared Agent",
+			AuthorEmail: "agent@cleared.dev",
+		},
+		BankAccounts: []BankAccount{
+			{Name: "Chase Checking", Type: "checking", LastFour: "1234", AccountID: 1010, Format: "chase"},
+			{Name: "Amex", Type: "credit_card", AccountID: 2010},
+		},
+	}
+
+	tests := []struct {
+		path     string
+		expected any
+	}{
+		{"business.name", "Test Corp"},
+		{"business.entity_type", "llc_single_member"},
+		{"fiscal.year_start", "01-01"},
+		{"thresholds.auto_confirm", 0.95},
+		{"thresholds.review_flag", 0.70},
+		{"git.auto_commit", true},
+		{"git.author_name", "Cleared Agent"},
+		{"git.author_email", "agent@cleared.dev"},
+		{"bank_accounts.0.account_id", 1010},
+		{"bank_accounts.0.format", "chase"},
+		{"bank_accounts.1.name", "Amex"},
+		{"bank_accounts.1.file_pattern", ""},
+		{"fiscal", map[string]any{"year_start": "01-01"}},
+		{"import_formats", []any{}},
+		{"nonexistent.path", nil},
+		{"business.name.extra", nil},
+		{"bank_accounts.2.name", nil},
+		{"bank_accounts.x", nil},
+		{"bank_accounts.-1", nil},
+		{"", nil},
+	}
+	for _, tc := range tests {
+		result := cfg.Get(tc.path)
+		assert.Equal(t, tc.expected, result, "path: %s", tc.path)
+	}
+
+	accts, ok := cfg.Get("bank_accounts").([]any)
+	require.True(t, ok)
+	require.Len(t, accts, 2)
+	assert.Equal(t, "1234", accts[0].(map[string]any)["last_four"])
+}
+
+func TestSet(t *testing.T) {
+	cfg := Default("Test Biz", "llc_single_member")
+	cfg.BankAccounts = []BankAccount{{Name: "Chase Checking", AccountID: 1010}}
+
+	require.NoError(t, cfg.Set("thresholds.auto_confirm", "0.9"))
+	require.NoError(t, cfg.Set("business.name", "Renamed LLC"))
+	require.NoError(t, cfg.Set("git.full_hashes", "true"))
+	require.NoError(t, cfg.Set("logs.agent_log_max_bytes", "1048576"))
+	require.NoError(t, cfg.Set("bank_accounts.0.account_id", "1020"))
+
+	assert.InDelta(t, 0.9, cfg.Thresholds.AutoConfirm, 0.001)
+	assert.Equal(t, "Renamed LLC", cfg.Business.Name)
+	assert.True(t, cfg.Git.FullHashes)
+	assert.Equal(t, int64(1048576), cfg.Logs.AgentLogMaxBytes)
+	assert.Equal(t, 1020, cfg.BankAccounts[0].AccountID)
+
+	tests := []struct {
+		path, value, want string
+	}{
+		{"thresholds.nope", "1", `unknown config key "thresholds.nope"`},
+		{"bank_accounts.3.name", "x", `unknown config key "bank_accounts.3.name"`},
+		{"thresholds.auto_confirm", "high", `thresholds.auto_confirm: "high" is not a number`},
+		{"git.auto_commit", "maybe", `git.auto_commit: "maybe" is not a boolean`},
+		{"bank_accounts.0.account_id", "1.5", `bank_accounts.0.account_id: "1.5" is not an integer`},
+		{"thresholds", "1", "thresholds is a section, not a single value"},
+	}
+	for _, tt := range tests {
+		assert.EqualError(t, cfg.Set(tt.path, tt.value), tt.want)
+	}
+}
@@ -0,0 +1,65 @@
+// Package export converts the journal into formats other accounting tools
+// can import.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// AccountNamer looks up an account in the chart of accounts.
+type AccountNamer interface {
+	Get(id int) (model.Account, bool)
+}
+
+// QuickBooksHeader is the column layout of the QuickBooks Online journal
+// entry import.
+var QuickBooksHeader = []string{"Journal No.", "Journal Date", "Account", "Debits", "Credits", "Description", "Name", "Memo"}
+
+// quickBooksDate is the MM/DD/YYYY form QuickBooks expects.
+const quickBooksDate = "01/02/2006"
+
+// ToQuickBooksCSV writes legs as a QuickBooks journal entry import: one row
+// per leg, grouped into journal entries by entry ID. Accounts are named from
+// the chart so they match the accounts set up in QuickBooks; an account
+// missing from the chart is an error. Amounts have two decimals and the
+// unused side is left blank.
+func ToQuickBooksCSV(legs []model.Leg, chart AccountNamer, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(QuickBooksHeader); err != nil {
+		return fmt.Errorf("writing header: %w", err)
+	}
+
+	for _, leg := range legs {
+		acct, ok := chart.Get(leg.AccountID)
+		if !ok {
+			return fmt.Errorf("leg %s: unknown account %d", leg.EntryID, leg.AccountID)
+		}
+		var debit, credit string
+		if !leg.Debit.IsZero() {
+			debit = leg.Debit.StringFixed(2)
+		}
+		if !leg.Credit.IsZero() {
+			credit = leg.Credit.StringFixed(2)
+		}
+		row := []string{
+			leg.EntryGroup(),
+			leg.Date.Format(quickBooksDate),
+			acct.Name,
+			debit,
+			credit,
+			leg.Description,
+			leg.Counterparty,
+			leg.Notes,
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("writing leg %s: %w", leg.EntryID, err)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,42 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cleared-dev/cleared/internal/accounts"
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+func TestToQuickBooksCSV(t *testing.T) {
+	chart := accounts.NewService(accounts.DefaultChart("llc_single_member"))
+	day := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+	legs := []model.Leg{
+		{EntryID: "2025-01-0001a", Date: day, AccountID: 5020, Description: "GitHub Pro", Debit: decimal.RequireFromString("4"), Counterparty: "GitHub"},
+		{EntryID: "2025-01-0001b", Date: day, AccountID: 1010, Description: "GitHub Pro", Credit: decimal.RequireFromString("4"), Counterparty: "GitHub", Notes: "monthly"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, ToQuickBooksCSV(legs, chart, &buf))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"Journal No.", "Journal Date", "Account", "Debits", "Credits", "Description", "Name", "Memo"}, rows[0])
+	assert.Equal(t, []string{"2025-01-0001", "01/03/2025", "Software & SaaS", "4.00", "", "GitHub Pro", "GitHub", ""}, rows[1])
+	assert.Equal(t, []string{"2025-01-0001", "01/03/2025", "Business Checking", "", "4.00", "GitHub Pro", "GitHub", "monthly"}, rows[2])
+}
+
+func TestToQuickBooksCSV_UnknownAccount(t *testing.T) {
+	chart := accounts.NewService(accounts.DefaultChart("llc_single_member"))
+	legs := []model.Leg{{EntryID: "2025-01-0001a", AccountID: 9999, Debit: decimal.NewFromInt(1)}}
+
+	err := ToQuickBooksCSV(legs, chart, &bytes.Buffer{})
+	assert.EqualError(t, err, "leg 2025-01-0001a: unknown account 9999")
+}
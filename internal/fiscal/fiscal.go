@@ -0,0 +1,89 @@
+// Package fiscal maps dates to fiscal years and periods for a business
+// whose year may not start on January 1. The journal stays in calendar
+// months; only reports group by fiscal year.
+package fiscal
+
+import (
+	"fmt"
+	"time"
+)
+
+// Year describes when a fiscal year starts. The zero value is not valid;
+// use Calendar or Parse.
+type Year struct {
+	month time.Month
+	day   int
+}
+
+// Calendar is the January 1 fiscal year.
+var Calendar = Year{month: time.January, day: 1}
+
+// Parse reads a year start in the config's MM-DD form, e.g. "07-01". An
+// empty string is the calendar year. February 29 is rejected since most
+// years would have no such start date.
+func Parse(yearStart string) (Year, error) {
+	if yearStart == "" {
+		return Calendar, nil
+	}
+	t, err := time.Parse("01-02", yearStart)
+	if err != nil || len(yearStart) != len("01-02") {
+		return Year{}, fmt.Errorf("%q is not a valid MM-DD date", yearStart)
+	}
+	if t.Month() == time.February && t.Day() == 29 {
+		return Year{}, fmt.Errorf("%q can't start a fiscal year; it only exists in leap years", yearStart)
+	}
+	return Year{month: t.Month(), day: t.Day()}, nil
+}
+
+// String returns the year start in MM-DD form.
+func (y Year) String() string {
+	return fmt.Sprintf("%02d-%02d", int(y.month), y.day)
+}
+
+// IsCalendar reports whether the fiscal year is the calendar year.
+func (y Year) IsCalendar() bool {
+	return y == Calendar
+}
+
+// Of returns the fiscal year containing t. A fiscal year is named by the
+// calendar year it ends in, so with a 07-01 start, 2025-07-01 through
+// 2026-06-30 is fiscal 2026; with the calendar start, fiscal and calendar
+// years coincide.
+func (y Year) Of(t time.Time) int {
+	fy := t.Year() + y.offset()
+	if t.Before(y.startIn(t.Year(), t.Location())) {
+		fy--
+	}
+	return fy
+}
+
+// Period returns t's fiscal period, 1 through 12. Periods run from the
+// start day in one month to the day before it in the next.
+func (y Year) Period(t time.Time) int {
+	start, _ := y.Bounds(y.Of(t))
+	months := (t.Year()-start.Year())*12 + int(t.Month()-start.Month())
+	if t.Day() < start.Day() {
+		months--
+	}
+	return months + 1
+}
+
+// Bounds returns the first and last day (inclusive) of fiscal year fy.
+func (y Year) Bounds(fy int) (start, end time.Time) {
+	start = y.startIn(fy-y.offset(), time.UTC)
+	end = y.startIn(fy-y.offset()+1, time.UTC).AddDate(0, 0, -1)
+	return start, end
+}
+
+// startIn returns the fiscal year start that falls in calendar year year.
+func (y Year) startIn(year int, loc *time.Location) time.Time {
+	return time.Date(year, y.month, y.day, 0, 0, 0, 0, loc)
+}
+
+// offset is 1 when fiscal years end in the calendar year after they start.
+func (y Year) offset() int {
+	if y.IsCalendar() {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,80 @@
+package fiscal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(y, m, d int) time.Time {
+	return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	y, err := Parse("")
+	require.NoError(t, err)
+	assert.True(t, y.IsCalendar(), "empty is the calendar year")
+
+	y, err = Parse("07-01")
+	require.NoError(t, err)
+	assert.Equal(t, "07-01", y.String())
+	assert.False(t, y.IsCalendar())
+
+	for _, bad := range []string{"13-01", "7-1", "02-30", "02-29", "July"} {
+		_, err := Parse(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestOf_JulyStart(t *testing.T) {
+	y, err := Parse("07-01")
+	require.NoError(t, err)
+
+	tests := []struct {
+		date   time.Time
+		year   int
+		period int
+	}{
+		{date(2025, 6, 30), 2025, 12},
+		{date(2025, 7, 1), 2026, 1},
+		{date(2025, 12, 31), 2026, 6},
+		{date(2026, 1, 1), 2026, 7},
+		{date(2026, 6, 30), 2026, 12},
+		{date(2026, 7, 1), 2027, 1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.year, y.Of(tt.date), "fiscal year of %s", tt.date.Format("2006-01-02"))
+		assert.Equal(t, tt.period, y.Period(tt.date), "period of %s", tt.date.Format("2006-01-02"))
+	}
+
+	start, end := y.Bounds(2026)
+	assert.Equal(t, date(2025, 7, 1), start)
+	assert.Equal(t, date(2026, 6, 30), end)
+}
+
+func TestOf_Calendar(t *testing.T) {
+	assert.Equal(t, 2025, Calendar.Of(date(2025, 1, 1)))
+	assert.Equal(t, 2025, Calendar.Of(date(2025, 12, 31)))
+	assert.Equal(t, 3, Calendar.Period(date(2025, 3, 15)))
+
+	start, end := Calendar.Bounds(2025)
+	assert.Equal(t, date(2025, 1, 1), start)
+	assert.Equal(t, date(2025, 12, 31), end)
+}
+
+func TestPeriod_MidMonthStart(t *testing.T) {
+	y, err := Parse("04-06") // UK tax year
+	require.NoError(t, err)
+
+	assert.Equal(t, 2026, y.Of(date(2025, 4, 6)))
+	assert.Equal(t, 2025, y.Of(date(2025, 4, 5)))
+	assert.Equal(t, 1, y.Period(date(2025, 5, 5)))
+	assert.Equal(t, 2, y.Period(date(2025, 5, 6)))
+	assert.Equal(t, 12, y.Period(date(2026, 4, 5)))
+
+	start, end := y.Bounds(2026)
+	assert.Equal(t, date(2025, 4, 6), start)
+	assert.Equal(t, date(2026, 4, 5), end)
+}
@@ -0,0 +1,38 @@
+package gitops
+
+import (
+	"strings"
+	"time"
+)
+
+// CloseTagPrefix begins the tag cleared close puts on a month, followed by
+// the month as YYYY-MM.
+const CloseTagPrefix = "close-"
+
+// CloseTag returns the tag marking the close of month, e.g. "close-2025-01".
+func CloseTag(month time.Time) string {
+	return CloseTagPrefix + month.Format("2006-01")
+}
+
+// ClosedMonths returns the first day of every month with a close tag in dir,
+// oldest first. A directory that is not a git repository has none.
+func ClosedMonths(dir string) ([]time.Time, error) {
+	if !IsRepo(dir) {
+		return nil, nil
+	}
+	tags, err := Tags(dir)
+	if err != nil {
+		return nil, err
+	}
+	var months []time.Time
+	for _, tag := range tags {
+		rest, ok := strings.CutPrefix(tag, CloseTagPrefix)
+		if !ok {
+			continue
+		}
+		if month, err := time.Parse("2006-01", rest); err == nil {
+			months = append(months, month)
+		}
+	}
+	return months, nil
+}
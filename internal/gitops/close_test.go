@@ -0,0 +1,35 @@
+package gitops
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClosedMonths(t *testing.T) {
+	dir := t.TempDir()
+	months, err := ClosedMonths(dir)
+	require.NoError(t, err)
+	assert.Empty(t, months, "not a repository")
+
+	require.NoError(t, Init(dir))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+	_, err = CommitAll(dir, "import: batch", "Test Author", "test@example.com")
+	require.NoError(t, err)
+
+	feb := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	jan := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "close-2025-02", CloseTag(feb))
+	require.NoError(t, Tag(dir, CloseTag(feb), "close: 2025-02"))
+	require.NoError(t, Tag(dir, CloseTag(jan), "close: 2025-01"))
+	require.NoError(t, Tag(dir, "close-soon", "not a month"))
+	require.NoError(t, Tag(dir, "v1.0", "release"))
+
+	months, err = ClosedMonths(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{jan, feb}, months)
+}
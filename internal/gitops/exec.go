@@ -0,0 +1,233 @@
+package gitops
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ExecBackend runs the git CLI.
+type ExecBackend struct{}
+
+func (ExecBackend) Init(dir string) error {
+	cmd := exec.Command("git", "init")
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git init: %w", err)
+	}
+	return nil
+}
+
+func (e ExecBackend) CommitAll(dir, message, authorName, authorEmail string) (string, error) {
+	changed, err := e.ChangedPaths(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(changed) == 0 {
+		return "", ErrNothingToCommit
+	}
+
+	// Stage all files.
+	add := exec.Command("git", "add", "-A")
+	add.Dir = dir
+	if out, err := add.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git add: %s: %w", out, err)
+	}
+	return execCommitStaged(dir, message, authorName, authorEmail)
+}
+
+func (e ExecBackend) Commit(dir string, paths []string, message, authorName, authorEmail string) (string, error) {
+	changed, err := e.ChangedPaths(dir)
+	if err != nil {
+		return "", err
+	}
+	changed = within(changed, paths)
+	if len(changed) == 0 {
+		return "", ErrNothingToCommit
+	}
+
+	// Stage the changed files by name; a listed path that was never
+	// tracked and no longer exists would fail as a pathspec.
+	add := exec.Command("git", append([]string{"add", "-A", "--"}, changed...)...)
+	add.Dir = dir
+	if out, err := add.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git add: %s: %w", out, err)
+	}
+	return execCommitStaged(dir, message, authorName, authorEmail)
+}
+
+// execCommitStaged commits the index and returns the full hash.
+func execCommitStaged(dir, message, authorName, authorEmail string) (string, error) {
+	author := fmt.Sprintf("%s <%s>", authorName, authorEmail)
+
+	// Commit.
+	commit := exec.Command("git", "commit", "-m", message, "--author", author)
+	commit.Dir = dir
+	if out, err := commit.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git commit: %s: %w", out, err)
+	}
+
+	// Get the hash.
+	rev := exec.Command("git", "rev-parse", "HEAD")
+	rev.Dir = dir
+	out, err := rev.Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (ExecBackend) IsRepo(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+func (ExecBackend) ChangedPaths(dir string) ([]string, error) {
+	cmd := exec.Command("git", "status", "--porcelain", "-z", "--untracked-files=all")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git status: %w", err)
+	}
+
+	// Each record is "XY path"; renames and copies carry the original path
+	// as an extra NUL-terminated field.
+	var paths []string
+	fields := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	for i := 0; i < len(fields); i++ {
+		if len(fields[i]) < 4 {
+			continue
+		}
+		paths = append(paths, fields[i][3:])
+		if x := fields[i][0]; x == 'R' || x == 'C' {
+			i++
+		}
+	}
+	return paths, nil
+}
+
+func (ExecBackend) CheckoutBranch(dir, branch string) error {
+	args := []string{"checkout", "-b", branch}
+	exists := exec.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+branch)
+	exists.Dir = dir
+	if exists.Run() == nil {
+		args = []string{"checkout", branch}
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git checkout %s: %s: %w", branch, strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+func (ExecBackend) Head(dir string) (CommitInfo, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%H%x00%an%x00%ae%x00%B")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return CommitInfo{}, fmt.Errorf("git log: %w", err)
+	}
+	fields := strings.SplitN(string(out), "\x00", 4)
+	if len(fields) != 4 {
+		return CommitInfo{}, fmt.Errorf("git log: unexpected output %q", out)
+	}
+	return CommitInfo{
+		Hash:        fields[0],
+		AuthorName:  fields[1],
+		AuthorEmail: fields[2],
+		Message:     strings.TrimSpace(fields[3]),
+	}, nil
+}
+
+func (e ExecBackend) RevertHead(dir string) (string, error) {
+	head, err := e.Head(dir)
+	if err != nil {
+		return "", err
+	}
+
+	// The paths HEAD changed, including for a root commit.
+	diff := exec.Command("git", "diff-tree", "--no-commit-id", "--name-only", "-r", "-z", "--root", "HEAD")
+	diff.Dir = dir
+	out, err := diff.Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff-tree: %w", err)
+	}
+	if len(out) == 0 {
+		return "", ErrNothingToCommit
+	}
+	paths := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	changed, err := e.ChangedPaths(dir)
+	if err != nil {
+		return "", err
+	}
+	if dirty := within(changed, paths); len(dirty) > 0 {
+		return "", fmt.Errorf("reverting %s: uncommitted changes to %s", ShortHash(head.Hash), strings.Join(dirty, ", "))
+	}
+
+	revert := exec.Command("git", "revert", "--no-commit", "HEAD")
+	revert.Dir = dir
+	if out, err := revert.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git revert: %s: %w", strings.TrimSpace(string(out)), err)
+	}
+	return execCommitStaged(dir, revertMessage(head), head.AuthorName, head.AuthorEmail)
+}
+
+func (ExecBackend) DiffFiles(dir, fromRev, toRev string) ([]FileDiff, error) {
+	cmd := exec.Command("git", "diff", "--name-status", "--no-renames", "-z", fromRev, toRev, "--")
+	cmd.Dir = dir
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s %s: %s: %w", fromRev, toRev, strings.TrimSpace(stderr.String()), err)
+	}
+
+	// Records are "status\x00path\x00".
+	var diffs []FileDiff
+	fields := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		diffs = append(diffs, FileDiff{Path: fields[i+1], Change: FileChange(fields[i])})
+	}
+	return diffs, nil
+}
+
+func (ExecBackend) ShowFile(dir, rev, path string) ([]byte, error) {
+	cmd := exec.Command("git", "show", rev+":"+path)
+	cmd.Dir = dir
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git show %s:%s: %s: %w", rev, path, strings.TrimSpace(stderr.String()), err)
+	}
+	return out, nil
+}
+
+func (e ExecBackend) Tag(dir, name, message string) error {
+	head, err := e.Head(dir)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("git", "tag", "-a", name, "-m", message)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_COMMITTER_NAME="+head.AuthorName, "GIT_COMMITTER_EMAIL="+head.AuthorEmail)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git tag %s: %s: %w", name, strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+func (ExecBackend) Tags(dir string) ([]string, error) {
+	cmd := exec.Command("git", "tag", "--list")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git tag: %w", err)
+	}
+	return strings.Fields(string(out)), nil
+}
@@ -1,55 +1,230 @@
+// Package gitops commits repository changes, either by running the git CLI
+// or with a pure-Go implementation for systems without git installed.
 package gitops
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
+	"path"
 	"strings"
 )
 
+// BackendEnv names the environment variable that selects the git backend:
+// "exec" runs the git CLI, "go-git" uses the pure-Go implementation. Unset
+// (or any other value) uses the git CLI when it is on PATH and go-git
+// otherwise.
+const BackendEnv = "CLEARED_GIT"
+
+// ErrNothingToCommit is returned by CommitAll when the working tree has no
+// changes. Callers that commit after work which may change nothing can treat
+// it as a no-op.
+var ErrNothingToCommit = errors.New("nothing to commit")
+
+// Backend performs the git operations cleared needs. Implementations must
+// agree on behavior: the given author on commits, and the full hash returned.
+type Backend interface {
+	// Init initializes a new git repository at dir.
+	Init(dir string) error
+	// CommitAll stages all files and creates a commit. Returns the full
+	// commit hash, or ErrNothingToCommit if there were no changes.
+	CommitAll(dir, message, authorName, authorEmail string) (string, error)
+	// Commit stages the changes under paths, which are relative to dir and
+	// may name files or directories, and creates a commit. Other changes are
+	// left uncommitted unless already staged. Returns the full commit hash,
+	// or ErrNothingToCommit if nothing under paths changed.
+	Commit(dir string, paths []string, message, authorName, authorEmail string) (string, error)
+	// IsRepo reports whether dir is the root of a git repository.
+	IsRepo(dir string) bool
+	// ChangedPaths lists the paths with uncommitted changes in dir,
+	// including untracked files, relative to the repository root with
+	// forward slashes, sorted.
+	ChangedPaths(dir string) ([]string, error)
+	// CheckoutBranch switches dir to branch, first creating it at HEAD if
+	// it does not exist. Uncommitted changes are carried over.
+	CheckoutBranch(dir, branch string) error
+	// Head describes the commit HEAD points to.
+	Head(dir string) (CommitInfo, error)
+	// RevertHead commits the reverse of HEAD's changes, authored as HEAD
+	// was, with git revert's message. Returns the full commit hash, or an
+	// error if a path HEAD changed has uncommitted changes.
+	RevertHead(dir string) (string, error)
+	// DiffFiles lists the files that differ between the trees of fromRev
+	// and toRev, sorted by path. A rename is a deletion and an addition.
+	DiffFiles(dir, fromRev, toRev string) ([]FileDiff, error)
+	// ShowFile returns the content of path, relative to the repository root
+	// with forward slashes, in rev's tree.
+	ShowFile(dir, rev, path string) ([]byte, error)
+	// Tag creates the annotated tag name at HEAD, tagged by HEAD's author.
+	// It fails if the tag already exists.
+	Tag(dir, name, message string) error
+	// Tags lists the repository's tag names, sorted.
+	Tags(dir string) ([]string, error)
+}
+
+// FileChange is how a file differs between two revisions, as the status
+// letter git diff --name-status prints.
+type FileChange string
+
+const (
+	FileAdded    FileChange = "A"
+	FileModified FileChange = "M"
+	FileDeleted  FileChange = "D"
+)
+
+// FileDiff is a file that differs between two revisions.
+type FileDiff struct {
+	Path   string // relative to the repository root, with forward slashes
+	Change FileChange
+}
+
+// CommitInfo describes a commit.
+type CommitInfo struct {
+	Hash        string // full hash
+	Message     string // trimmed
+	AuthorName  string
+	AuthorEmail string
+}
+
+// Subject returns the first line of the commit message.
+func (c CommitInfo) Subject() string {
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	return subject
+}
+
+// Current returns the backend selected by BackendEnv.
+func Current() Backend {
+	switch os.Getenv(BackendEnv) {
+	case "exec":
+		return ExecBackend{}
+	case "go-git":
+		return GoGitBackend{}
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return GoGitBackend{}
+	}
+	return ExecBackend{}
+}
+
 // Init initializes a new git repository at dir.
 func Init(dir string) error {
-	cmd := exec.Command("git", "init")
-	cmd.Dir = dir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("git init: %w", err)
-	}
-	return nil
+	return Current().Init(dir)
 }
 
-// CommitAll stages all files and creates a commit. Returns the short commit hash.
+// CommitAll stages all files and creates a commit. Returns the short commit
+// hash, or ErrNothingToCommit if there were no changes.
 func CommitAll(dir, message, authorName, authorEmail string) (string, error) {
-	author := fmt.Sprintf("%s <%s>", authorName, authorEmail)
+	hash, err := CommitAllFull(dir, message, authorName, authorEmail)
+	return ShortHash(hash), err
+}
 
-	// Stage all files.
-	add := exec.Command("git", "add", "-A")
-	add.Dir = dir
-	if out, err := add.CombinedOutput(); err != nil {
-		return "", fmt.Errorf("git add: %s: %w", out, err)
-	}
+// CommitAllFull is CommitAll returning the full 40-character hash.
+func CommitAllFull(dir, message, authorName, authorEmail string) (string, error) {
+	return Current().CommitAll(dir, message, authorName, authorEmail)
+}
 
-	// Commit.
-	commit := exec.Command("git", "commit", "-m", message, "--author", author)
-	commit.Dir = dir
-	if out, err := commit.CombinedOutput(); err != nil {
-		return "", fmt.Errorf("git commit: %s: %w", out, err)
-	}
+// Commit stages only the changes under paths and creates a commit. Returns
+// the short commit hash, or ErrNothingToCommit if nothing under paths changed.
+func Commit(dir string, paths []string, message, authorName, authorEmail string) (string, error) {
+	hash, err := CommitFull(dir, paths, message, authorName, authorEmail)
+	return ShortHash(hash), err
+}
 
-	// Get short hash.
-	rev := exec.Command("git", "rev-parse", "--short", "HEAD")
-	rev.Dir = dir
-	out, err := rev.Output()
-	if err != nil {
-		return "", fmt.Errorf("git rev-parse: %w", err)
+// CommitFull is Commit returning the full 40-character hash.
+func CommitFull(dir string, paths []string, message, authorName, authorEmail string) (string, error) {
+	return Current().Commit(dir, paths, message, authorName, authorEmail)
+}
+
+// shortHashLen matches git's default abbreviation.
+const shortHashLen = 7
+
+// ShortHash abbreviates a full commit hash for display.
+func ShortHash(hash string) string {
+	if len(hash) > shortHashLen {
+		return hash[:shortHashLen]
 	}
-	return strings.TrimSpace(string(out)), nil
+	return hash
 }
 
 // IsRepo reports whether dir is inside a git repository.
 func IsRepo(dir string) bool {
-	_, err := os.Stat(filepath.Join(dir, ".git"))
-	return err == nil
+	return Current().IsRepo(dir)
+}
+
+// ChangedPaths lists the paths with uncommitted changes in dir, including
+// untracked files, relative to the repository root with forward slashes.
+func ChangedPaths(dir string) ([]string, error) {
+	return Current().ChangedPaths(dir)
+}
+
+// CheckoutBranch switches dir to branch, creating it at HEAD if it does not
+// exist, so later commits land there rather than on the current branch.
+// Uncommitted changes are carried over.
+func CheckoutBranch(dir, branch string) error {
+	return Current().CheckoutBranch(dir, branch)
+}
+
+// Head describes the commit HEAD points to in dir.
+func Head(dir string) (CommitInfo, error) {
+	return Current().Head(dir)
+}
+
+// RevertLast creates a commit undoing HEAD's changes, keeping HEAD in the
+// history as git revert does. Returns the short hash of the new commit.
+func RevertLast(dir string) (string, error) {
+	hash, err := Current().RevertHead(dir)
+	return ShortHash(hash), err
+}
+
+// DiffFiles lists the files that differ between fromRev and toRev, which
+// may be anything git rev-parse accepts (hashes, branches, HEAD~1).
+func DiffFiles(dir, fromRev, toRev string) ([]FileDiff, error) {
+	return Current().DiffFiles(dir, fromRev, toRev)
+}
+
+// ShowFile returns the content of path as of rev.
+func ShowFile(dir, rev, path string) ([]byte, error) {
+	return Current().ShowFile(dir, rev, path)
+}
+
+// Tag creates the annotated tag name at HEAD. Git signs it when the
+// repository's tag.gpgSign config asks it to.
+func Tag(dir, name, message string) error {
+	return Current().Tag(dir, name, message)
+}
+
+// Tags lists the tags in dir, sorted.
+func Tags(dir string) ([]string, error) {
+	return Current().Tags(dir)
+}
+
+// revertMessage is the message git revert gives a commit reverting c.
+func revertMessage(c CommitInfo) string {
+	return fmt.Sprintf("Revert \"%s\"\n\nThis reverts commit %s.", c.Subject(), c.Hash)
+}
+
+// IsClean reports whether dir has no uncommitted changes or untracked files.
+func IsClean(dir string) (bool, error) {
+	paths, err := ChangedPaths(dir)
+	if err != nil {
+		return false, err
+	}
+	return len(paths) == 0, nil
+}
+
+// within returns the changed paths that are one of paths or inside one of
+// them.
+func within(changed, paths []string) []string {
+	var matched []string
+	for _, c := range changed {
+		for _, p := range paths {
+			p = strings.TrimSuffix(path.Clean(p), "/")
+			if c == p || strings.HasPrefix(c, p+"/") {
+				matched = append(matched, c)
+				break
+			}
+		}
+	}
+	return matched
 }
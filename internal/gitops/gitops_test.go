@@ -4,51 +4,370 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// backends runs fn once per Backend, so both implementations pass the same
+// suite.
+func backends(t *testing.T, fn func(t *testing.T, b Backend)) {
+	t.Helper()
+	for name, b := range map[string]Backend{"exec": ExecBackend{}, "go-git": GoGitBackend{}} {
+		t.Run(name, func(t *testing.T) { fn(t, b) })
+	}
+}
+
 func TestInit(t *testing.T) {
-	dir := t.TempDir()
-	err := Init(dir)
-	require.NoError(t, err)
+	backends(t, func(t *testing.T, b Backend) {
+		dir := t.TempDir()
+		err := b.Init(dir)
+		require.NoError(t, err)
 
-	_, err = os.Stat(filepath.Join(dir, ".git"))
-	require.NoError(t, err, ".git directory should exist")
+		_, err = os.Stat(filepath.Join(dir, ".git"))
+		require.NoError(t, err, ".git directory should exist")
+	})
 }
 
 func TestIsRepo(t *testing.T) {
-	dir := t.TempDir()
-	assert.False(t, IsRepo(dir), "empty dir should not be a repo")
+	backends(t, func(t *testing.T, b Backend) {
+		dir := t.TempDir()
+		assert.False(t, b.IsRepo(dir), "empty dir should not be a repo")
 
-	require.NoError(t, Init(dir))
-	assert.True(t, IsRepo(dir), "initialized dir should be a repo")
+		require.NoError(t, b.Init(dir))
+		assert.True(t, b.IsRepo(dir), "initialized dir should be a repo")
+	})
 }
 
 func TestCommitAll(t *testing.T) {
+	backends(t, func(t *testing.T, b Backend) {
+		dir := t.TempDir()
+		require.NoError(t, b.Init(dir))
+
+		// Create a file to commit.
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "test.txt"), []byte("hello"), 0o644))
+
+		hash, err := b.CommitAll(dir, "init: test commit", "Test Author", "test@example.com")
+		require.NoError(t, err)
+		assert.Regexp(t, "^[0-9a-f]{40}$", hash, "backends return the full hash")
+
+		// Verify commit message.
+		log := exec.Command("git", "log", "--format=%s", "-1")
+		log.Dir = dir
+		out, err := log.Output()
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "init: test commit")
+
+		// Verify author and hash.
+		authorLog := exec.Command("git", "log", "--format=%an <%ae> %H", "-1")
+		authorLog.Dir = dir
+		out, err = authorLog.Output()
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "Test Author <test@example.com> "+hash)
+	})
+}
+
+func TestCommitAllFull(t *testing.T) {
 	dir := t.TempDir()
 	require.NoError(t, Init(dir))
 
-	// Create a file to commit.
-	require.NoError(t, os.WriteFile(filepath.Join(dir, "test.txt"), []byte("hello"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+	short, err := CommitAll(dir, "first", "Test Author", "test@example.com")
+	require.NoError(t, err)
+	assert.Len(t, short, 7)
 
-	hash, err := CommitAll(dir, "init: test commit", "Test Author", "test@example.com")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0o644))
+	full, err := CommitAllFull(dir, "second", "Test Author", "test@example.com")
 	require.NoError(t, err)
-	assert.NotEmpty(t, hash)
+	assert.Regexp(t, "^[0-9a-f]{40}$", full)
+	assert.True(t, strings.HasPrefix(full, ShortHash(full)))
 
-	// Verify commit message.
-	log := exec.Command("git", "log", "--format=%s", "-1")
-	log.Dir = dir
-	out, err := log.Output()
+	rev := exec.Command("git", "rev-parse", "--short", "HEAD")
+	rev.Dir = dir
+	out, err := rev.Output()
 	require.NoError(t, err)
-	assert.Contains(t, string(out), "init: test commit")
+	assert.True(t, strings.HasPrefix(full, strings.TrimSpace(string(out))), "git's short hash prefixes the full one")
+	assert.NotEqual(t, short, ShortHash(full))
+}
+
+func TestCommitAll_StagesDeletions(t *testing.T) {
+	backends(t, func(t *testing.T, b Backend) {
+		dir := t.TempDir()
+		require.NoError(t, b.Init(dir))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "gone.txt"), []byte("x"), 0o644))
+		_, err := b.CommitAll(dir, "add", "Test Author", "test@example.com")
+		require.NoError(t, err)
+
+		require.NoError(t, os.Remove(filepath.Join(dir, "gone.txt")))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "kept.txt"), []byte("y"), 0o644))
+		_, err = b.CommitAll(dir, "swap", "Test Author", "test@example.com")
+		require.NoError(t, err)
+
+		clean, err := b.ChangedPaths(dir)
+		require.NoError(t, err)
+		assert.Empty(t, clean, "deletion and addition both committed")
+	})
+}
 
-	// Verify author.
-	authorLog := exec.Command("git", "log", "--format=%an <%ae>", "-1")
-	authorLog.Dir = dir
-	out, err = authorLog.Output()
+func TestCommitAll_NothingToCommit(t *testing.T) {
+	backends(t, func(t *testing.T, b Backend) {
+		dir := t.TempDir()
+		require.NoError(t, b.Init(dir))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+
+		_, err := b.CommitAll(dir, "first", "Test Author", "test@example.com")
+		require.NoError(t, err)
+
+		_, err = b.CommitAll(dir, "second", "Test Author", "test@example.com")
+		require.ErrorIs(t, err, ErrNothingToCommit)
+	})
+}
+
+func TestCommit_OnlyListedPaths(t *testing.T) {
+	backends(t, func(t *testing.T, b Backend) {
+		dir := t.TempDir()
+		require.NoError(t, b.Init(dir))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+		_, err := b.CommitAll(dir, "first", "Test Author", "test@example.com")
+		require.NoError(t, err)
+
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "2025", "01"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "2025", "01", "journal.csv"), []byte("j"), 0o644))
+		require.NoError(t, os.Remove(filepath.Join(dir, "a.txt")))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "stray.txt"), []byte("s"), 0o644))
+
+		// A directory, a deleted file, and a path that never existed.
+		hash, err := b.Commit(dir, []string{"2025", "a.txt", "import/gone.csv"}, "journal", "Test Author", "test@example.com")
+		require.NoError(t, err)
+		assert.Len(t, hash, 40)
+
+		changed, err := b.ChangedPaths(dir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"stray.txt"}, changed, "unrelated file left uncommitted")
+
+		_, err = b.Commit(dir, []string{"2025"}, "again", "Test Author", "test@example.com")
+		require.ErrorIs(t, err, ErrNothingToCommit)
+	})
+}
+
+func TestIsClean(t *testing.T) {
+	backends(t, func(t *testing.T, b Backend) {
+		t.Setenv(BackendEnv, map[Backend]string{ExecBackend{}: "exec", GoGitBackend{}: "go-git"}[b])
+
+		dir := t.TempDir()
+		require.NoError(t, Init(dir))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+		_, err := CommitAll(dir, "add a", "Test Author", "test@example.com")
+		require.NoError(t, err)
+
+		clean, err := IsClean(dir)
+		require.NoError(t, err)
+		assert.True(t, clean)
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed"), 0o644))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "new file.txt"), []byte("b"), 0o644))
+
+		clean, err = IsClean(dir)
+		require.NoError(t, err)
+		assert.False(t, clean)
+
+		paths, err := ChangedPaths(dir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a.txt", "sub/new file.txt"}, paths)
+	})
+}
+
+func TestIsClean_NotARepo(t *testing.T) {
+	backends(t, func(t *testing.T, b Backend) {
+		_, err := b.ChangedPaths(t.TempDir())
+		require.Error(t, err)
+	})
+}
+
+func TestCurrent(t *testing.T) {
+	t.Setenv(BackendEnv, "go-git")
+	assert.Equal(t, GoGitBackend{}, Current())
+
+	t.Setenv(BackendEnv, "exec")
+	assert.Equal(t, ExecBackend{}, Current())
+
+	t.Setenv(BackendEnv, "")
+	t.Setenv("PATH", "")
+	assert.Equal(t, GoGitBackend{}, Current(), "falls back without git on PATH")
+}
+
+// gitOutput runs git in dir and returns its trimmed output.
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	require.NoError(t, err, "git %v", args)
+	return strings.TrimSpace(string(out))
+}
+
+func TestCheckoutBranch(t *testing.T) {
+	backends(t, func(t *testing.T, b Backend) {
+		dir := t.TempDir()
+		require.NoError(t, b.Init(dir))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+		_, err := b.CommitAll(dir, "init: first", "Test Author", "test@example.com")
+		require.NoError(t, err)
+		main := gitOutput(t, dir, "rev-parse", "--abbrev-ref", "HEAD")
+		mainHead := gitOutput(t, dir, "rev-parse", "HEAD")
+
+		// Uncommitted work follows the switch.
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0o644))
+		require.NoError(t, b.CheckoutBranch(dir, "agent/ingest/20250103-060000"))
+		assert.Equal(t, "agent/ingest/20250103-060000", gitOutput(t, dir, "rev-parse", "--abbrev-ref", "HEAD"))
+		hash, err := b.CommitAll(dir, "import: on branch", "Test Author", "test@example.com")
+		require.NoError(t, err)
+
+		assert.Equal(t, mainHead, gitOutput(t, dir, "rev-parse", main), "main is untouched")
+		assert.Equal(t, hash, gitOutput(t, dir, "rev-parse", "agent/ingest/20250103-060000"))
+
+		// Switching to an existing branch does not recreate it.
+		require.NoError(t, b.CheckoutBranch(dir, main))
+		assert.Equal(t, main, gitOutput(t, dir, "rev-parse", "--abbrev-ref", "HEAD"))
+		_, err = os.Stat(filepath.Join(dir, "b.txt"))
+		require.ErrorIs(t, err, os.ErrNotExist, "worktree matches main again")
+		require.NoError(t, b.CheckoutBranch(dir, "agent/ingest/20250103-060000"))
+		assert.Equal(t, hash, gitOutput(t, dir, "rev-parse", "HEAD"))
+
+		require.Error(t, b.CheckoutBranch(dir, "bad..name"))
+	})
+}
+
+func TestRevertHead(t *testing.T) {
+	backends(t, func(t *testing.T, b Backend) {
+		dir := t.TempDir()
+		require.NoError(t, b.Init(dir))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "gone.txt"), []byte("gone"), 0o644))
+		_, err := b.CommitAll(dir, "init: first", "Test Author", "test@example.com")
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed"), 0o644))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "new.txt"), []byte("new"), 0o644))
+		require.NoError(t, os.Remove(filepath.Join(dir, "gone.txt")))
+		imported, err := b.CommitAll(dir, "import: batch\n\ndetails", "Agent", "agent@example.com")
+		require.NoError(t, err)
+
+		head, err := b.Head(dir)
+		require.NoError(t, err)
+		assert.Equal(t, CommitInfo{Hash: imported, Message: "import: batch\n\ndetails", AuthorName: "Agent", AuthorEmail: "agent@example.com"}, head)
+		assert.Equal(t, "import: batch", head.Subject())
+
+		// A path the revert would touch must be committed first.
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("edited"), 0o644))
+		_, err = b.RevertHead(dir)
+		require.ErrorContains(t, err, "uncommitted changes to a.txt")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed"), 0o644))
+
+		// Unrelated work is left alone.
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "other.txt"), []byte("other"), 0o644))
+		hash, err := b.RevertHead(dir)
+		require.NoError(t, err)
+		assert.Equal(t, hash, gitOutput(t, dir, "rev-parse", "HEAD"))
+		assert.Equal(t, imported, gitOutput(t, dir, "rev-parse", "HEAD^"), "history is kept")
+		assert.Equal(t, "Revert \"import: batch\"\n\nThis reverts commit "+imported+".", gitOutput(t, dir, "log", "-1", "--format=%B"))
+		assert.Equal(t, "Agent <agent@example.com>", gitOutput(t, dir, "log", "-1", "--format=%an <%ae>"))
+
+		data, err := os.ReadFile(filepath.Join(dir, "a.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "a", string(data))
+		data, err = os.ReadFile(filepath.Join(dir, "gone.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "gone", string(data))
+		_, err = os.Stat(filepath.Join(dir, "sub", "new.txt"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+		assert.Empty(t, gitOutput(t, dir, "diff", "HEAD~2", "HEAD"), "tree matches the first commit")
+		assert.Equal(t, "?? other.txt", gitOutput(t, dir, "status", "--porcelain"))
+	})
+}
+
+func TestRevertLast(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, Init(dir))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+	_, err := CommitAll(dir, "first", "Test Author", "test@example.com")
 	require.NoError(t, err)
-	assert.Contains(t, string(out), "Test Author <test@example.com>")
+
+	short, err := RevertLast(dir)
+	require.NoError(t, err, "a root commit reverts to the empty tree")
+	assert.Len(t, short, 7)
+	_, err = os.Stat(filepath.Join(dir, "a.txt"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestDiffFiles(t *testing.T) {
+	backends(t, func(t *testing.T, b Backend) {
+		dir := t.TempDir()
+		require.NoError(t, b.Init(dir))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "gone.txt"), []byte("gone"), 0o644))
+		first, err := b.CommitAll(dir, "init: first", "Test Author", "test@example.com")
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed"), 0o644))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "2025", "01"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "2025", "01", "journal.csv"), []byte("new"), 0o644))
+		require.NoError(t, os.Remove(filepath.Join(dir, "gone.txt")))
+		_, err = b.CommitAll(dir, "import: batch", "Test Author", "test@example.com")
+		require.NoError(t, err)
+
+		diffs, err := b.DiffFiles(dir, first, "HEAD")
+		require.NoError(t, err)
+		assert.Equal(t, []FileDiff{
+			{Path: "2025/01/journal.csv", Change: FileAdded},
+			{Path: "a.txt", Change: FileModified},
+			{Path: "gone.txt", Change: FileDeleted},
+		}, diffs)
+
+		diffs, err = b.DiffFiles(dir, "HEAD", "HEAD")
+		require.NoError(t, err)
+		assert.Empty(t, diffs)
+		_, err = b.DiffFiles(dir, "no-such-rev", "HEAD")
+		require.Error(t, err)
+
+		data, err := b.ShowFile(dir, "HEAD~1", "a.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "a", string(data))
+		data, err = b.ShowFile(dir, "HEAD", "2025/01/journal.csv")
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(data))
+		_, err = b.ShowFile(dir, "HEAD", "gone.txt")
+		require.Error(t, err)
+	})
+}
+
+func TestTag(t *testing.T) {
+	backends(t, func(t *testing.T, b Backend) {
+		dir := t.TempDir()
+		require.NoError(t, b.Init(dir))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+		hash, err := b.CommitAll(dir, "import: batch", "Test Author", "test@example.com")
+		require.NoError(t, err)
+
+		tags, err := b.Tags(dir)
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+
+		require.NoError(t, b.Tag(dir, "close-2025-01", "close: 2025-01"))
+		require.NoError(t, b.Tag(dir, "b-tag", "another"))
+		assert.Equal(t, hash, gitOutput(t, dir, "rev-parse", "close-2025-01^{commit}"))
+		assert.Equal(t, "tag", gitOutput(t, dir, "cat-file", "-t", "close-2025-01"), "annotated")
+		assert.Equal(t, "close: 2025-01", gitOutput(t, dir, "tag", "-l", "--format=%(contents)", "close-2025-01"))
+		assert.Equal(t, "Test Author <test@example.com>", gitOutput(t, dir, "tag", "-l", "--format=%(taggername) %(taggeremail)", "close-2025-01"))
+
+		tags, err = b.Tags(dir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b-tag", "close-2025-01"}, tags)
+
+		require.Error(t, b.Tag(dir, "close-2025-01", "again"), "tags are not moved")
+	})
 }
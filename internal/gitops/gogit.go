@@ -0,0 +1,398 @@
+package gitops
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+// GoGitBackend uses go-git, so cleared works without git installed.
+type GoGitBackend struct{}
+
+func (GoGitBackend) Init(dir string) error {
+	if _, err := git.PlainInit(dir, false); err != nil {
+		return fmt.Errorf("git init: %w", err)
+	}
+	return nil
+}
+
+func (GoGitBackend) CommitAll(dir, message, authorName, authorEmail string) (string, error) {
+	wt, status, err := openStatus(dir)
+	if err != nil {
+		return "", err
+	}
+	if status.IsClean() {
+		return "", ErrNothingToCommit
+	}
+	if err := stage(wt, status, changedPaths(status)); err != nil {
+		return "", err
+	}
+	return commitStaged(wt, message, authorName, authorEmail)
+}
+
+func (GoGitBackend) Commit(dir string, paths []string, message, authorName, authorEmail string) (string, error) {
+	wt, status, err := openStatus(dir)
+	if err != nil {
+		return "", err
+	}
+	changed := within(changedPaths(status), paths)
+	if len(changed) == 0 {
+		return "", ErrNothingToCommit
+	}
+	if err := stage(wt, status, changed); err != nil {
+		return "", err
+	}
+	return commitStaged(wt, message, authorName, authorEmail)
+}
+
+// openStatus opens the worktree at dir and reads its status.
+func openStatus(dir string) (*git.Worktree, git.Status, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening repository: %w", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening worktree: %w", err)
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return nil, nil, fmt.Errorf("git status: %w", err)
+	}
+	return wt, status, nil
+}
+
+// stage adds paths to the index, including deletions, as git add -A does.
+func stage(wt *git.Worktree, status git.Status, paths []string) error {
+	for _, path := range paths {
+		var err error
+		switch status.File(path).Worktree {
+		case git.Unmodified:
+			continue
+		case git.Deleted:
+			_, err = wt.Remove(path)
+		default:
+			_, err = wt.Add(path)
+		}
+		if err != nil {
+			return fmt.Errorf("git add %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// commitStaged commits the index and returns the full hash.
+func commitStaged(wt *git.Worktree, message, authorName, authorEmail string) (string, error) {
+	hash, err := wt.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{Name: authorName, Email: authorEmail, When: time.Now()},
+	})
+	if err != nil {
+		return "", fmt.Errorf("git commit: %w", err)
+	}
+	return hash.String(), nil
+}
+
+// changedPaths lists the paths in status with staged or unstaged changes,
+// sorted.
+func changedPaths(status git.Status) []string {
+	var paths []string
+	for path, fs := range status {
+		if fs.Staging != git.Unmodified || fs.Worktree != git.Unmodified {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+func (GoGitBackend) IsRepo(dir string) bool {
+	_, err := git.PlainOpen(dir)
+	return err == nil
+}
+
+func (GoGitBackend) ChangedPaths(dir string) ([]string, error) {
+	_, status, err := openStatus(dir)
+	if err != nil {
+		return nil, err
+	}
+	return changedPaths(status), nil
+}
+
+func (GoGitBackend) CheckoutBranch(dir, branch string) error {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return fmt.Errorf("opening repository: %w", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("opening worktree: %w", err)
+	}
+	ref := plumbing.NewBranchReferenceName(branch)
+	if err := ref.Validate(); err != nil {
+		return fmt.Errorf("git checkout %s: %w", branch, err)
+	}
+	_, err = repo.Reference(ref, false)
+	create := errors.Is(err, plumbing.ErrReferenceNotFound)
+	if err != nil && !create {
+		return fmt.Errorf("git checkout %s: %w", branch, err)
+	}
+	// A new branch starts at HEAD, so uncommitted changes are kept as git
+	// checkout -b keeps them; switching to an existing one replaces the tree.
+	if err := wt.Checkout(&git.CheckoutOptions{Branch: ref, Create: create, Keep: create}); err != nil {
+		return fmt.Errorf("git checkout %s: %w", branch, err)
+	}
+	return nil
+}
+
+func (GoGitBackend) Head(dir string) (CommitInfo, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return CommitInfo{}, fmt.Errorf("opening repository: %w", err)
+	}
+	head, err := headCommit(repo)
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	return commitInfo(head), nil
+}
+
+// headCommit reads the commit HEAD points to.
+func headCommit(repo *git.Repository) (*object.Commit, error) {
+	ref, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("reading HEAD: %w", err)
+	}
+	commit, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("reading commit %s: %w", ShortHash(ref.Hash().String()), err)
+	}
+	return commit, nil
+}
+
+func commitInfo(c *object.Commit) CommitInfo {
+	return CommitInfo{
+		Hash:        c.Hash.String(),
+		Message:     strings.TrimSpace(c.Message),
+		AuthorName:  c.Author.Name,
+		AuthorEmail: c.Author.Email,
+	}
+}
+
+func (GoGitBackend) RevertHead(dir string) (string, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return "", fmt.Errorf("opening repository: %w", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("opening worktree: %w", err)
+	}
+	head, err := headCommit(repo)
+	if err != nil {
+		return "", err
+	}
+	info := commitInfo(head)
+
+	tree, err := head.Tree()
+	if err != nil {
+		return "", fmt.Errorf("reading tree: %w", err)
+	}
+	// A root commit is reverted to the empty tree.
+	parentTree := &object.Tree{}
+	if head.NumParents() > 0 {
+		parent, err := head.Parent(0)
+		if err != nil {
+			return "", fmt.Errorf("reading parent: %w", err)
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return "", fmt.Errorf("reading parent tree: %w", err)
+		}
+	}
+	changes, err := object.DiffTree(tree, parentTree)
+	if err != nil {
+		return "", fmt.Errorf("git diff-tree: %w", err)
+	}
+	if len(changes) == 0 {
+		return "", ErrNothingToCommit
+	}
+	paths := make([]string, len(changes))
+	for i, ch := range changes {
+		paths[i] = cmp.Or(ch.To.Name, ch.From.Name)
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return "", fmt.Errorf("git status: %w", err)
+	}
+	if dirty := within(changedPaths(status), paths); len(dirty) > 0 {
+		return "", fmt.Errorf("reverting %s: uncommitted changes to %s", ShortHash(info.Hash), strings.Join(dirty, ", "))
+	}
+
+	// Restore each path to its content before HEAD: files HEAD added are
+	// removed, the rest are rewritten from the parent tree.
+	for i, ch := range changes {
+		full := filepath.Join(dir, filepath.FromSlash(paths[i]))
+		if ch.To.Name == "" {
+			if err := os.Remove(full); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return "", fmt.Errorf("reverting %s: %w", paths[i], err)
+			}
+			continue
+		}
+		if err := restoreFile(parentTree, paths[i], full); err != nil {
+			return "", err
+		}
+	}
+
+	if status, err = wt.Status(); err != nil {
+		return "", fmt.Errorf("git status: %w", err)
+	}
+	if err := stage(wt, status, paths); err != nil {
+		return "", err
+	}
+	return commitStaged(wt, revertMessage(info), info.AuthorName, info.AuthorEmail)
+}
+
+// restoreFile writes path's content in tree to full.
+func restoreFile(tree *object.Tree, path, full string) error {
+	f, err := tree.File(path)
+	if err != nil {
+		return fmt.Errorf("reverting %s: %w", path, err)
+	}
+	contents, err := f.Contents()
+	if err != nil {
+		return fmt.Errorf("reverting %s: %w", path, err)
+	}
+	mode, err := f.Mode.ToOSFileMode()
+	if err != nil {
+		return fmt.Errorf("reverting %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return fmt.Errorf("reverting %s: %w", path, err)
+	}
+	if err := os.WriteFile(full, []byte(contents), mode.Perm()); err != nil {
+		return fmt.Errorf("reverting %s: %w", path, err)
+	}
+	return nil
+}
+
+func (GoGitBackend) DiffFiles(dir, fromRev, toRev string) ([]FileDiff, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return nil, fmt.Errorf("opening repository: %w", err)
+	}
+	from, err := revTree(repo, fromRev)
+	if err != nil {
+		return nil, err
+	}
+	to, err := revTree(repo, toRev)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := object.DiffTree(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s %s: %w", fromRev, toRev, err)
+	}
+
+	var diffs []FileDiff
+	for _, ch := range changes {
+		action, err := ch.Action()
+		if err != nil {
+			return nil, fmt.Errorf("git diff %s %s: %w", fromRev, toRev, err)
+		}
+		d := FileDiff{Path: cmp.Or(ch.To.Name, ch.From.Name), Change: FileModified}
+		switch action {
+		case merkletrie.Insert:
+			d.Change = FileAdded
+		case merkletrie.Delete:
+			d.Change = FileDeleted
+		}
+		diffs = append(diffs, d)
+	}
+	slices.SortFunc(diffs, func(a, b FileDiff) int { return strings.Compare(a.Path, b.Path) })
+	return diffs, nil
+}
+
+func (GoGitBackend) ShowFile(dir, rev, path string) ([]byte, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return nil, fmt.Errorf("opening repository: %w", err)
+	}
+	tree, err := revTree(repo, rev)
+	if err != nil {
+		return nil, err
+	}
+	f, err := tree.File(path)
+	if err != nil {
+		return nil, fmt.Errorf("git show %s:%s: %w", rev, path, err)
+	}
+	contents, err := f.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("git show %s:%s: %w", rev, path, err)
+	}
+	return []byte(contents), nil
+}
+
+// revTree resolves rev to its commit's tree.
+func revTree(repo *git.Repository, rev string) (*object.Tree, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", rev, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("reading commit %s: %w", rev, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("reading tree of %s: %w", rev, err)
+	}
+	return tree, nil
+}
+
+func (GoGitBackend) Tag(dir, name, message string) error {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return fmt.Errorf("opening repository: %w", err)
+	}
+	head, err := headCommit(repo)
+	if err != nil {
+		return err
+	}
+	tagger := head.Author
+	tagger.When = time.Now()
+	if _, err := repo.CreateTag(name, head.Hash, &git.CreateTagOptions{Tagger: &tagger, Message: message}); err != nil {
+		return fmt.Errorf("git tag %s: %w", name, err)
+	}
+	return nil
+}
+
+func (GoGitBackend) Tags(dir string) ([]string, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return nil, fmt.Errorf("opening repository: %w", err)
+	}
+	iter, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("git tag: %w", err)
+	}
+	var names []string
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		names = append(names, ref.Name().Short())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("git tag: %w", err)
+	}
+	slices.Sort(names)
+	return names, nil
+}
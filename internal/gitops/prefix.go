@@ -0,0 +1,65 @@
+package gitops
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultCommitPrefixes are the commit message prefixes allowed when
+// cleared.yaml sets no git.commit_prefixes.
+var DefaultCommitPrefixes = []string{"init", "import", "categorize", "correct", "void"}
+
+// ErrCommitPrefix is returned by NormalizeCommitMessage for a message that
+// does not start with an allowed prefix.
+var ErrCommitPrefix = errors.New("commit message prefix not allowed")
+
+// prefixPattern matches a conventional "prefix: subject" message.
+var prefixPattern = regexp.MustCompile(`^([a-z][a-z0-9_-]*):\s*(.*)$`)
+
+// ValidPrefix reports whether p can be used as a commit message prefix: a
+// lowercase word of letters, digits, '-' and '_'.
+func ValidPrefix(p string) bool {
+	return prefixPattern.MatchString(p + ": x")
+}
+
+// NormalizeCommitMessage checks that message starts with "<prefix>: " for
+// one of allowed (DefaultCommitPrefixes when empty) and returns it trimmed.
+// A message with no prefix is given fallback's when fallback is allowed;
+// otherwise, as for a message with some other prefix, it is rejected with
+// ErrCommitPrefix.
+func NormalizeCommitMessage(message string, allowed []string, fallback string) (string, error) {
+	if len(allowed) == 0 {
+		allowed = DefaultCommitPrefixes
+	}
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return "", errors.New("commit message is empty")
+	}
+
+	if m := prefixPattern.FindStringSubmatch(message); m != nil {
+		if !slices.Contains(allowed, m[1]) {
+			return "", fmt.Errorf("%w: %q (want one of %s)", ErrCommitPrefix, m[1], strings.Join(allowed, ", "))
+		}
+		if m[2] == "" {
+			return "", fmt.Errorf("commit message %q has no subject", message)
+		}
+		return m[1] + ": " + m[2], nil
+	}
+	if !slices.Contains(allowed, fallback) {
+		return "", fmt.Errorf("%w: %q has no prefix (want one of %s)", ErrCommitPrefix, message, strings.Join(allowed, ", "))
+	}
+	return fallback + ": " + message, nil
+}
+
+// CommitPrefix returns the prefix of a "prefix: subject" commit message, or
+// "" when its first line has none.
+func CommitPrefix(message string) string {
+	subject, _, _ := strings.Cut(message, "\n")
+	if m := prefixPattern.FindStringSubmatch(strings.TrimSpace(subject)); m != nil {
+		return m[1]
+	}
+	return ""
+}
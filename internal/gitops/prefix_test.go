@@ -0,0 +1,60 @@
+package gitops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeCommitMessage_Conforming(t *testing.T) {
+	msg, err := NormalizeCommitMessage("import: 6 transactions from 1 files", nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, "import: 6 transactions from 1 files", msg)
+
+	msg, err = NormalizeCommitMessage("  void:Voided 2025-01-0015 \n", nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, "void: Voided 2025-01-0015", msg, "spacing normalized")
+
+	msg, err = NormalizeCommitMessage("learn: 3 rules", []string{"learn"}, "")
+	require.NoError(t, err, "configured prefixes replace the defaults")
+	assert.Equal(t, "learn: 3 rules", msg)
+}
+
+func TestNormalizeCommitMessage_NonConforming(t *testing.T) {
+	_, err := NormalizeCommitMessage("rules: github", nil, "import")
+	require.ErrorIs(t, err, ErrCommitPrefix, "a disallowed prefix is not replaced")
+	assert.Contains(t, err.Error(), `"rules"`)
+
+	_, err = NormalizeCommitMessage("updated things", nil, "ingest")
+	require.ErrorIs(t, err, ErrCommitPrefix, "fallback must itself be allowed")
+
+	_, err = NormalizeCommitMessage("import: ", nil, "")
+	require.Error(t, err, "no subject")
+	_, err = NormalizeCommitMessage("  ", nil, "import")
+	require.Error(t, err)
+}
+
+func TestNormalizeCommitMessage_AutoPrefix(t *testing.T) {
+	msg, err := NormalizeCommitMessage("Updated 5 transaction categories", nil, "categorize")
+	require.NoError(t, err)
+	assert.Equal(t, "categorize: Updated 5 transaction categories", msg)
+
+	msg, err = NormalizeCommitMessage("Note: check amounts", nil, "correct")
+	require.NoError(t, err, "a capitalized word is not a prefix")
+	assert.Equal(t, "correct: Note: check amounts", msg)
+}
+
+func TestValidPrefix(t *testing.T) {
+	assert.True(t, ValidPrefix("import"))
+	assert.True(t, ValidPrefix("month-end_2"))
+	assert.False(t, ValidPrefix("Import"))
+	assert.False(t, ValidPrefix("two words"))
+	assert.False(t, ValidPrefix(""))
+}
+
+func TestCommitPrefix(t *testing.T) {
+	assert.Equal(t, "import", CommitPrefix("import: 6 transactions from 1 files\n\ndetails"))
+	assert.Equal(t, "categorize", CommitPrefix("categorize:github"))
+	assert.Empty(t, CommitPrefix(`Revert "import: 6 transactions"`))
+}
@@ -2,21 +2,39 @@ package id
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 )
 
-// FormatEntryID returns an entry ID like "2025-01-001".
+// SeqWidth is the zero-padded width of the sequence in new entry IDs, so IDs
+// sort correctly as strings up to 9999 entries a month. IDs written with the
+// older 3-digit width still parse.
+const SeqWidth = 4
+
+// FormatEntryID returns an entry ID like "2025-01-0001".
 func FormatEntryID(year, month, seq int) string {
-	return fmt.Sprintf("%04d-%02d-%03d", year, month, seq)
+	return fmt.Sprintf("%04d-%02d-%0*d", year, month, SeqWidth, seq)
 }
 
-// FormatLegID returns a leg ID like "2025-01-001a" (leg 0='a', 1='b', etc.).
+// FormatLegID returns a leg ID like "2025-01-0001a" (leg 0='a', 1='b', etc.).
+// Suffixes roll over like spreadsheet columns: leg 25='z', 26='aa', 27='ab'.
 func FormatLegID(entryID string, leg int) string {
-	return entryID + string(rune('a'+leg))
+	return entryID + legSuffix(leg)
+}
+
+// legSuffix converts a zero-based leg index to bijective base-26 letters.
+func legSuffix(leg int) string {
+	var buf []byte
+	for n := leg + 1; n > 0; n = (n - 1) / 26 {
+		buf = append(buf, byte('a'+(n-1)%26))
+	}
+	slices.Reverse(buf)
+	return string(buf)
 }
 
-// ParseEntryID parses "2025-01-001" into year, month, seq.
+// ParseEntryID parses "2025-01-0001" (or the older "2025-01-001") into
+// year, month, seq.
 func ParseEntryID(id string) (year, month, seq int, err error) {
 	// Strip any leg suffix (trailing lowercase letters).
 	base := EntryGroup(id)
@@ -45,7 +63,7 @@ func ParseEntryID(id string) (year, month, seq int, err error) {
 }
 
 // EntryGroup strips the leg suffix from a leg ID.
-// "2025-01-001a" -> "2025-01-001"
+// "2025-01-0001a" -> "2025-01-0001"
 func EntryGroup(legID string) string {
 	if len(legID) == 0 {
 		return ""
@@ -12,9 +12,10 @@ func TestFormatEntryID(t *testing.T) {
 		year, month, seq int
 		want             string
 	}{
-		{2025, 1, 1, "2025-01-001"},
-		{2025, 12, 99, "2025-12-099"},
-		{2025, 1, 123, "2025-01-123"},
+		{2025, 1, 1, "2025-01-0001"},
+		{2025, 12, 99, "2025-12-0099"},
+		{2025, 1, 123, "2025-01-0123"},
+		{2025, 1, 1000, "2025-01-1000"},
 	}
 	for _, tt := range tests {
 		got := FormatEntryID(tt.year, tt.month, tt.seq)
@@ -31,6 +32,13 @@ func TestFormatLegID(t *testing.T) {
 		{"2025-01-001", 0, "2025-01-001a"},
 		{"2025-01-001", 1, "2025-01-001b"},
 		{"2025-01-001", 2, "2025-01-001c"},
+		{"2025-01-001", 25, "2025-01-001z"},
+		{"2025-01-001", 26, "2025-01-001aa"},
+		{"2025-01-001", 27, "2025-01-001ab"},
+		{"2025-01-001", 51, "2025-01-001az"},
+		{"2025-01-001", 52, "2025-01-001ba"},
+		{"2025-01-001", 701, "2025-01-001zz"},
+		{"2025-01-001", 702, "2025-01-001aaa"},
 	}
 	for _, tt := range tests {
 		got := FormatLegID(tt.entryID, tt.leg)
@@ -48,6 +56,9 @@ func TestParseEntryID(t *testing.T) {
 		{"2025-12-099", 2025, 12, 99},
 		{"2025-01-001a", 2025, 1, 1},
 		{"2025-01-001b", 2025, 1, 1},
+		{"2025-01-0001", 2025, 1, 1},
+		{"2025-01-1000a", 2025, 1, 1000},
+		{"2025-01-0007ab", 2025, 1, 7},
 	}
 	for _, tt := range tests {
 		year, month, seq, err := ParseEntryID(tt.input)
@@ -58,6 +69,22 @@ func TestParseEntryID(t *testing.T) {
 	}
 }
 
+func TestEntryID_RoundTrip(t *testing.T) {
+	for _, seq := range []int{1, 999, 1000, 9999} {
+		entryID := FormatEntryID(2025, 3, seq)
+		assert.Len(t, entryID, 12, "fixed width for seq %d", seq)
+
+		year, month, got, err := ParseEntryID(FormatLegID(entryID, 1))
+		require.NoError(t, err)
+		assert.Equal(t, 2025, year)
+		assert.Equal(t, 3, month)
+		assert.Equal(t, seq, got)
+	}
+
+	// Fixed width keeps string order equal to numeric order.
+	assert.Less(t, FormatEntryID(2025, 1, 999), FormatEntryID(2025, 1, 1000))
+}
+
 func TestParseEntryID_Errors(t *testing.T) {
 	badInputs := []string{
 		"",
@@ -79,6 +106,8 @@ func TestEntryGroup(t *testing.T) {
 		{"2025-01-001a", "2025-01-001"},
 		{"2025-01-001b", "2025-01-001"},
 		{"2025-01-001", "2025-01-001"},
+		{"2025-01-001aa", "2025-01-001"},
+		{"2025-01-0001zz", "2025-01-0001"},
 		{"", ""},
 	}
 	for _, tt := range tests {
@@ -0,0 +1,149 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// AmexParser parses American Express card CSV exports.
+//
+// Amex reports charges as positive amounts and payments/credits as negative,
+// the opposite of a checking account, so the sign is flipped on import. The
+// basic export is Date,Description,Amount; the extended export adds columns
+// such as Card Member, Extended Details, and Reference, so columns are
+// located by header name.
+type AmexParser struct{}
+
+const amexDateFormat = "01/02/2006"
+
+// amexColumns holds resolved column indexes; -1 means absent.
+type amexColumns struct {
+	date, desc, amount, member, details, ref int
+}
+
+// Format returns the parser name.
+func (p *AmexParser) Format() string { return "amex" }
+
+// Matches reports whether header is an Amex export: the bare three-column
+// layout, or any layout with the Amex-specific Card Member column.
+func (p *AmexParser) Matches(header []string) bool {
+	cols, err := resolveAmexColumns(header)
+	if err != nil {
+		return false
+	}
+	return len(header) == 3 || cols.member >= 0
+}
+
+// Parse reads an Amex CSV and returns BankTransactions.
+func (p *AmexParser) Parse(r io.Reader) ([]model.BankTransaction, error) {
+	return collect(r, p.ParseStream)
+}
+
+// ParseStream reads an Amex CSV row by row, calling fn for each transaction.
+func (p *AmexParser) ParseStream(r io.Reader, fn func(model.BankTransaction) error) error {
+	cr := csv.NewReader(skipBOM(r))
+	cr.ReuseRecord = true
+
+	var cols amexColumns
+	for row := 1; ; row++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading amex CSV: %w", err)
+		}
+
+		if row == 1 {
+			if cols, err = resolveAmexColumns(rec); err != nil {
+				return err
+			}
+			continue
+		}
+
+		txn, err := parseAmexRow(rec, cols)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", row, err)
+		}
+		if err := fn(txn); err != nil {
+			return err
+		}
+	}
+}
+
+func resolveAmexColumns(header []string) (amexColumns, error) {
+	cols := amexColumns{date: -1, desc: -1, amount: -1, member: -1, details: -1, ref: -1}
+	for i, h := range header {
+		switch strings.TrimSpace(h) {
+		case "Date":
+			cols.date = i
+		case "Description":
+			cols.desc = i
+		case "Amount":
+			cols.amount = i
+		case "Card Member":
+			cols.member = i
+		case "Extended Details":
+			cols.details = i
+		case "Reference":
+			cols.ref = i
+		}
+	}
+	if cols.date < 0 || cols.desc < 0 || cols.amount < 0 {
+		return cols, errors.New("amex CSV: header must include Date, Description, and Amount")
+	}
+	return cols, nil
+}
+
+func parseAmexRow(rec []string, cols amexColumns) (model.BankTransaction, error) {
+	date, err := time.Parse(amexDateFormat, rec[cols.date])
+	if err != nil {
+		return model.BankTransaction{}, fmt.Errorf("parsing date %q: %w", rec[cols.date], err)
+	}
+
+	amount, err := parseMoney(rec[cols.amount])
+	if err != nil {
+		return model.BankTransaction{}, err
+	}
+
+	desc := rec[cols.desc]
+	ref := ""
+	if cols.ref >= 0 {
+		// Amex prefixes the reference with an apostrophe to stop spreadsheets
+		// from rendering it in scientific notation.
+		ref = strings.Trim(rec[cols.ref], "' ")
+	}
+	if ref == "" {
+		ref = makeReference("amex", date, desc)
+	}
+
+	return model.BankTransaction{
+		Date:        date,
+		Description: amexDescription(rec, cols),
+		Amount:      amount.Neg(),
+		Reference:   ref,
+	}, nil
+}
+
+// amexDescription joins the description with the extended details and card
+// member, when present, so that information reaches the categorizer.
+func amexDescription(rec []string, cols amexColumns) string {
+	parts := []string{rec[cols.desc]}
+	if cols.details >= 0 {
+		if details := strings.Join(strings.Fields(rec[cols.details]), " "); details != "" {
+			parts = append(parts, details)
+		}
+	}
+	if cols.member >= 0 {
+		if member := strings.TrimSpace(rec[cols.member]); member != "" {
+			parts = append(parts, "card member: "+member)
+		}
+	}
+	return strings.Join(parts, " | ")
+}
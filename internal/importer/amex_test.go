@@ -0,0 +1,75 @@
+package importer
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmexParser_Parse(t *testing.T) {
+	f, err := os.Open("../../testdata/amex.csv")
+	require.NoError(t, err)
+	defer f.Close()
+
+	p := &AmexParser{}
+	txns, err := p.Parse(f)
+	require.NoError(t, err)
+	require.Len(t, txns, 4)
+
+	assert.Equal(t, "AMAZON WEB SERVICES | AWS.AMAZON.COM Cloud services | card member: JANE DOE", txns[0].Description)
+	assert.Equal(t, "320250040123456789", txns[0].Reference, "leading apostrophe stripped")
+	assert.Equal(t, 4, txns[0].Date.Day())
+
+	assert.Equal(t, "UNITED AIRLINES | card member: JANE DOE", txns[1].Description)
+	assert.Equal(t, "amex_20250120_STAPLES001", txns[3].Reference, "generated when Reference is blank")
+}
+
+func TestAmexParser_SignConvention(t *testing.T) {
+	f, err := os.Open("../../testdata/amex.csv")
+	require.NoError(t, err)
+	defer f.Close()
+
+	p := &AmexParser{}
+	txns, err := p.Parse(f)
+	require.NoError(t, err)
+
+	// Charges are positive in the export and must come through negative.
+	assert.Equal(t, "-127.50", txns[0].Amount.StringFixed(2))
+	assert.Equal(t, "-1250.00", txns[1].Amount.StringFixed(2))
+	// Payments are negative in the export and must come through positive.
+	assert.Equal(t, "500.00", txns[2].Amount.StringFixed(2))
+}
+
+func TestAmexParser_BasicLayout(t *testing.T) {
+	csv := "Date,Description,Amount\n01/04/2025,AMAZON WEB SERVICES,127.50\n"
+	p := &AmexParser{}
+	txns, err := p.Parse(strings.NewReader(csv))
+	require.NoError(t, err)
+	require.Len(t, txns, 1)
+	assert.Equal(t, "AMAZON WEB SERVICES", txns[0].Description)
+	assert.Equal(t, "-127.50", txns[0].Amount.StringFixed(2))
+	assert.Equal(t, "amex_20250104_AMAZONWEBS", txns[0].Reference)
+}
+
+func TestAmexParser_Matches(t *testing.T) {
+	p := &AmexParser{}
+	assert.True(t, p.Matches([]string{"Date", "Description", "Amount"}))
+	assert.True(t, p.Matches([]string{"Date", "Description", "Card Member", "Account #", "Amount"}))
+	assert.False(t, p.Matches([]string{"Date", "Description", "Amount", "Running Bal."}))
+	assert.False(t, p.Matches([]string{"Details", "Posting Date", "Description", "Amount", "Type", "Balance", "Check or Slip #"}))
+}
+
+func TestAmexParser_MissingColumn(t *testing.T) {
+	p := &AmexParser{}
+	_, err := p.Parse(strings.NewReader("Date,Description\n01/04/2025,X\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must include")
+}
+
+func TestAmexParser_Format(t *testing.T) {
+	p := &AmexParser{}
+	assert.Equal(t, "amex", p.Format())
+}
@@ -0,0 +1,76 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/shopspring/decimal"
+)
+
+// parseMoney parses an amount as banks write it: currency symbols and
+// thousands separators are ignored, "(45.00)" is negative, and a trailing
+// "DR" marks a debit (negative) while "CR" marks a credit (positive).
+func parseMoney(s string) (decimal.Decimal, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+	neg := false
+
+	if upper := strings.ToUpper(s); strings.HasSuffix(upper, "DR") {
+		s, neg = s[:len(s)-2], true
+	} else if strings.HasSuffix(upper, "CR") {
+		s = s[:len(s)-2]
+	}
+	s = strings.TrimSpace(s)
+
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		s, neg = s[1:len(s)-1], true
+	}
+
+	s = strings.Map(func(r rune) rune {
+		if r == ',' || unicode.IsSpace(r) || unicode.Is(unicode.Sc, r) {
+			return -1
+		}
+		return r
+	}, s)
+
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("parsing amount %q: %w", orig, err)
+	}
+	if neg {
+		d = d.Abs().Neg()
+	}
+	return d, nil
+}
+
+// parseOptionalMoney is parseMoney for cells that may be blank, such as
+// one side of a debit/credit column pair. Blank parses as zero.
+func parseOptionalMoney(s string) (decimal.Decimal, error) {
+	if strings.TrimSpace(s) == "" {
+		return decimal.Zero, nil
+	}
+	return parseMoney(s)
+}
+
+// signedAmount combines separate debit and credit cells into a single signed
+// amount: debits (money out) become negative, credits (money in) positive.
+// Banks that split the columns populate exactly one per row; a row with both
+// populated is ambiguous and rejected.
+func signedAmount(debitCell, creditCell string) (decimal.Decimal, error) {
+	debit, err := parseOptionalMoney(debitCell)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("debit: %w", err)
+	}
+	credit, err := parseOptionalMoney(creditCell)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("credit: %w", err)
+	}
+	if !debit.IsZero() && !credit.IsZero() {
+		return decimal.Zero, fmt.Errorf("both debit %q and credit %q are populated", debitCell, creditCell)
+	}
+	if !debit.IsZero() {
+		return debit.Abs().Neg(), nil
+	}
+	return credit.Abs(), nil
+}
@@ -0,0 +1,74 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"-4.00", "-4.00"},
+		{"4.00", "4.00"},
+		{"1,234.56", "1234.56"},
+		{"$1,234.56", "1234.56"},
+		{"-$1,234.56", "-1234.56"},
+		{"$-4.00", "-4.00"},
+		{"€4,500.10", "4500.10"},
+		{"(45.00)", "-45.00"},
+		{"($1,045.00)", "-1045.00"},
+		{"45.00 CR", "45.00"},
+		{"45.00CR", "45.00"},
+		{"45.00 DR", "-45.00"},
+		{"45.00dr", "-45.00"},
+		{"  12.5  ", "12.50"},
+	}
+	for _, tt := range tests {
+		got, err := parseMoney(tt.input)
+		require.NoError(t, err, "input %q", tt.input)
+		assert.Equal(t, tt.want, got.StringFixed(2), "input %q", tt.input)
+	}
+}
+
+func TestParseMoney_Invalid(t *testing.T) {
+	for _, input := range []string{"", "abc", "$", "()", "4.00 XX"} {
+		_, err := parseMoney(input)
+		require.Error(t, err, "input %q", input)
+		assert.Contains(t, err.Error(), "parsing amount")
+	}
+}
+
+func TestSignedAmount(t *testing.T) {
+	tests := []struct {
+		debit, credit string
+		want          string
+	}{
+		{"4.00", "", "-4.00"},
+		{"", "3500.00", "3500.00"},
+		{"1,234.56", "", "-1234.56"},
+		{"", "$12.00", "12.00"},
+		{"-4.00", "", "-4.00"}, // some banks sign the debit column
+		{"", "", "0.00"},
+	}
+	for _, tt := range tests {
+		got, err := signedAmount(tt.debit, tt.credit)
+		require.NoError(t, err, "debit=%q credit=%q", tt.debit, tt.credit)
+		assert.Equal(t, tt.want, got.StringFixed(2), "debit=%q credit=%q", tt.debit, tt.credit)
+	}
+}
+
+func TestSignedAmount_BothPopulated(t *testing.T) {
+	_, err := signedAmount("4.00", "4.00")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "both debit")
+}
+
+func TestSignedAmount_BadNumber(t *testing.T) {
+	_, err := signedAmount("", "abc")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "credit")
+}
@@ -0,0 +1,114 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// BofAParser parses Bank of America checking CSV exports.
+//
+// BoA exports begin with a few summary rows (beginning balance, totals,
+// ending balance) followed by a blank line and the real header:
+// Date,Description,Amount,Running Bal.
+type BofAParser struct{}
+
+const (
+	boaDateFormat = "01/02/2006"
+	boaNumFields  = 4
+	boaColDate    = 0
+	boaColDesc    = 1
+	boaColAmount  = 2
+	boaColBalance = 3
+)
+
+// Format returns the parser name.
+func (p *BofAParser) Format() string { return "boa" }
+
+// Matches reports whether header is either the BoA summary preamble or the
+// transaction header itself (when the user trimmed the summary).
+func (p *BofAParser) Matches(header []string) bool {
+	if len(header) == 3 && strings.TrimSpace(header[0]) == "Description" && strings.TrimSpace(header[2]) == "Summary Amt." {
+		return true
+	}
+	return isBofAHeader(header) && strings.TrimSpace(header[boaColBalance]) == "Running Bal."
+}
+
+// Parse reads a BoA CSV and returns BankTransactions.
+func (p *BofAParser) Parse(r io.Reader) ([]model.BankTransaction, error) {
+	return collect(r, p.ParseStream)
+}
+
+// ParseStream reads a BoA CSV row by row, skipping the summary preamble,
+// and calls fn for each transaction.
+func (p *BofAParser) ParseStream(r io.Reader, fn func(model.BankTransaction) error) error {
+	cr := csv.NewReader(skipBOM(r))
+	cr.FieldsPerRecord = -1 // summary rows have a different width
+	cr.ReuseRecord = true
+
+	inBody := false
+	for row := 1; ; row++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			if !inBody && row > 1 {
+				return errors.New("boa CSV: transaction header not found")
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading boa CSV: %w", err)
+		}
+
+		if !inBody {
+			inBody = isBofAHeader(rec)
+			continue
+		}
+
+		if len(rec) != boaNumFields {
+			return fmt.Errorf("row %d: expected %d fields, got %d", row, boaNumFields, len(rec))
+		}
+		// The first data row is the opening balance and carries no amount.
+		if strings.TrimSpace(rec[boaColAmount]) == "" {
+			continue
+		}
+		txn, err := parseBofARow(rec)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", row, err)
+		}
+		if err := fn(txn); err != nil {
+			return err
+		}
+	}
+}
+
+func isBofAHeader(rec []string) bool {
+	return len(rec) == boaNumFields &&
+		strings.TrimSpace(rec[boaColDate]) == "Date" &&
+		strings.TrimSpace(rec[boaColDesc]) == "Description" &&
+		strings.TrimSpace(rec[boaColAmount]) == "Amount"
+}
+
+func parseBofARow(rec []string) (model.BankTransaction, error) {
+	date, err := time.Parse(boaDateFormat, rec[boaColDate])
+	if err != nil {
+		return model.BankTransaction{}, fmt.Errorf("parsing date %q: %w", rec[boaColDate], err)
+	}
+
+	amount, err := parseMoney(rec[boaColAmount])
+	if err != nil {
+		return model.BankTransaction{}, err
+	}
+
+	desc := rec[boaColDesc]
+	return model.BankTransaction{
+		Date:        date,
+		Description: desc,
+		Amount:      amount,
+		Reference:   makeReference("boa", date, desc),
+	}, nil
+}
@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBofAParser_Parse(t *testing.T) {
+	f, err := os.Open("../../testdata/boa_checking.csv")
+	require.NoError(t, err)
+	defer f.Close()
+
+	p := &BofAParser{}
+	txns, err := p.Parse(f)
+	require.NoError(t, err)
+	require.Len(t, txns, 6, "summary rows and opening balance should be skipped")
+
+	assert.Equal(t, "GITHUB *PRO SUBSCRIPTION", txns[0].Description)
+	assert.Equal(t, "-4.00", txns[0].Amount.StringFixed(2))
+	assert.Equal(t, 2025, txns[0].Date.Year())
+	assert.Equal(t, 1, int(txns[0].Date.Month()))
+	assert.Equal(t, 3, txns[0].Date.Day())
+	assert.Equal(t, "boa_20250103_GITHUBPROS", txns[0].Reference)
+}
+
+func TestBofAParser_ThousandsSeparator(t *testing.T) {
+	f, err := os.Open("../../testdata/boa_checking.csv")
+	require.NoError(t, err)
+	defer f.Close()
+
+	p := &BofAParser{}
+	txns, err := p.Parse(f)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ACME CONSULTING INVOICE 1042", txns[3].Description)
+	assert.Equal(t, "3500.00", txns[3].Amount.StringFixed(2))
+}
+
+func TestBofAParser_NoSummaryRows(t *testing.T) {
+	csv := "Date,Description,Amount,Running Bal.\n01/03/2025,GITHUB,-4.00,100.00\n"
+	p := &BofAParser{}
+	txns, err := p.Parse(strings.NewReader(csv))
+	require.NoError(t, err)
+	require.Len(t, txns, 1)
+	assert.Equal(t, "GITHUB", txns[0].Description)
+}
+
+func TestBofAParser_Empty(t *testing.T) {
+	p := &BofAParser{}
+	txns, err := p.Parse(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Nil(t, txns)
+}
+
+func TestBofAParser_MissingHeader(t *testing.T) {
+	csv := "Description,,Summary Amt.\nTotal credits,,\"3,500.00\"\n"
+	p := &BofAParser{}
+	_, err := p.Parse(strings.NewReader(csv))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "header not found")
+}
+
+func TestBofAParser_BadAmount(t *testing.T) {
+	csv := "Date,Description,Amount,Running Bal.\n01/03/2025,desc,NOTANUMBER,100.00\n"
+	p := &BofAParser{}
+	_, err := p.Parse(strings.NewReader(csv))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parsing amount")
+}
+
+func TestBofAParser_Format(t *testing.T) {
+	p := &BofAParser{}
+	assert.Equal(t, "boa", p.Format())
+}
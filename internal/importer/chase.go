@@ -2,13 +2,12 @@ package importer
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
 	"time"
 
-	"github.com/shopspring/decimal"
-
 	"github.com/cleared-dev/cleared/internal/model"
 )
 
@@ -27,29 +26,44 @@ const (
 // Format returns the parser name.
 func (p *ChaseParser) Format() string { return "chase" }
 
+// Matches reports whether header is a Chase checking export header.
+func (p *ChaseParser) Matches(header []string) bool {
+	return len(header) == chaseNumFields &&
+		strings.TrimSpace(header[0]) == "Details" &&
+		strings.TrimSpace(header[chaseColDate]) == "Posting Date"
+}
+
 // Parse reads a Chase CSV and returns BankTransactions.
 func (p *ChaseParser) Parse(r io.Reader) ([]model.BankTransaction, error) {
-	cr := csv.NewReader(r)
-	cr.FieldsPerRecord = chaseNumFields
+	return collect(r, p.ParseStream)
+}
 
-	records, err := cr.ReadAll()
-	if err != nil {
-		return nil, fmt.Errorf("reading chase CSV: %w", err)
-	}
+// ParseStream reads a Chase CSV row by row, calling fn for each transaction.
+func (p *ChaseParser) ParseStream(r io.Reader, fn func(model.BankTransaction) error) error {
+	cr := csv.NewReader(skipBOM(r))
+	cr.FieldsPerRecord = chaseNumFields
+	cr.ReuseRecord = true
 
-	if len(records) <= 1 {
-		return nil, nil
-	}
+	for row := 1; ; row++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading chase CSV: %w", err)
+		}
+		if row == 1 {
+			continue // header
+		}
 
-	var txns []model.BankTransaction
-	for i, rec := range records[1:] {
 		txn, err := parseChaseRow(rec)
 		if err != nil {
-			return nil, fmt.Errorf("row %d: %w", i+2, err)
+			return fmt.Errorf("row %d: %w", row, err)
+		}
+		if err := fn(txn); err != nil {
+			return err
 		}
-		txns = append(txns, txn)
 	}
-	return txns, nil
 }
 
 func parseChaseRow(rec []string) (model.BankTransaction, error) {
@@ -58,13 +72,13 @@ func parseChaseRow(rec []string) (model.BankTransaction, error) {
 		return model.BankTransaction{}, fmt.Errorf("parsing date %q: %w", rec[chaseColDate], err)
 	}
 
-	amount, err := decimal.NewFromString(rec[chaseColAmount])
+	amount, err := parseMoney(rec[chaseColAmount])
 	if err != nil {
-		return model.BankTransaction{}, fmt.Errorf("parsing amount %q: %w", rec[chaseColAmount], err)
+		return model.BankTransaction{}, err
 	}
 
 	desc := rec[chaseColDesc]
-	ref := makeChaseRef(date, desc)
+	ref := makeReference("chase", date, desc)
 
 	return model.BankTransaction{
 		Date:        date,
@@ -75,8 +89,9 @@ func parseChaseRow(rec []string) (model.BankTransaction, error) {
 	}, nil
 }
 
-// makeChaseRef creates a reference like chase_20250103_GITHUB.
-func makeChaseRef(date time.Time, desc string) string {
+// makeReference creates a reference like chase_20250103_GITHUB from a bank
+// prefix, the posting date, and the first 10 alphanumerics of the description.
+func makeReference(bank string, date time.Time, desc string) string {
 	prefix := strings.Map(func(r rune) rune {
 		if r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
 			return r
@@ -86,5 +101,5 @@ func makeChaseRef(date time.Time, desc string) string {
 	if len(prefix) > 10 {
 		prefix = prefix[:10]
 	}
-	return fmt.Sprintf("chase_%s_%s", date.Format("20060102"), prefix)
+	return fmt.Sprintf("%s_%s_%s", bank, date.Format("20060102"), prefix)
 }
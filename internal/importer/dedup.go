@@ -0,0 +1,86 @@
+package importer
+
+import (
+	"fmt"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// MonthReader reads the journal legs booked in a month. journal.Service
+// satisfies it.
+type MonthReader interface {
+	ReadMonth(year, month int) ([]model.Leg, error)
+}
+
+// Deduplicate returns txns minus any whose Reference is already booked in
+// the journal for the transaction's month. Transactions without a Reference
+// are always kept since there is nothing reliable to match on.
+func Deduplicate(txns []model.BankTransaction, journal MonthReader) ([]model.BankTransaction, error) {
+	booked := make(map[[2]int]map[string]bool)
+
+	var result []model.BankTransaction
+	for _, txn := range txns {
+		if txn.Reference == "" {
+			result = append(result, txn)
+			continue
+		}
+
+		key := [2]int{txn.Date.Year(), int(txn.Date.Month())}
+		refs, ok := booked[key]
+		if !ok {
+			legs, err := journal.ReadMonth(key[0], key[1])
+			if err != nil {
+				return nil, fmt.Errorf("reading journal %04d-%02d: %w", key[0], key[1], err)
+			}
+			refs = make(map[string]bool, len(legs))
+			for _, leg := range legs {
+				if leg.Reference != "" {
+					refs[leg.Reference] = true
+				}
+			}
+			booked[key] = refs
+		}
+
+		if !refs[txn.Reference] {
+			result = append(result, txn)
+		}
+	}
+	return result, nil
+}
+
+// Duplicate records a transaction DedupeWithin dropped.
+type Duplicate struct {
+	Index       int // position in the input slice
+	FirstIndex  int // position of the transaction it duplicates
+	Transaction model.BankTransaction
+}
+
+// DedupeWithin collapses repeated transactions inside a single import, a
+// known bank export glitch. Two transactions are duplicates when they share
+// a Reference and an Amount; the amount check keeps generated references
+// (date + description prefix) from merging distinct same-day purchases.
+// The first occurrence is kept.
+func DedupeWithin(txns []model.BankTransaction) ([]model.BankTransaction, []Duplicate) {
+	type key struct {
+		ref    string
+		amount string
+	}
+	first := make(map[key]int, len(txns))
+
+	var kept []model.BankTransaction
+	var dups []Duplicate
+	for i, txn := range txns {
+		if txn.Reference == "" {
+			kept = append(kept, txn)
+			continue
+		}
+		k := key{ref: txn.Reference, amount: txn.Amount.String()}
+		if j, seen := first[k]; seen {
+			dups = append(dups, Duplicate{Index: i, FirstIndex: j, Transaction: txn})
+			continue
+		}
+		first[k] = i
+		kept = append(kept, txn)
+	}
+	return kept, dups
+}
@@ -0,0 +1,117 @@
+package importer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cleared-dev/cleared/internal/accounts"
+	"github.com/cleared-dev/cleared/internal/journal"
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+func parseChaseTestdata(t *testing.T) []model.BankTransaction {
+	t.Helper()
+	f, err := os.Open("../../testdata/chase_checking.csv")
+	require.NoError(t, err)
+	defer f.Close()
+
+	txns, err := (&ChaseParser{}).Parse(f)
+	require.NoError(t, err)
+	return txns
+}
+
+func bookAll(t *testing.T, svc *journal.Service, txns []model.BankTransaction) {
+	t.Helper()
+	for _, txn := range txns {
+		debit, credit := 5030, 1010
+		if txn.Amount.IsPositive() {
+			debit, credit = 1010, 4010
+		}
+		_, err := svc.AddDouble(journal.AddDoubleParams{
+			Date:          txn.Date,
+			Description:   txn.Description,
+			DebitAccount:  debit,
+			CreditAccount: credit,
+			Amount:        txn.Amount.Abs(),
+			Reference:     txn.Reference,
+			Status:        model.StatusPendingReview,
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestDeduplicate_SecondImportIsEmpty(t *testing.T) {
+	dir := t.TempDir()
+	svc := journal.NewService(dir, accounts.NewService(accounts.DefaultChart("llc_single_member")))
+
+	first, err := Deduplicate(parseChaseTestdata(t), svc)
+	require.NoError(t, err)
+	require.Len(t, first, 6, "nothing booked yet")
+	bookAll(t, svc, first)
+
+	second, err := Deduplicate(parseChaseTestdata(t), svc)
+	require.NoError(t, err)
+	assert.Empty(t, second, "re-importing the same file should yield nothing")
+}
+
+func TestDeduplicate_PartialOverlap(t *testing.T) {
+	dir := t.TempDir()
+	svc := journal.NewService(dir, accounts.NewService(accounts.DefaultChart("llc_single_member")))
+
+	txns := parseChaseTestdata(t)
+	bookAll(t, svc, txns[:2])
+
+	got, err := Deduplicate(txns, svc)
+	require.NoError(t, err)
+	require.Len(t, got, 4)
+	assert.Equal(t, txns[2].Reference, got[0].Reference)
+}
+
+func TestDeduplicate_KeepsBlankReference(t *testing.T) {
+	dir := t.TempDir()
+	svc := journal.NewService(dir, accounts.NewService(accounts.DefaultChart("llc_single_member")))
+
+	txns := parseChaseTestdata(t)
+	bookAll(t, svc, txns)
+
+	txns[0].Reference = ""
+	got, err := Deduplicate(txns, svc)
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+}
+
+func TestDedupeWithin(t *testing.T) {
+	txns := parseChaseTestdata(t)
+	// The bank repeated the AWS row.
+	withDup := append(append([]model.BankTransaction{}, txns[:3]...), txns[1])
+	withDup = append(withDup, txns[3:]...)
+
+	kept, dups := DedupeWithin(withDup)
+	require.Len(t, kept, len(txns))
+	require.Len(t, dups, 1)
+	assert.Equal(t, 3, dups[0].Index)
+	assert.Equal(t, 1, dups[0].FirstIndex)
+	assert.Equal(t, txns[1].Reference, dups[0].Transaction.Reference)
+}
+
+func TestDedupeWithin_SameReferenceDifferentAmount(t *testing.T) {
+	txns := []model.BankTransaction{
+		{Reference: "chase_20250103_STARBUCKS", Amount: decimal.RequireFromString("-4.50")},
+		{Reference: "chase_20250103_STARBUCKS", Amount: decimal.RequireFromString("-6.25")},
+	}
+
+	kept, dups := DedupeWithin(txns)
+	assert.Len(t, kept, 2, "distinct purchases sharing a generated reference are kept")
+	assert.Empty(t, dups)
+}
+
+func TestDedupeWithin_NoDuplicates(t *testing.T) {
+	txns := parseChaseTestdata(t)
+	kept, dups := DedupeWithin(txns)
+	assert.Len(t, kept, len(txns))
+	assert.Nil(t, dups)
+}
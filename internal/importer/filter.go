@@ -0,0 +1,23 @@
+package importer
+
+import (
+	"time"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// FilterDateRange returns the transactions dated within [since, until],
+// inclusive on both ends. A zero since or until leaves that side unbounded.
+func FilterDateRange(txns []model.BankTransaction, since, until time.Time) []model.BankTransaction {
+	var result []model.BankTransaction
+	for _, txn := range txns {
+		if !since.IsZero() && txn.Date.Before(since) {
+			continue
+		}
+		if !until.IsZero() && txn.Date.After(until) {
+			continue
+		}
+		result = append(result, txn)
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package importer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+func day(d int) time.Time {
+	return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+func descriptions(txns []model.BankTransaction) []string {
+	var out []string
+	for _, txn := range txns {
+		out = append(out, txn.Description)
+	}
+	return out
+}
+
+func TestFilterDateRange(t *testing.T) {
+	txns := []model.BankTransaction{
+		{Date: day(3), Description: "a"},
+		{Date: day(5), Description: "b"},
+		{Date: day(10), Description: "c"},
+		{Date: day(15), Description: "d"},
+		{Date: day(18), Description: "e"},
+	}
+
+	tests := []struct {
+		name         string
+		since, until time.Time
+		want         []string
+	}{
+		{"inclusive bounds", day(5), day(15), []string{"b", "c", "d"}},
+		{"single boundary day", day(10), day(10), []string{"c"}},
+		{"since only", day(15), time.Time{}, []string{"d", "e"}},
+		{"until only", time.Time{}, day(5), []string{"a", "b"}},
+		{"unbounded", time.Time{}, time.Time{}, []string{"a", "b", "c", "d", "e"}},
+		{"empty window", day(11), day(14), nil},
+	}
+	for _, tt := range tests {
+		got := FilterDateRange(txns, tt.since, tt.until)
+		assert.Equal(t, tt.want, descriptions(got), tt.name)
+	}
+}
@@ -0,0 +1,192 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cleared-dev/cleared/internal/config"
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// ColumnMapping tells a GenericParser which header names hold each field.
+// Either Amount or both Debit and Credit must be set.
+type ColumnMapping struct {
+	Date        string
+	DateLayout  string // Go time layout, e.g. "01/02/2006"
+	Description string
+	Amount      string // signed: negative = expense, positive = income
+	Debit       string // money out, positive number
+	Credit      string // money in, positive number
+	Reference   string // optional; generated from date+description when empty
+	Type        string // optional
+}
+
+// GenericParser parses any CSV whose layout is described by a ColumnMapping.
+type GenericParser struct {
+	name    string
+	mapping ColumnMapping
+}
+
+// NewGenericParser creates a parser registered under name.
+func NewGenericParser(name string, mapping ColumnMapping) *GenericParser {
+	return &GenericParser{name: name, mapping: mapping}
+}
+
+// NewGenericParserFromConfig creates a parser from a cleared.yaml import format.
+func NewGenericParserFromConfig(f config.ImportFormat) *GenericParser {
+	return NewGenericParser(f.Name, ColumnMapping{
+		Date:        f.DateColumn,
+		DateLayout:  f.DateLayout,
+		Description: f.DescriptionColumn,
+		Amount:      f.AmountColumn,
+		Debit:       f.DebitColumn,
+		Credit:      f.CreditColumn,
+		Reference:   f.ReferenceColumn,
+		Type:        f.TypeColumn,
+	})
+}
+
+// Format returns the parser name.
+func (p *GenericParser) Format() string { return p.name }
+
+// genericColumns holds resolved column indexes; -1 means not mapped.
+type genericColumns struct {
+	date, desc, amount, debit, credit, ref, typ int
+}
+
+// Parse reads a CSV and returns BankTransactions.
+func (p *GenericParser) Parse(r io.Reader) ([]model.BankTransaction, error) {
+	return collect(r, p.ParseStream)
+}
+
+// ParseStream reads a CSV row by row, calling fn for each transaction.
+func (p *GenericParser) ParseStream(r io.Reader, fn func(model.BankTransaction) error) error {
+	cr := csv.NewReader(skipBOM(r))
+	cr.ReuseRecord = true
+
+	var cols genericColumns
+	for row := 1; ; row++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s CSV: %w", p.name, err)
+		}
+
+		if row == 1 {
+			if cols, err = p.resolveColumns(rec); err != nil {
+				return err
+			}
+			continue
+		}
+
+		txn, err := p.parseRow(rec, cols)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", row, err)
+		}
+		if err := fn(txn); err != nil {
+			return err
+		}
+	}
+}
+
+// Matches reports whether every mapped column appears in header.
+func (p *GenericParser) Matches(header []string) bool {
+	_, err := p.resolveColumns(header)
+	return err == nil
+}
+
+func (p *GenericParser) resolveColumns(header []string) (genericColumns, error) {
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		index[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	lookup := func(name string, required bool) (int, error) {
+		if name == "" {
+			if required {
+				return -1, errors.New("column mapping is incomplete")
+			}
+			return -1, nil
+		}
+		i, ok := index[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return -1, fmt.Errorf("column %q not found in header", name)
+		}
+		return i, nil
+	}
+
+	var cols genericColumns
+	var err error
+	if cols.date, err = lookup(p.mapping.Date, true); err != nil {
+		return cols, err
+	}
+	if cols.desc, err = lookup(p.mapping.Description, true); err != nil {
+		return cols, err
+	}
+	if cols.ref, err = lookup(p.mapping.Reference, false); err != nil {
+		return cols, err
+	}
+	if cols.typ, err = lookup(p.mapping.Type, false); err != nil {
+		return cols, err
+	}
+
+	if p.mapping.Amount != "" {
+		cols.debit, cols.credit = -1, -1
+		cols.amount, err = lookup(p.mapping.Amount, true)
+		return cols, err
+	}
+
+	cols.amount = -1
+	if p.mapping.Debit == "" || p.mapping.Credit == "" {
+		return cols, errors.New("column mapping needs an amount column or both debit and credit columns")
+	}
+	if cols.debit, err = lookup(p.mapping.Debit, true); err != nil {
+		return cols, err
+	}
+	cols.credit, err = lookup(p.mapping.Credit, true)
+	return cols, err
+}
+
+func (p *GenericParser) parseRow(rec []string, cols genericColumns) (model.BankTransaction, error) {
+	date, err := time.Parse(p.mapping.DateLayout, strings.TrimSpace(rec[cols.date]))
+	if err != nil {
+		return model.BankTransaction{}, fmt.Errorf("parsing date %q: %w", rec[cols.date], err)
+	}
+
+	var amount decimal.Decimal
+	if cols.amount >= 0 {
+		amount, err = parseMoney(rec[cols.amount])
+		if err != nil {
+			return model.BankTransaction{}, err
+		}
+	} else {
+		amount, err = signedAmount(rec[cols.debit], rec[cols.credit])
+		if err != nil {
+			return model.BankTransaction{}, err
+		}
+	}
+
+	desc := rec[cols.desc]
+	txn := model.BankTransaction{
+		Date:        date,
+		Description: desc,
+		Amount:      amount,
+	}
+	if cols.ref >= 0 && rec[cols.ref] != "" {
+		txn.Reference = rec[cols.ref]
+	} else {
+		txn.Reference = makeReference(p.name, date, desc)
+	}
+	if cols.typ >= 0 {
+		txn.Type = rec[cols.typ]
+	}
+	return txn, nil
+}
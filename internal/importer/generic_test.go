@@ -0,0 +1,131 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cleared-dev/cleared/internal/config"
+)
+
+func TestGenericParser_HeaderLookup(t *testing.T) {
+	// Columns are out of the order Chase uses and carry extra whitespace/case.
+	csv := "Memo, Amount ,Posted On,Ref\nGITHUB *PRO,-4.00,2025-01-03,abc123\nACME INVOICE,\"3,500.00\",2025-01-15,\n"
+	p := NewGenericParser("mybank", ColumnMapping{
+		Date:        "posted on",
+		DateLayout:  "2006-01-02",
+		Description: "Memo",
+		Amount:      "amount",
+		Reference:   "Ref",
+	})
+
+	txns, err := p.Parse(strings.NewReader(csv))
+	require.NoError(t, err)
+	require.Len(t, txns, 2)
+
+	assert.Equal(t, "GITHUB *PRO", txns[0].Description)
+	assert.Equal(t, "-4.00", txns[0].Amount.StringFixed(2))
+	assert.Equal(t, 3, txns[0].Date.Day())
+	assert.Equal(t, "abc123", txns[0].Reference)
+
+	assert.Equal(t, "3500.00", txns[1].Amount.StringFixed(2))
+	assert.Equal(t, "mybank_20250115_ACMEINVOIC", txns[1].Reference, "blank reference falls back to generated")
+}
+
+func TestGenericParser_MissingColumn(t *testing.T) {
+	csv := "Date,Description\n01/03/2025,GITHUB\n"
+	p := NewGenericParser("mybank", ColumnMapping{
+		Date:        "Date",
+		DateLayout:  "01/02/2006",
+		Description: "Description",
+		Amount:      "Amount",
+	})
+
+	_, err := p.Parse(strings.NewReader(csv))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `column "Amount" not found`)
+}
+
+func TestGenericParser_IncompleteMapping(t *testing.T) {
+	csv := "Date,Description,Debit\n01/03/2025,GITHUB,4.00\n"
+	p := NewGenericParser("mybank", ColumnMapping{
+		Date:        "Date",
+		DateLayout:  "01/02/2006",
+		Description: "Description",
+		Debit:       "Debit",
+	})
+
+	_, err := p.Parse(strings.NewReader(csv))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "both debit and credit")
+}
+
+func TestGenericParser_DebitCreditColumns(t *testing.T) {
+	csv := "Date,Description,Debit,Credit\n01/03/2025,GITHUB,4.00,\n01/15/2025,ACME,,3500.00\n"
+	p := NewGenericParser("cu", ColumnMapping{
+		Date:        "Date",
+		DateLayout:  "01/02/2006",
+		Description: "Description",
+		Debit:       "Debit",
+		Credit:      "Credit",
+	})
+
+	txns, err := p.Parse(strings.NewReader(csv))
+	require.NoError(t, err)
+	require.Len(t, txns, 2)
+	assert.Equal(t, "-4.00", txns[0].Amount.StringFixed(2))
+	assert.Equal(t, "3500.00", txns[1].Amount.StringFixed(2))
+}
+
+func TestGenericParser_DebitAndCreditPopulated(t *testing.T) {
+	csv := "Date,Description,Debit,Credit\n01/03/2025,GITHUB,4.00,4.00\n"
+	p := NewGenericParser("cu", ColumnMapping{
+		Date:        "Date",
+		DateLayout:  "01/02/2006",
+		Description: "Description",
+		Debit:       "Debit",
+		Credit:      "Credit",
+	})
+
+	_, err := p.Parse(strings.NewReader(csv))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "row 2")
+	assert.Contains(t, err.Error(), "both debit")
+}
+
+func TestGenericParser_BadDate(t *testing.T) {
+	csv := "Date,Description,Amount\nNOTADATE,desc,-4.00\n"
+	p := NewGenericParser("mybank", ColumnMapping{
+		Date:        "Date",
+		DateLayout:  "01/02/2006",
+		Description: "Description",
+		Amount:      "Amount",
+	})
+
+	_, err := p.Parse(strings.NewReader(csv))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parsing date")
+}
+
+func TestDefaultRegistry_ConfigFormats(t *testing.T) {
+	r := defaultRegistry(t, config.ImportFormat{
+		Name:              "MyCreditUnion",
+		DateColumn:        "Date",
+		DateLayout:        "01/02/2006",
+		DescriptionColumn: "Description",
+		DebitColumn:       "Debit",
+		CreditColumn:      "Credit",
+	})
+
+	p := r.Get("mycreditunion")
+	require.NotNil(t, p)
+	assert.Equal(t, "MyCreditUnion", p.Format())
+	assert.NotNil(t, r.Get("chase"), "built-ins remain registered")
+
+	_, err := DefaultRegistry(config.ImportFormat{Name: "Chase", DateColumn: "Date"})
+	require.EqualError(t, err, "import_formats: duplicate parser format: chase", "user config cannot replace a built-in")
+	_, err = DefaultRegistry(config.ImportFormat{DateColumn: "Date"})
+	require.EqualError(t, err, "import_formats: parser format name is empty")
+}
@@ -1,12 +1,20 @@
 package importer
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/cleared-dev/cleared/internal/config"
 	"github.com/cleared-dev/cleared/internal/model"
 )
 
@@ -14,14 +22,22 @@ import (
 type Parser interface {
 	Parse(r io.Reader) ([]model.BankTransaction, error)
 	Format() string
+	// ParseStream reads transactions one at a time, calling fn for each
+	// instead of buffering the whole file. An error from fn stops parsing.
+	ParseStream(r io.Reader, fn func(model.BankTransaction) error) error
+	// Matches reports whether the first line of a file, split as CSV,
+	// looks like this parser's format.
+	Matches(header []string) bool
 }
 
 // Registry holds named parsers.
 type Registry struct {
-	parsers map[string]Parser
+	parsers  map[string]Parser
+	order    []string // registration order, used by Detect
+	rounding Rounding
 }
 
-// FileInfo describes a CSV file in the import directory.
+// FileInfo describes an importable file in the import directory.
 type FileInfo struct {
 	Name string
 	Path string
@@ -33,13 +49,33 @@ func NewRegistry() *Registry {
 	return &Registry{parsers: make(map[string]Parser)}
 }
 
-// Register adds a parser. Panics on duplicate format.
+// SetRounding sets how ParseFile rounds amounts to cents. The default is
+// RoundBankers.
+func (r *Registry) SetRounding(mode Rounding) {
+	r.rounding = mode
+}
+
+// Register adds a built-in parser. Panics on duplicate format; use Add for
+// parsers built from user config.
 func (r *Registry) Register(p Parser) {
+	if err := r.Add(p); err != nil {
+		panic(err.Error())
+	}
+}
+
+// Add adds a parser, failing if its format is empty or already registered
+// (formats are case-insensitive).
+func (r *Registry) Add(p Parser) error {
 	key := strings.ToLower(p.Format())
+	if key == "" {
+		return errors.New("parser format name is empty")
+	}
 	if _, ok := r.parsers[key]; ok {
-		panic("duplicate parser format: " + key)
+		return fmt.Errorf("duplicate parser format: %s", key)
 	}
 	r.parsers[key] = p
+	r.order = append(r.order, key)
+	return nil
 }
 
 // Get returns the parser for format, or nil.
@@ -47,11 +83,163 @@ func (r *Registry) Get(format string) Parser {
 	return r.parsers[strings.ToLower(format)]
 }
 
-// DefaultRegistry returns a registry with all built-in parsers.
-func DefaultRegistry() *Registry {
+// Formats returns the registered format names, sorted.
+func (r *Registry) Formats() []string {
+	names := slices.Clone(r.order)
+	slices.Sort(names)
+	return names
+}
+
+// Detect reads the first line of r and returns the first registered parser
+// whose Matches accepts it. The reader is consumed; callers must rewind or
+// reopen the file before calling Parse.
+func (r *Registry) Detect(rd io.Reader) (Parser, error) {
+	header, err := readHeader(rd)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range r.order {
+		if p := r.parsers[key]; p.Matches(header) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("unrecognized file format (header %q); known formats: %s",
+		strings.Join(header, ","), strings.Join(r.Formats(), ", "))
+}
+
+// readHeader returns the first line of rd split as a CSV record. Lines that
+// are not valid CSV (e.g. an OFX preamble) are returned as a single field.
+func readHeader(rd io.Reader) ([]string, error) {
+	line, err := bufio.NewReader(skipBOM(rd)).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty file")
+	}
+	cr := csv.NewReader(strings.NewReader(line))
+	cr.FieldsPerRecord = -1
+	rec, err := cr.Read()
+	if err != nil {
+		return []string{line}, nil
+	}
+	return rec, nil
+}
+
+// ParseFile detects the format of the file at path, parses it, rounds
+// amounts to cents (see SetRounding), and stamps each transaction with the
+// account ID of the bank account it belongs to.
+func (r *Registry) ParseFile(path string, banks []config.BankAccount) ([]model.BankTransaction, error) {
+	return r.ParseFileFormat(path, "", banks)
+}
+
+// ParseFileFormat is ParseFile with the format named explicitly instead of
+// detected. An empty format means detect.
+func (r *Registry) ParseFileFormat(path, format string, banks []config.BankAccount) ([]model.BankTransaction, error) {
+	name := filepath.Base(path)
+
+	var parser Parser
+	if format != "" {
+		if parser = r.Get(format); parser == nil {
+			return nil, fmt.Errorf("unknown format %q (known: %s)", format, strings.Join(r.Formats(), ", "))
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	if parser == nil {
+		if parser, err = r.Detect(f); err != nil {
+			return nil, fmt.Errorf("detecting format of %s: %w", name, err)
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("rewinding %s: %w", name, err)
+		}
+	}
+
+	txns, err := parser.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", name, err)
+	}
+	RoundAmounts(txns, r.rounding)
+
+	if acctID := ResolveBankAccount(banks, name, parser.Format()); acctID != 0 {
+		for i := range txns {
+			txns[i].BankAccountID = acctID
+		}
+	}
+	return txns, nil
+}
+
+// ResolveBankAccount returns the account ID of the first bank account whose
+// FilePattern matches fileName, falling back to the first whose Format
+// matches format. Returns 0 when nothing matches.
+func ResolveBankAccount(banks []config.BankAccount, fileName, format string) int {
+	lower := strings.ToLower(fileName)
+	for _, b := range banks {
+		if b.FilePattern == "" {
+			continue
+		}
+		if ok, _ := filepath.Match(strings.ToLower(b.FilePattern), lower); ok {
+			return b.AccountID
+		}
+	}
+	for _, b := range banks {
+		if b.Format != "" && strings.EqualFold(b.Format, format) {
+			return b.AccountID
+		}
+	}
+	return 0
+}
+
+// collect runs a streaming parse and gathers the results, for parsers whose
+// Parse is implemented on top of ParseStream.
+func collect(r io.Reader, stream func(io.Reader, func(model.BankTransaction) error) error) ([]model.BankTransaction, error) {
+	var txns []model.BankTransaction
+	err := stream(r, func(txn model.BankTransaction) error {
+		txns = append(txns, txn)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return txns, nil
+}
+
+// utf8BOM is the byte-order mark Windows tools prepend to UTF-8 files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// skipBOM returns a reader that yields r's contents minus a leading UTF-8
+// BOM, if present. Without this the first header becomes "\ufeffDetails".
+func skipBOM(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	if prefix, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(prefix, utf8BOM) {
+		_, _ = br.Discard(len(utf8BOM))
+	}
+	return br
+}
+
+// DefaultRegistry returns a registry with all built-in parsers plus a
+// GenericParser for each user-defined format from cleared.yaml. A format
+// with no name, or whose name is taken by a built-in or an earlier format,
+// is an error.
+func DefaultRegistry(formats ...config.ImportFormat) (*Registry, error) {
 	r := NewRegistry()
 	r.Register(&ChaseParser{})
-	return r
+	r.Register(&BofAParser{})
+	r.Register(&OFXParser{})
+	r.Register(&AmexParser{})
+	r.Register(&QIFParser{})
+	for _, f := range formats {
+		if err := r.Add(NewGenericParserFromConfig(f)); err != nil {
+			return nil, fmt.Errorf("import_formats: %w", err)
+		}
+	}
+	return r, nil
 }
 
 // importDir is the subdirectory for import CSVs.
@@ -60,7 +248,10 @@ const importDir = "import"
 // processedDir is the subdirectory for processed CSVs.
 const processedDir = "import/processed"
 
-// Scan returns CSV files in <repoRoot>/import/.
+// importExts lists the file extensions Scan picks up.
+var importExts = []string{".csv", ".ofx", ".qfx", ".qif"}
+
+// Scan returns importable files in <repoRoot>/import/.
 func Scan(repoRoot string) ([]FileInfo, error) {
 	dir := filepath.Join(repoRoot, importDir)
 	entries, err := os.ReadDir(dir)
@@ -76,7 +267,7 @@ func Scan(repoRoot string) ([]FileInfo, error) {
 		if e.IsDir() {
 			continue
 		}
-		if !strings.HasSuffix(strings.ToLower(e.Name()), ".csv") {
+		if !slices.Contains(importExts, strings.ToLower(filepath.Ext(e.Name()))) {
 			continue
 		}
 		info, err := e.Info()
@@ -94,6 +285,9 @@ func Scan(repoRoot string) ([]FileInfo, error) {
 
 // MarkProcessed moves a file from import/ to import/processed/.
 func MarkProcessed(repoRoot, fileName string) error {
+	if err := checkFileName(fileName); err != nil {
+		return err
+	}
 	src := filepath.Join(repoRoot, importDir, fileName)
 	dstDir := filepath.Join(repoRoot, processedDir)
 
@@ -107,3 +301,47 @@ func MarkProcessed(repoRoot, fileName string) error {
 	}
 	return nil
 }
+
+// Unprocess reverses MarkProcessed, moving a file from import/processed/
+// back to import/ so it can be imported again. It refuses to overwrite a
+// file of the same name already in import/. Manifest rows for the file are
+// kept, since the entries it produced are still in the journal.
+func Unprocess(repoRoot, fileName string) error {
+	if err := checkFileName(fileName); err != nil {
+		return err
+	}
+	if path.Join(processedDir, fileName) == ManifestFile {
+		return fmt.Errorf("%s is the processed-file manifest, not an import", fileName)
+	}
+	src := filepath.Join(repoRoot, processedDir, fileName)
+	dst := filepath.Join(repoRoot, importDir, fileName)
+
+	if _, err := os.Stat(src); err != nil {
+		return fmt.Errorf("moving %s back to import: %w", fileName, err)
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		return fmt.Errorf("moving %s back to import: %w", fileName, fs.ErrExist)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("checking import/%s: %w", fileName, err)
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return fmt.Errorf("moving %s back to import: %w", fileName, err)
+	}
+	return nil
+}
+
+// checkFileName rejects a file name that is not a plain name in import/, so
+// a caller such as an agent script cannot move files elsewhere.
+func checkFileName(fileName string) error {
+	if fileName == "" || fileName == "." || fileName == ".." ||
+		filepath.Base(fileName) != fileName || path.Base(fileName) != fileName {
+		return fmt.Errorf("invalid import file name %q: want a plain file name", fileName)
+	}
+	return nil
+}
+
+// MarkedPaths returns the paths, relative to the repo root, that
+// MarkProcessed (or Unprocess) changes for fileName.
+func MarkedPaths(fileName string) []string {
+	return []string{path.Join(importDir, fileName), path.Join(processedDir, fileName)}
+}
@@ -1,6 +1,10 @@
 package importer
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -8,6 +12,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/cleared-dev/cleared/internal/config"
+	"github.com/cleared-dev/cleared/internal/model"
 )
 
 func TestChaseParser_Parse(t *testing.T) {
@@ -105,6 +112,97 @@ func TestChaseParser_Reference(t *testing.T) {
 	assert.Equal(t, "chase_20250103_GITHUBPROS", txns[0].Reference)
 }
 
+func TestChaseParser_BOMAndCRLF(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/chase_checking.csv")
+	require.NoError(t, err)
+
+	p := &ChaseParser{}
+	want, err := p.Parse(strings.NewReader(string(data)))
+	require.NoError(t, err)
+
+	crlf := strings.ReplaceAll(string(data), "\n", "\r\n")
+	variants := map[string]string{
+		"bom":      "\ufeff" + string(data),
+		"crlf":     crlf,
+		"bom+crlf": "\ufeff" + crlf,
+	}
+	for name, input := range variants {
+		got, err := p.Parse(strings.NewReader(input))
+		require.NoError(t, err, name)
+		require.Len(t, got, len(want), name)
+		for i := range want {
+			assert.Equal(t, want[i].Description, got[i].Description, "%s row %d", name, i)
+			assert.True(t, want[i].Amount.Equal(got[i].Amount), "%s row %d", name, i)
+			assert.True(t, want[i].Date.Equal(got[i].Date), "%s row %d", name, i)
+			assert.Equal(t, want[i].Reference, got[i].Reference, "%s row %d", name, i)
+		}
+	}
+}
+
+func TestRegistry_DetectWithBOM(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/chase_checking.csv")
+	require.NoError(t, err)
+
+	p, err := defaultRegistry(t).Detect(strings.NewReader("\ufeff" + string(data)))
+	require.NoError(t, err)
+	assert.Equal(t, "chase", p.Format())
+}
+
+// chaseRowGenerator produces a Chase CSV of n rows on demand so large-file
+// tests never hold the whole input in memory.
+type chaseRowGenerator struct {
+	n, row int
+	buf    []byte
+}
+
+func (g *chaseRowGenerator) Read(p []byte) (int, error) {
+	for len(g.buf) == 0 {
+		switch {
+		case g.row == 0:
+			g.buf = []byte("Details,Posting Date,Description,Amount,Type,Balance,Check or Slip #\n")
+		case g.row > g.n:
+			return 0, io.EOF
+		default:
+			g.buf = fmt.Appendf(nil, "DEBIT,01/%02d/2025,VENDOR %d,-%d.%02d,ACH_DEBIT,100.00,\n", g.row%28+1, g.row, g.row%500, g.row%100)
+		}
+		g.row++
+	}
+	n := copy(p, g.buf)
+	g.buf = g.buf[n:]
+	return n, nil
+}
+
+func TestChaseParser_ParseStreamLargeFile(t *testing.T) {
+	const rows = 40000
+
+	p := &ChaseParser{}
+	count := 0
+	var last model.BankTransaction
+	err := p.ParseStream(&chaseRowGenerator{n: rows}, func(txn model.BankTransaction) error {
+		count++
+		last = txn
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, rows, count)
+	assert.Equal(t, "VENDOR 40000", last.Description)
+}
+
+func TestChaseParser_ParseStreamStopsOnError(t *testing.T) {
+	stop := errors.New("stop")
+	p := &ChaseParser{}
+	count := 0
+	err := p.ParseStream(&chaseRowGenerator{n: 100}, func(model.BankTransaction) error {
+		count++
+		if count == 3 {
+			return stop
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, stop)
+	assert.Equal(t, 3, count)
+}
+
 func TestRegistry_GetUnknown(t *testing.T) {
 	r := NewRegistry()
 	assert.Nil(t, r.Get("nonexistent"))
@@ -125,9 +223,139 @@ func TestRegistry_CaseInsensitive(t *testing.T) {
 	assert.NotNil(t, r.Get("CHASE"))
 }
 
+// defaultRegistry is DefaultRegistry for formats known to be valid.
+func defaultRegistry(t *testing.T, formats ...config.ImportFormat) *Registry {
+	t.Helper()
+	r, err := DefaultRegistry(formats...)
+	require.NoError(t, err)
+	return r
+}
+
 func TestDefaultRegistry(t *testing.T) {
-	r := DefaultRegistry()
+	r := defaultRegistry(t)
 	assert.NotNil(t, r.Get("chase"))
+	assert.NotNil(t, r.Get("boa"))
+	assert.NotNil(t, r.Get("ofx"))
+	assert.NotNil(t, r.Get("amex"))
+	assert.NotNil(t, r.Get("qif"))
+}
+
+func TestRegistry_DetectChase(t *testing.T) {
+	f, err := os.Open("../../testdata/chase_checking.csv")
+	require.NoError(t, err)
+	defer f.Close()
+
+	p, err := defaultRegistry(t).Detect(f)
+	require.NoError(t, err)
+	assert.Equal(t, "chase", p.Format())
+}
+
+func TestRegistry_DetectBuiltins(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"../../testdata/boa_checking.csv", "boa"},
+		{"../../testdata/checking.ofx", "ofx"},
+		{"../../testdata/amex.csv", "amex"},
+		{"../../testdata/checking.qif", "qif"},
+	}
+	for _, tt := range tests {
+		f, err := os.Open(tt.file)
+		require.NoError(t, err)
+		p, err := defaultRegistry(t).Detect(f)
+		f.Close()
+		require.NoError(t, err, tt.file)
+		assert.Equal(t, tt.want, p.Format(), tt.file)
+	}
+}
+
+func TestRegistry_DetectGeneric(t *testing.T) {
+	r := defaultRegistry(t, config.ImportFormat{
+		Name:              "credit_union",
+		DateColumn:        "Posted",
+		DateLayout:        "01/02/2006",
+		DescriptionColumn: "Memo",
+		DebitColumn:       "Withdrawal",
+		CreditColumn:      "Deposit",
+	})
+
+	p, err := r.Detect(strings.NewReader("Posted,Memo,Withdrawal,Deposit\n01/03/2025,GITHUB,4.00,\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "credit_union", p.Format())
+}
+
+func TestRegistry_DetectUnknown(t *testing.T) {
+	_, err := defaultRegistry(t).Detect(strings.NewReader("Foo,Bar,Baz\n1,2,3\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unrecognized file format")
+	assert.Contains(t, err.Error(), "known formats: amex, boa, chase, ofx, qif")
+}
+
+func TestRegistry_DetectEmpty(t *testing.T) {
+	_, err := defaultRegistry(t).Detect(strings.NewReader(""))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "empty file")
+}
+
+func TestParseFile_BankAccountMapping(t *testing.T) {
+	dir := t.TempDir()
+	chase, err := os.ReadFile("../../testdata/chase_checking.csv")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "chase-jan.csv"), chase, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "savings-jan.csv"), chase, 0o644))
+
+	banks := []config.BankAccount{
+		{Name: "Chase Savings", AccountID: 1020, FilePattern: "savings-*.csv"},
+		{Name: "Chase Checking", AccountID: 1010, Format: "chase"},
+	}
+	r := defaultRegistry(t)
+
+	checking, err := r.ParseFile(filepath.Join(dir, "chase-jan.csv"), banks)
+	require.NoError(t, err)
+	require.NotEmpty(t, checking)
+	for _, txn := range checking {
+		assert.Equal(t, 1010, txn.BankAccountID, "matched by format")
+	}
+
+	savings, err := r.ParseFile(filepath.Join(dir, "savings-jan.csv"), banks)
+	require.NoError(t, err)
+	require.NotEmpty(t, savings)
+	for _, txn := range savings {
+		assert.Equal(t, 1020, txn.BankAccountID, "file pattern wins over format")
+	}
+}
+
+func TestParseFile_Unmapped(t *testing.T) {
+	txns, err := defaultRegistry(t).ParseFile("../../testdata/chase_checking.csv", nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, txns)
+	assert.Zero(t, txns[0].BankAccountID)
+}
+
+func TestParseFileFormat(t *testing.T) {
+	r := defaultRegistry(t)
+
+	txns, err := r.ParseFileFormat("../../testdata/chase_checking.csv", "CHASE", nil)
+	require.NoError(t, err)
+	assert.Len(t, txns, 6)
+
+	_, err = r.ParseFileFormat("../../testdata/chase_checking.csv", "boa", nil)
+	require.Error(t, err, "forced format does not fall back to detection")
+
+	_, err = r.ParseFileFormat("../../testdata/chase_checking.csv", "wells", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown format "wells"`)
+}
+
+func TestResolveBankAccount(t *testing.T) {
+	banks := []config.BankAccount{
+		{AccountID: 2010, FilePattern: "AMEX*.csv"},
+		{AccountID: 1010, Format: "chase"},
+	}
+	assert.Equal(t, 2010, ResolveBankAccount(banks, "amex-2025-01.csv", "amex"), "pattern is case-insensitive")
+	assert.Equal(t, 1010, ResolveBankAccount(banks, "export.csv", "Chase"))
+	assert.Equal(t, 0, ResolveBankAccount(banks, "export.ofx", "ofx"))
 }
 
 func TestScan_FindsCSVs(t *testing.T) {
@@ -137,11 +365,13 @@ func TestScan_FindsCSVs(t *testing.T) {
 
 	require.NoError(t, os.WriteFile(filepath.Join(importDir, "bank.csv"), []byte("data"), 0o644))
 	require.NoError(t, os.WriteFile(filepath.Join(importDir, "other.txt"), []byte("data"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(importDir, "card.QFX"), []byte("data"), 0o644))
 
 	files, err := Scan(dir)
 	require.NoError(t, err)
-	assert.Len(t, files, 1)
+	assert.Len(t, files, 2)
 	assert.Equal(t, "bank.csv", files[0].Name)
+	assert.Equal(t, "card.QFX", files[1].Name)
 }
 
 func TestScan_IgnoresProcessedDir(t *testing.T) {
@@ -197,3 +427,56 @@ func TestMarkProcessed_CreatesDir(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, info.IsDir())
 }
+
+func TestUnprocess(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "import"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "import", "bank.csv"), []byte("data"), 0o644))
+	require.NoError(t, MarkProcessed(dir, "bank.csv"))
+
+	require.NoError(t, Unprocess(dir, "bank.csv"))
+
+	data, err := os.ReadFile(filepath.Join(dir, "import", "bank.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+	assert.NoFileExists(t, filepath.Join(dir, "import", "processed", "bank.csv"))
+
+	err = Unprocess(dir, "missing.csv")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestProcessed_RejectsPaths(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "import", "processed"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "outside.csv"), []byte("x"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "import", "processed", "bank.csv"), []byte("x"), 0o644))
+
+	for _, name := range []string{"../outside.csv", "../../foo", "sub/bank.csv", "..", ".", ""} {
+		require.ErrorContains(t, MarkProcessed(dir, name), "invalid import file name", name)
+		require.ErrorContains(t, Unprocess(dir, name), "invalid import file name", name)
+	}
+	require.ErrorContains(t, Unprocess(dir, "../processed/bank.csv"), "invalid import file name")
+	assert.FileExists(t, filepath.Join(dir, "outside.csv"))
+	assert.FileExists(t, filepath.Join(dir, "import", "processed", "bank.csv"))
+}
+
+func TestUnprocess_Collision(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "import", "processed"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "import", "processed", "bank.csv"), []byte("old"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "import", "bank.csv"), []byte("new"), 0o644))
+
+	err := Unprocess(dir, "bank.csv")
+	require.ErrorIs(t, err, fs.ErrExist)
+
+	// Neither file is touched.
+	data, err := os.ReadFile(filepath.Join(dir, "import", "bank.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+	data, err = os.ReadFile(filepath.Join(dir, "import", "processed", "bank.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(data))
+
+	require.NoError(t, RecordManifest(dir, "bank.csv", []string{"2025-01-0001"}))
+	require.Error(t, Unprocess(dir, "manifest.csv"), "the manifest is not an import")
+}
@@ -0,0 +1,122 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/cleared-dev/cleared/internal/id"
+)
+
+// ManifestFile is the repo-relative path of the processed-file manifest,
+// which maps each processed file to the journal entries it produced.
+const ManifestFile = processedDir + "/manifest.csv"
+
+// ManifestHeader is the CSV header for manifest.csv.
+const ManifestHeader = "file,entry_id"
+
+// ManifestRow links one entry to the import file that produced it.
+type ManifestRow struct {
+	File    string
+	EntryID string
+}
+
+// RecordManifest appends a row per entry ID for fileName to the manifest,
+// creating it with a header if needed. Entry IDs are stored as entry groups,
+// without the leg suffix.
+func RecordManifest(repoRoot, fileName string, entryIDs []string) error {
+	if len(entryIDs) == 0 {
+		return nil
+	}
+	p := filepath.Join(repoRoot, filepath.FromSlash(ManifestFile))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("creating processed dir: %w", err)
+	}
+	_, statErr := os.Stat(p)
+
+	f, err := os.OpenFile(p, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening manifest: %w", err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if errors.Is(statErr, fs.ErrNotExist) {
+		if _, err := f.WriteString(ManifestHeader + "\n"); err != nil {
+			return fmt.Errorf("writing manifest header: %w", err)
+		}
+	}
+	for _, entryID := range entryIDs {
+		if err := w.Write([]string{fileName, id.EntryGroup(entryID)}); err != nil {
+			return fmt.Errorf("writing manifest: %w", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+	return nil
+}
+
+// ReadManifest returns every manifest row in the order recorded. A missing
+// manifest reads as empty.
+func ReadManifest(repoRoot string) ([]ManifestRow, error) {
+	f, err := os.Open(filepath.Join(repoRoot, filepath.FromSlash(ManifestFile)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening manifest: %w", err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	rows := make([]ManifestRow, 0, len(records)-1)
+	for i, rec := range records[1:] {
+		if len(rec) != 2 {
+			return nil, fmt.Errorf("manifest row %d: expected 2 fields, got %d", i+2, len(rec))
+		}
+		rows = append(rows, ManifestRow{File: rec[0], EntryID: rec[1]})
+	}
+	return rows, nil
+}
+
+// EntriesForFile returns the entry IDs the processed file produced.
+func EntriesForFile(repoRoot, fileName string) ([]string, error) {
+	rows, err := ReadManifest(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, r := range rows {
+		if r.File == fileName {
+			ids = append(ids, r.EntryID)
+		}
+	}
+	return ids, nil
+}
+
+// SourceFile returns the processed file that produced entryID (an entry
+// group or a leg ID), reporting false if the manifest has no record of it.
+func SourceFile(repoRoot, entryID string) (string, bool, error) {
+	rows, err := ReadManifest(repoRoot)
+	if err != nil {
+		return "", false, err
+	}
+	group := id.EntryGroup(entryID)
+	for _, r := range rows {
+		if r.EntryID == group {
+			return r.File, true, nil
+		}
+	}
+	return "", false, nil
+}
@@ -0,0 +1,40 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	rows, err := ReadManifest(dir)
+	require.NoError(t, err)
+	assert.Empty(t, rows, "missing manifest reads as empty")
+
+	require.NoError(t, RecordManifest(dir, "chase_jan.csv", []string{"2025-01-0001", "2025-01-0002b"}))
+	require.NoError(t, RecordManifest(dir, "amex_jan.csv", []string{"2025-01-0003"}))
+	require.NoError(t, RecordManifest(dir, "empty.csv", nil))
+
+	data, err := os.ReadFile(filepath.Join(dir, "import", "processed", "manifest.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, ManifestHeader+"\nchase_jan.csv,2025-01-0001\nchase_jan.csv,2025-01-0002\namex_jan.csv,2025-01-0003\n",
+		string(data), "one header, leg suffixes dropped")
+
+	ids, err := EntriesForFile(dir, "chase_jan.csv")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2025-01-0001", "2025-01-0002"}, ids)
+
+	file, ok, err := SourceFile(dir, "2025-01-0003a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "amex_jan.csv", file)
+
+	_, ok, err = SourceFile(dir, "2025-01-0099")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
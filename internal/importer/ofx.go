@@ -0,0 +1,112 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// OFXParser parses OFX/QFX statement downloads. Both the SGML (OFX 1.x,
+// unclosed leaf tags) and XML (OFX 2.x) flavors are accepted.
+type OFXParser struct{}
+
+const ofxDateFormat = "20060102"
+
+var (
+	ofxTxnPattern = regexp.MustCompile(`(?is)<STMTTRN>(.*?)</STMTTRN>`)
+	ofxTagPattern = regexp.MustCompile(`(?i)<([A-Z0-9.]+)>([^<\r\n]*)`)
+)
+
+// Format returns the parser name.
+func (p *OFXParser) Format() string { return "ofx" }
+
+// Matches reports whether header is an OFX 1.x SGML preamble or the start of
+// an OFX 2.x XML document.
+func (p *OFXParser) Matches(header []string) bool {
+	if len(header) == 0 {
+		return false
+	}
+	first := strings.ToUpper(strings.TrimSpace(header[0]))
+	return strings.HasPrefix(first, "OFXHEADER") ||
+		strings.HasPrefix(first, "<?XML") ||
+		strings.HasPrefix(first, "<OFX")
+}
+
+// Parse reads an OFX document and returns BankTransactions. The FITID is
+// used as the Reference since banks guarantee it is unique per account.
+func (p *OFXParser) Parse(r io.Reader) ([]model.BankTransaction, error) {
+	return collect(r, p.ParseStream)
+}
+
+// ParseStream calls fn for each <STMTTRN> block. OFX is not line-oriented
+// and statements are small, so the document is read whole before emitting.
+func (p *OFXParser) ParseStream(r io.Reader, fn func(model.BankTransaction) error) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("reading OFX: %w", err)
+	}
+
+	for i, m := range ofxTxnPattern.FindAllSubmatch(data, -1) {
+		txn, err := parseOFXTransaction(string(m[1]))
+		if err != nil {
+			return fmt.Errorf("transaction %d: %w", i+1, err)
+		}
+		if err := fn(txn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseOFXTransaction(block string) (model.BankTransaction, error) {
+	fields := make(map[string]string)
+	for _, m := range ofxTagPattern.FindAllStringSubmatch(block, -1) {
+		fields[strings.ToUpper(m[1])] = strings.TrimSpace(m[2])
+	}
+
+	date, err := parseOFXDate(fields["DTPOSTED"])
+	if err != nil {
+		return model.BankTransaction{}, err
+	}
+
+	amount, err := parseMoney(fields["TRNAMT"])
+	if err != nil {
+		return model.BankTransaction{}, err
+	}
+
+	fitID := fields["FITID"]
+	if fitID == "" {
+		return model.BankTransaction{}, errors.New("missing FITID")
+	}
+
+	desc := fields["NAME"]
+	if desc == "" {
+		desc = fields["MEMO"]
+	}
+
+	return model.BankTransaction{
+		Date:        date,
+		Description: desc,
+		Amount:      amount,
+		Reference:   fitID,
+		Type:        fields["TRNTYPE"],
+	}, nil
+}
+
+// parseOFXDate accepts YYYYMMDD and YYYYMMDDHHMMSS[.XXX][TZ] forms. Only the
+// calendar date matters for the journal, so the time portion is ignored.
+func parseOFXDate(s string) (time.Time, error) {
+	if len(s) < len(ofxDateFormat) {
+		return time.Time{}, fmt.Errorf("parsing date %q: too short", s)
+	}
+	date, err := time.Parse(ofxDateFormat, s[:len(ofxDateFormat)])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing date %q: %w", s, err)
+	}
+	return date, nil
+}
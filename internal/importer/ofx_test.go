@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOFXParser_Parse(t *testing.T) {
+	f, err := os.Open("../../testdata/checking.ofx")
+	require.NoError(t, err)
+	defer f.Close()
+
+	p := &OFXParser{}
+	txns, err := p.Parse(f)
+	require.NoError(t, err)
+	require.Len(t, txns, 3)
+
+	assert.Equal(t, "GITHUB *PRO SUBSCRIPTION", txns[0].Description)
+	assert.Equal(t, "-4.00", txns[0].Amount.StringFixed(2))
+	assert.Equal(t, "202501030001", txns[0].Reference)
+	assert.Equal(t, "DEBIT", txns[0].Type)
+	assert.Equal(t, 3, txns[0].Date.Day())
+}
+
+func TestOFXParser_LongDate(t *testing.T) {
+	f, err := os.Open("../../testdata/checking.ofx")
+	require.NoError(t, err)
+	defer f.Close()
+
+	p := &OFXParser{}
+	txns, err := p.Parse(f)
+	require.NoError(t, err)
+
+	// 20250105120000.000[-5:EST]
+	assert.Equal(t, 2025, txns[1].Date.Year())
+	assert.Equal(t, 1, int(txns[1].Date.Month()))
+	assert.Equal(t, 5, txns[1].Date.Day())
+	assert.Equal(t, 0, txns[1].Date.Hour(), "time portion is dropped")
+}
+
+func TestOFXParser_NameThenMemo(t *testing.T) {
+	f, err := os.Open("../../testdata/checking.ofx")
+	require.NoError(t, err)
+	defer f.Close()
+
+	p := &OFXParser{}
+	txns, err := p.Parse(f)
+	require.NoError(t, err)
+
+	assert.Equal(t, "AWS *SERVICES", txns[1].Description, "NAME wins over MEMO")
+	assert.Equal(t, "ACME CONSULTING INVOICE 1042", txns[2].Description, "MEMO used when NAME is absent")
+	assert.True(t, txns[2].Amount.IsPositive())
+}
+
+func TestOFXParser_XMLClosingTags(t *testing.T) {
+	doc := `<?xml version="1.0"?><OFX><BANKTRANLIST>
+<STMTTRN><TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20250110</DTPOSTED><TRNAMT>-15.00</TRNAMT><FITID>abc</FITID><NAME>DROPBOX</NAME></STMTTRN>
+</BANKTRANLIST></OFX>`
+	p := &OFXParser{}
+	txns, err := p.Parse(strings.NewReader(doc))
+	require.NoError(t, err)
+	require.Len(t, txns, 1)
+	assert.Equal(t, "DROPBOX", txns[0].Description)
+	assert.Equal(t, "-15.00", txns[0].Amount.StringFixed(2))
+	assert.Equal(t, "abc", txns[0].Reference)
+}
+
+func TestOFXParser_MissingFITID(t *testing.T) {
+	doc := "<STMTTRN>\n<DTPOSTED>20250110\n<TRNAMT>-15.00\n<NAME>DROPBOX\n</STMTTRN>"
+	p := &OFXParser{}
+	_, err := p.Parse(strings.NewReader(doc))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "FITID")
+}
+
+func TestOFXParser_BadDate(t *testing.T) {
+	doc := "<STMTTRN>\n<DTPOSTED>2025\n<TRNAMT>-15.00\n<FITID>1\n</STMTTRN>"
+	p := &OFXParser{}
+	_, err := p.Parse(strings.NewReader(doc))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parsing date")
+}
+
+func TestOFXParser_NoTransactions(t *testing.T) {
+	p := &OFXParser{}
+	txns, err := p.Parse(strings.NewReader("<OFX></OFX>"))
+	require.NoError(t, err)
+	assert.Nil(t, txns)
+}
+
+func TestOFXParser_Format(t *testing.T) {
+	p := &OFXParser{}
+	assert.Equal(t, "ofx", p.Format())
+}
@@ -0,0 +1,117 @@
+package importer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// QIFParser parses Quicken Interchange Format bank and card exports.
+//
+// Each record is a run of lines whose first character is a field code,
+// terminated by a "^" line: D date, T amount, P payee, M memo.
+type QIFParser struct{}
+
+// qifDateFormats covers the two-digit-year and apostrophe four-digit-year
+// forms Quicken emits. Single-digit months and days are accepted.
+var qifDateFormats = []string{"1/2/06", "1/2'2006", "1/2/2006"}
+
+// Format returns the parser name.
+func (p *QIFParser) Format() string { return "qif" }
+
+// Matches reports whether header is a QIF bank or credit card type line.
+func (p *QIFParser) Matches(header []string) bool {
+	if len(header) == 0 {
+		return false
+	}
+	first := strings.ToLower(strings.TrimSpace(header[0]))
+	return first == "!type:bank" || first == "!type:ccard"
+}
+
+// Parse reads a QIF file and returns BankTransactions.
+func (p *QIFParser) Parse(r io.Reader) ([]model.BankTransaction, error) {
+	return collect(r, p.ParseStream)
+}
+
+// ParseStream reads a QIF file record by record, calling fn for each
+// transaction.
+func (p *QIFParser) ParseStream(r io.Reader, fn func(model.BankTransaction) error) error {
+	sc := bufio.NewScanner(skipBOM(r))
+
+	fields := make(map[byte]string)
+	start := 0
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimRight(sc.Text(), "\r")
+		if text == "" || text[0] == '!' {
+			continue
+		}
+		if start == 0 {
+			start = line
+		}
+
+		if text[0] != '^' {
+			fields[text[0]] = text[1:]
+			continue
+		}
+
+		txn, err := parseQIFRecord(fields)
+		if err != nil {
+			return fmt.Errorf("record at line %d: %w", start, err)
+		}
+		if err := fn(txn); err != nil {
+			return err
+		}
+		clear(fields)
+		start = 0
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("reading QIF: %w", err)
+	}
+	if len(fields) > 0 {
+		return fmt.Errorf("record at line %d: missing ^ terminator", start)
+	}
+	return nil
+}
+
+func parseQIFRecord(fields map[byte]string) (model.BankTransaction, error) {
+	date, err := parseQIFDate(fields['D'])
+	if err != nil {
+		return model.BankTransaction{}, err
+	}
+
+	amountStr, ok := fields['T']
+	if !ok {
+		return model.BankTransaction{}, errors.New("missing T (amount) field")
+	}
+	amount, err := parseMoney(amountStr)
+	if err != nil {
+		return model.BankTransaction{}, err
+	}
+
+	desc := strings.TrimSpace(fields['P'])
+	if desc == "" {
+		desc = strings.TrimSpace(fields['M'])
+	}
+
+	return model.BankTransaction{
+		Date:        date,
+		Description: desc,
+		Amount:      amount,
+		Reference:   makeReference("qif", date, desc),
+	}, nil
+}
+
+func parseQIFDate(s string) (time.Time, error) {
+	cleaned := strings.ReplaceAll(s, " ", "")
+	for _, layout := range qifDateFormats {
+		if d, err := time.Parse(layout, cleaned); err == nil {
+			return d, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("parsing date %q: unrecognized format", s)
+}
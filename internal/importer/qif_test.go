@@ -0,0 +1,80 @@
+package importer
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQIFParser_Parse(t *testing.T) {
+	f, err := os.Open("../../testdata/checking.qif")
+	require.NoError(t, err)
+	defer f.Close()
+
+	p := &QIFParser{}
+	txns, err := p.Parse(f)
+	require.NoError(t, err)
+	require.Len(t, txns, 4)
+
+	assert.Equal(t, "GITHUB *PRO SUBSCRIPTION", txns[0].Description)
+	assert.Equal(t, "-4.00", txns[0].Amount.StringFixed(2))
+	assert.Equal(t, "qif_20250103_GITHUBPROS", txns[0].Reference)
+
+	assert.Equal(t, "AWS *SERVICES", txns[1].Description, "payee wins over memo")
+	assert.Equal(t, "ACME CONSULTING INVOICE 1042", txns[2].Description, "memo used when payee is absent")
+	assert.Equal(t, "3500.00", txns[2].Amount.StringFixed(2))
+}
+
+func TestQIFParser_DateFormats(t *testing.T) {
+	f, err := os.Open("../../testdata/checking.qif")
+	require.NoError(t, err)
+	defer f.Close()
+
+	p := &QIFParser{}
+	txns, err := p.Parse(f)
+	require.NoError(t, err)
+
+	days := []int{3, 5, 15, 22}
+	for i, d := range days {
+		assert.Equal(t, 2025, txns[i].Date.Year(), "record %d", i)
+		assert.Equal(t, 1, int(txns[i].Date.Month()), "record %d", i)
+		assert.Equal(t, d, txns[i].Date.Day(), "record %d", i)
+	}
+}
+
+func TestQIFParser_BadDate(t *testing.T) {
+	p := &QIFParser{}
+	_, err := p.Parse(strings.NewReader("!Type:Bank\nD2025-01-03\nT-4.00\n^\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+	assert.Contains(t, err.Error(), "parsing date")
+}
+
+func TestQIFParser_MissingAmount(t *testing.T) {
+	p := &QIFParser{}
+	_, err := p.Parse(strings.NewReader("!Type:Bank\nD01/03/25\nPGITHUB\n^\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing T")
+}
+
+func TestQIFParser_Unterminated(t *testing.T) {
+	p := &QIFParser{}
+	_, err := p.Parse(strings.NewReader("!Type:Bank\nD01/03/25\nT-4.00\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "terminator")
+}
+
+func TestQIFParser_Matches(t *testing.T) {
+	p := &QIFParser{}
+	assert.True(t, p.Matches([]string{"!Type:Bank"}))
+	assert.True(t, p.Matches([]string{"!TYPE:CCard"}))
+	assert.False(t, p.Matches([]string{"!Type:Invst"}))
+}
+
+func TestQIFParser_Format(t *testing.T) {
+	p := &QIFParser{}
+	assert.Equal(t, "qif", p.Format())
+}
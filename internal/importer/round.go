@@ -0,0 +1,37 @@
+package importer
+
+import (
+	"fmt"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// Rounding is how imported amounts with more than two decimal places are
+// brought to cents, set by import.rounding in cleared.yaml.
+type Rounding string
+
+// Rounding modes. The zero value rounds like RoundBankers.
+const (
+	RoundBankers Rounding = "bankers" // half to even: 1.005 becomes 1.00
+	RoundHalfUp  Rounding = "half_up" // half away from zero: 1.005 becomes 1.01
+	RoundNone    Rounding = "none"    // keep amounts as parsed
+)
+
+// RoundAmounts normalizes each transaction's amount to two decimal places,
+// which the journal requires. A transaction whose value changed keeps the
+// original in Notes, so the bank's figure is never lost.
+func RoundAmounts(txns []model.BankTransaction, mode Rounding) {
+	if mode == RoundNone {
+		return
+	}
+	for i, txn := range txns {
+		rounded := txn.Amount.RoundBank(2)
+		if mode == RoundHalfUp {
+			rounded = txn.Amount.Round(2)
+		}
+		if !rounded.Equal(txn.Amount) {
+			txns[i].Notes = fmt.Sprintf("original amount %s", txn.Amount)
+		}
+		txns[i].Amount = rounded
+	}
+}
@@ -0,0 +1,36 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+func TestRoundAmounts(t *testing.T) {
+	tests := []struct {
+		amount    string
+		mode      Rounding
+		want      string
+		wantNotes string
+	}{
+		{"1.005", "", "1.00", "original amount 1.005"},
+		{"1.015", RoundBankers, "1.02", "original amount 1.015"},
+		{"1.004", RoundBankers, "1.00", "original amount 1.004"},
+		{"-1.005", RoundBankers, "-1.00", "original amount -1.005"},
+		{"1.005", RoundHalfUp, "1.01", "original amount 1.005"},
+		{"-1.005", RoundHalfUp, "-1.01", "original amount -1.005"},
+		{"127.50", RoundBankers, "127.50", ""},
+		{"4.500", RoundBankers, "4.50", ""},
+		{"1.005", RoundNone, "1.005", ""},
+	}
+	for _, tt := range tests {
+		txns := []model.BankTransaction{{Amount: decimal.RequireFromString(tt.amount)}}
+		RoundAmounts(txns, tt.mode)
+		assert.True(t, decimal.RequireFromString(tt.want).Equal(txns[0].Amount),
+			"%s %q: got %s", tt.amount, tt.mode, txns[0].Amount)
+		assert.Equal(t, tt.wantNotes, txns[0].Notes, "%s %q", tt.amount, tt.mode)
+	}
+}
@@ -0,0 +1,129 @@
+package journal
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "journal.csv")
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0o644))
+
+	err := writeFileAtomic(path, func(w io.Writer) error {
+		_, err := io.WriteString(w, "new\n")
+		return err
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new\n", string(data))
+	assertNoTempFiles(t, dir)
+}
+
+func TestWriteFileAtomic_FailureLeavesOriginal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "journal.csv")
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0o644))
+
+	err := writeFileAtomic(path, func(w io.Writer) error {
+		// Simulate dying halfway through a row.
+		_, _ = io.WriteString(w, "old\n2025-01-002a,2025-01")
+		return errors.New("disk full")
+	})
+	require.Error(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "old\n", string(data), "original file untouched")
+	assertNoTempFiles(t, dir)
+}
+
+func TestReadMonth_TruncatedRow(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020)
+	svc := NewService(dir, accts)
+
+	_, err := svc.AddDouble(AddDoubleParams{
+		Date:          date(2025, 1, 15),
+		Description:   "GitHub subscription",
+		DebitAccount:  5020,
+		CreditAccount: 1010,
+		Amount:        dec("4.00"),
+		Status:        model.StatusAutoConfirmed,
+		Confidence:    dec("0.98"),
+	})
+	require.NoError(t, err)
+
+	// Simulate a crash that left a partial row (as a pre-atomic writer could).
+	path := filepath.Join(dir, "2025", "01", "journal.csv")
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString("2025-01-002a,2025-01-16,5020,AWS")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	_, err = svc.ReadMonth(2025, 1)
+	require.Error(t, err, "partial row must not be silently dropped")
+	assert.Contains(t, err.Error(), "expected 17 fields, got 4")
+	assert.Contains(t, err.Error(), "2025/01/journal.csv")
+
+	// Further writes refuse to build on a corrupt month.
+	_, err = svc.AddDouble(AddDoubleParams{
+		Date:          date(2025, 1, 20),
+		Description:   "Next",
+		DebitAccount:  5020,
+		CreditAccount: 1010,
+		Amount:        dec("1.00"),
+		Status:        model.StatusAutoConfirmed,
+		Confidence:    dec("0.98"),
+	})
+	require.Error(t, err)
+}
+
+func TestAddDouble_NoTrailingNewline(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020)
+	svc := NewService(dir, accts)
+
+	// A hand-edited file whose last row has no newline.
+	path := filepath.Join(dir, "2025", "01", "journal.csv")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	content := Header + "\n" +
+		"2025-01-001a,2025-01-03,5020,GitHub,4.00,,,,0.98,auto-confirmed,,,,\n" +
+		"2025-01-001b,2025-01-03,1010,GitHub,,4.00,,,0.98,auto-confirmed,,,,"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	entryID, err := svc.AddDouble(AddDoubleParams{
+		Date:          date(2025, 1, 20),
+		Description:   "Next",
+		DebitAccount:  5020,
+		CreditAccount: 1010,
+		Amount:        dec("1.00"),
+		Status:        model.StatusAutoConfirmed,
+		Confidence:    dec("0.98"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01-0002", entryID)
+
+	legs, err := svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	assert.Len(t, legs, 4)
+	assertNoTempFiles(t, filepath.Dir(path))
+}
+
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, ".*.tmp-*"))
+	require.NoError(t, err)
+	assert.Empty(t, matches, "temp files left behind")
+}
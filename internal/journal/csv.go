@@ -14,10 +14,10 @@ import (
 )
 
 // Header is the CSV header for journal.csv.
-const Header = "entry_id,date,account_id,description,debit,credit,counterparty,reference,confidence,status,evidence,receipt_hash,tags,notes"
+const Header = "entry_id,date,account_id,description,debit,credit,counterparty,reference,confidence,status,evidence,receipt_hash,tags,notes,currency,created_at,source_file"
 
 const (
-	numFields   = 14
+	numFields   = 17
 	dateFormat  = "2006-01-02"
 	colEntryID  = 0
 	colDate     = 1
@@ -33,12 +33,55 @@ const (
 	colReceipt  = 11
 	colTags     = 12
 	colNotes    = 13
+	colCurrency = 14
+	colCreated  = 15
+	colSource   = 16
+
+	// legacyFields is the column count before currency was added. Such rows
+	// read with an empty Currency, CreatedAt and SourceFile.
+	legacyFields = 14
 )
 
-// ReadLegs reads all legs from a journal.csv reader.
+// requiredColumns must appear in a journal's header; other columns read as
+// empty when missing.
+var requiredColumns = []string{"entry_id", "date", "account_id", "debit", "credit"}
+
+// columns maps a journal's column names to their index in each row.
+type columns map[string]int
+
+// canonicalColumns is the layout Header describes.
+var canonicalColumns = func() columns {
+	cols := make(columns)
+	for i, name := range strings.Split(Header, ",") {
+		cols[name] = i
+	}
+	return cols
+}()
+
+// headerColumns maps the names in a journal's header row to their index.
+func headerColumns(header []string) (columns, error) {
+	cols := make(columns, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if _, dup := cols[name]; dup {
+			return nil, fmt.Errorf("journal header repeats column %q", name)
+		}
+		cols[name] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := cols[name]; !ok {
+			return nil, fmt.Errorf("journal header is missing column %q", name)
+		}
+	}
+	return cols, nil
+}
+
+// ReadLegs reads all legs from a journal.csv reader. Columns are matched by
+// the names in the header row, so they may come in any order; unknown
+// columns are ignored.
 func ReadLegs(r io.Reader) ([]model.Leg, error) {
 	cr := csv.NewReader(r)
-	cr.FieldsPerRecord = numFields
+	cr.FieldsPerRecord = -1 // legacy rows without currency, and newer rows with extra columns, are accepted
 
 	records, err := cr.ReadAll()
 	if err != nil {
@@ -49,10 +92,22 @@ func ReadLegs(r io.Reader) ([]model.Leg, error) {
 		return nil, nil
 	}
 
-	// Skip header row.
+	cols, err := headerColumns(records[0])
+	if err != nil {
+		return nil, err
+	}
+	// Rows may stop short of trailing optional columns, as legacy rows
+	// without currency do, but must reach every required one.
+	minFields := 0
+	for _, name := range requiredColumns {
+		minFields = max(minFields, cols[name]+1)
+	}
 	var legs []model.Leg
 	for i, rec := range records[1:] {
-		leg, err := UnmarshalLeg(rec)
+		if len(rec) < minFields {
+			return nil, fmt.Errorf("row %d: expected %d fields, got %d", i+2, len(records[0]), len(rec))
+		}
+		leg, err := unmarshalColumns(rec, cols)
 		if err != nil {
 			return nil, fmt.Errorf("row %d: %w", i+2, err)
 		}
@@ -118,63 +173,92 @@ func MarshalLeg(leg model.Leg) []string {
 	row[colReceipt] = leg.ReceiptHash
 	row[colTags] = leg.Tags
 	row[colNotes] = leg.Notes
+	row[colCurrency] = leg.Currency
+	if !leg.CreatedAt.IsZero() {
+		row[colCreated] = leg.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	row[colSource] = leg.SourceFile
 
 	return row
 }
 
-// UnmarshalLeg converts a CSV row to a Leg.
+// UnmarshalLeg converts a CSV row to a Leg. Legacy rows without the
+// currency column are accepted, and columns past the known ones, as a later
+// version might write, are ignored.
 func UnmarshalLeg(record []string) (model.Leg, error) {
-	if len(record) != numFields {
+	if len(record) < legacyFields {
 		return model.Leg{}, fmt.Errorf("expected %d fields, got %d", numFields, len(record))
 	}
+	return unmarshalColumns(record, canonicalColumns)
+}
+
+// unmarshalColumns converts a CSV row laid out as cols to a Leg.
+func unmarshalColumns(record []string, cols columns) (model.Leg, error) {
+	field := func(name string) string {
+		if i, ok := cols[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
 
-	date, err := time.Parse(dateFormat, record[colDate])
+	date, err := time.Parse(dateFormat, field("date"))
 	if err != nil {
-		return model.Leg{}, fmt.Errorf("parsing date %q: %w", record[colDate], err)
+		return model.Leg{}, fmt.Errorf("parsing date %q: %w", field("date"), err)
 	}
 
-	accountID, err := strconv.Atoi(record[colAcctID])
+	accountID, err := strconv.Atoi(field("account_id"))
 	if err != nil {
-		return model.Leg{}, fmt.Errorf("parsing account_id %q: %w", record[colAcctID], err)
+		return model.Leg{}, fmt.Errorf("parsing account_id %q: %w", field("account_id"), err)
 	}
 
 	var debit, credit, confidence decimal.Decimal
 
-	if record[colDebit] != "" {
-		debit, err = decimal.NewFromString(record[colDebit])
+	if s := field("debit"); s != "" {
+		debit, err = decimal.NewFromString(s)
+		if err != nil {
+			return model.Leg{}, fmt.Errorf("parsing debit %q: %w", s, err)
+		}
+	}
+
+	if s := field("credit"); s != "" {
+		credit, err = decimal.NewFromString(s)
 		if err != nil {
-			return model.Leg{}, fmt.Errorf("parsing debit %q: %w", record[colDebit], err)
+			return model.Leg{}, fmt.Errorf("parsing credit %q: %w", s, err)
 		}
 	}
 
-	if record[colCredit] != "" {
-		credit, err = decimal.NewFromString(record[colCredit])
+	if s := field("confidence"); s != "" {
+		confidence, err = decimal.NewFromString(s)
 		if err != nil {
-			return model.Leg{}, fmt.Errorf("parsing credit %q: %w", record[colCredit], err)
+			return model.Leg{}, fmt.Errorf("parsing confidence %q: %w", s, err)
 		}
 	}
 
-	if record[colConf] != "" {
-		confidence, err = decimal.NewFromString(record[colConf])
+	var created time.Time
+	if s := field("created_at"); s != "" {
+		created, err = time.Parse(time.RFC3339, s)
 		if err != nil {
-			return model.Leg{}, fmt.Errorf("parsing confidence %q: %w", record[colConf], err)
+			return model.Leg{}, fmt.Errorf("parsing created_at %q: %w", s, err)
 		}
 	}
 
 	return model.Leg{
-		EntryID:      record[colEntryID],
+		EntryID:      field("entry_id"),
 		Date:         date,
 		AccountID:    accountID,
-		Description:  record[colDesc],
+		Description:  field("description"),
 		Debit:        debit,
 		Credit:       credit,
-		Counterparty: record[colCparty],
-		Reference:    record[colRef],
+		Counterparty: field("counterparty"),
+		Reference:    field("reference"),
 		Confidence:   confidence,
-		Status:       model.EntryStatus(record[colStatus]),
-		Evidence:     record[colEvidence],
-		ReceiptHash:  record[colReceipt],
-		Tags:         record[colTags],
-		Notes:        record[colNotes],
+		Status:       model.EntryStatus(field("status")),
+		Evidence:     field("evidence"),
+		ReceiptHash:  field("receipt_hash"),
+		Tags:         field("tags"),
+		Notes:        field("notes"),
+		Currency:     field("currency"),
+		CreatedAt:    created,
+		SourceFile:   field("source_file"),
 	}, nil
 }
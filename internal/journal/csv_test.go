@@ -310,3 +310,109 @@ func TestAllStatusValues(t *testing.T) {
 		assert.Equal(t, status, got[0].Status, "status %q should survive round-trip", status)
 	}
 }
+
+func TestReadLegs_LegacyColumns(t *testing.T) {
+	csv := "entry_id,date,account_id,description,debit,credit,counterparty,reference,confidence,status,evidence,receipt_hash,tags,notes\n" +
+		"2025-01-0001a,2025-01-03,5020,GitHub,4.00,,,,,auto-confirmed,,,,\n" +
+		"2025-01-0001b,2025-01-03,1010,GitHub,,4.00,,,,auto-confirmed,,,,\n"
+
+	legs, err := ReadLegs(strings.NewReader(csv))
+	require.NoError(t, err)
+	require.Len(t, legs, 2)
+	assert.Empty(t, legs[0].Currency)
+	assert.Equal(t, "4.00", legs[1].Credit.StringFixed(2))
+}
+
+func TestReadLegs_ExtraColumns(t *testing.T) {
+	csv := Header + ",reviewer\n" +
+		"2025-01-0001a,2025-01-03,5020,GitHub,4.00,,GitHub,,0.98,auto-confirmed,rule: GITHUB*,,recurring,,CAD,,,dana\n" +
+		"2025-01-0001b,2025-01-03,1010,GitHub,,4.00,GitHub,,0.98,auto-confirmed,rule: GITHUB*,,recurring,,CAD,,,dana\n"
+
+	legs, err := ReadLegs(strings.NewReader(csv))
+	require.NoError(t, err)
+	require.Len(t, legs, 2)
+	assert.Equal(t, "2025-01-0001a", legs[0].EntryID)
+	assert.Equal(t, "4.00", legs[0].Debit.StringFixed(2))
+	assert.Equal(t, "rule: GITHUB*", legs[0].Evidence)
+	assert.Equal(t, "recurring", legs[0].Tags)
+	assert.Equal(t, "CAD", legs[1].Currency, "known columns populate; the extra is ignored")
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteLegs(&buf, legs))
+	assert.True(t, strings.HasPrefix(buf.String(), Header+"\n"), "written in the canonical layout")
+	assert.NotContains(t, buf.String(), "dana")
+
+	_, err = ReadLegs(strings.NewReader(Header + "\n2025-01-0001a,2025-01-03,5020\n"))
+	require.Error(t, err, "too few columns")
+}
+
+func TestReadLegs_ReorderedColumns(t *testing.T) {
+	csv := "status,credit,debit,description,account_id,date,entry_id,counterparty,currency\n" +
+		"auto-confirmed,,4.00,GitHub,5020,2025-01-03,2025-01-0001a,GitHub,CAD\n" +
+		"auto-confirmed,4.00,,GitHub,1010,2025-01-03,2025-01-0001b,GitHub,CAD\n"
+
+	legs, err := ReadLegs(strings.NewReader(csv))
+	require.NoError(t, err)
+	require.Len(t, legs, 2)
+	assert.Equal(t, "2025-01-0001a", legs[0].EntryID)
+	assert.Equal(t, date(2025, 1, 3), legs[0].Date)
+	assert.Equal(t, 5020, legs[0].AccountID)
+	assert.Equal(t, "4.00", legs[0].Debit.StringFixed(2))
+	assert.True(t, legs[0].Credit.IsZero())
+	assert.Equal(t, "4.00", legs[1].Credit.StringFixed(2))
+	assert.Equal(t, model.StatusAutoConfirmed, legs[1].Status)
+	assert.Equal(t, "GitHub", legs[1].Counterparty)
+	assert.Equal(t, "CAD", legs[1].Currency)
+	assert.Empty(t, legs[1].Evidence, "missing optional columns read as empty")
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteLegs(&buf, legs))
+	assert.True(t, strings.HasPrefix(buf.String(), Header+"\n"), "written in the canonical order")
+
+	_, err = ReadLegs(strings.NewReader("entry_id,date,debit,credit\n2025-01-0001a,2025-01-03,4.00,\n"))
+	require.ErrorContains(t, err, `missing column "account_id"`)
+}
+
+func TestProvenance_RoundTrip(t *testing.T) {
+	created := time.Date(2025, 1, 18, 14, 5, 9, 0, time.UTC)
+	legs := []model.Leg{{
+		EntryID: "2025-01-0001a", Date: date(2025, 1, 3), AccountID: 5020, Debit: dec("4.00"),
+		Status: model.StatusAutoConfirmed, CreatedAt: created, SourceFile: "chase_checking.csv",
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteLegs(&buf, legs))
+	assert.Contains(t, buf.String(), ",2025-01-18T14:05:09Z,chase_checking.csv\n")
+
+	got, err := ReadLegs(&buf)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, created, got[0].CreatedAt)
+	assert.Equal(t, "chase_checking.csv", got[0].SourceFile)
+
+	// A journal from before provenance was recorded.
+	old := "entry_id,date,account_id,description,debit,credit,counterparty,reference,confidence,status,evidence,receipt_hash,tags,notes,currency\n" +
+		"2025-01-0001a,2025-01-03,5020,GitHub,4.00,,,,,auto-confirmed,,,,,USD\n"
+	got, err = ReadLegs(strings.NewReader(old))
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.True(t, got[0].CreatedAt.IsZero())
+	assert.Empty(t, got[0].SourceFile)
+	assert.Equal(t, "USD", got[0].Currency)
+
+	_, err = ReadLegs(strings.NewReader(Header + "\n2025-01-0001a,2025-01-03,5020,GitHub,4.00,,,,,auto-confirmed,,,,,,yesterday,\n"))
+	require.ErrorContains(t, err, "parsing created_at")
+}
+
+func TestCurrency_RoundTrip(t *testing.T) {
+	legs := []model.Leg{{EntryID: "2025-01-0001a", Date: date(2025, 1, 3), AccountID: 5020, Debit: dec("4.00"), Status: model.StatusAutoConfirmed, Currency: "CAD"}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteLegs(&buf, legs))
+	assert.Contains(t, buf.String(), ",CAD,")
+
+	got, err := ReadLegs(&buf)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "CAD", got[0].Currency)
+}
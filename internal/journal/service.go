@@ -1,24 +1,54 @@
 package journal
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
-	"strings"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/shopspring/decimal"
 
 	"github.com/cleared-dev/cleared/internal/id"
 	"github.com/cleared-dev/cleared/internal/model"
+	"github.com/cleared-dev/cleared/internal/receipts"
 )
 
+// ErrEntryNotFound is returned when an operation names an entry that is not
+// in the month's journal.
+var ErrEntryNotFound = errors.New("entry not found")
+
+// ErrMonthClosed is returned when a new entry is dated in a closed month.
+var ErrMonthClosed = errors.New("month is closed")
+
 // Service provides business logic for journal entries.
 type Service struct {
 	repoRoot string
 	accounts AccountChecker
+
+	mu         sync.Mutex
+	monthLocks map[string]*sync.Mutex // keyed by month path
+
+	dryRun   bool
+	staged   map[string][]model.Leg // dry-run legs, keyed by month path; guarded by mu
+	currency string                 // stamped on new legs that name none
+	closed   map[string]bool        // closed months, keyed YYYY-MM
+
+	cache  map[string]cachedMonth // parsed month files, keyed by month path; guarded by mu
+	parses int                    // number of journal files parsed; guarded by mu
+}
+
+// cachedMonth is a parsed journal file plus the stat info it was read at,
+// so edits made outside this Service are noticed.
+type cachedMonth struct {
+	legs    []model.Leg
+	modTime time.Time
+	size    int64
 }
 
 // NewService creates a journal Service.
@@ -26,6 +56,44 @@ func NewService(repoRoot string, accounts AccountChecker) *Service {
 	return &Service{repoRoot: repoRoot, accounts: accounts}
 }
 
+// SetDryRun makes the Service validate new entries and keep them in memory
+// instead of writing journal.csv. Staged legs are returned by ReadMonth, so
+// entry IDs and validation behave as they would for a real run.
+func (s *Service) SetDryRun(dryRun bool) {
+	s.dryRun = dryRun
+}
+
+// SetCurrency sets the currency code stamped on new legs whose entry does
+// not name one, normally the business currency from cleared.yaml.
+func (s *Service) SetCurrency(code string) {
+	s.currency = code
+}
+
+// SetClosedMonths marks months, given by any day in them, as closed:
+// AddDouble, AddSplit, VoidEntry and CorrectEntry refuse to write into them
+// with ErrMonthClosed unless asked to reopen, and AttachReceipt refuses
+// outright.
+func (s *Service) SetClosedMonths(months []time.Time) {
+	s.closed = make(map[string]bool, len(months))
+	for _, m := range months {
+		s.closed[m.Format("2006-01")] = true
+	}
+}
+
+// checkOpen rejects writing into the month of date, if it is closed,
+// unless reopen.
+func (s *Service) checkOpen(date time.Time, reopen bool) error {
+	if month := date.Format("2006-01"); s.closed[month] && !reopen {
+		return fmt.Errorf("%w: %s", ErrMonthClosed, month)
+	}
+	return nil
+}
+
+// monthStart returns the first day of a month.
+func monthStart(year, month int) time.Time {
+	return time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+}
+
 // AddDoubleParams holds parameters for creating a double-entry journal entry.
 type AddDoubleParams struct {
 	Date          time.Time
@@ -40,26 +108,61 @@ type AddDoubleParams struct {
 	Evidence      string
 	Tags          string
 	Notes         string
+	Currency      string // empty uses the Service currency
+	SourceFile    string // import file the entry was booked from, if any
+
+	// Reopen allows booking into a month closed with SetClosedMonths.
+	Reopen bool
 }
 
 // AddDouble creates a balanced double-entry (debit + credit legs), validates,
-// and appends to the month's journal.csv. Returns the entry ID.
+// and appends to the month's journal.csv. Returns the entry ID. An entry
+// that would break an invariant is rejected with ValidationErrors; see
+// SignWarnings for the soft checks, which never block the write. An entry dated in a closed month is rejected with ErrMonthClosed unless
+// params.Reopen is set.
 func (s *Service) AddDouble(params AddDoubleParams) (string, error) {
+	if err := s.checkOpen(params.Date, params.Reopen); err != nil {
+		return "", err
+	}
 	year := params.Date.Year()
 	month := int(params.Date.Month())
 
+	unlock := s.lockMonth(year, month)
+	defer unlock()
+
 	seq, err := s.NextEntrySeq(year, month)
 	if err != nil {
 		return "", err
 	}
 
 	entryID := id.FormatEntryID(year, month, seq)
-	debitLegID := id.FormatLegID(entryID, 0)
-	creditLegID := id.FormatLegID(entryID, 1)
+	legs := doubleLegs(entryID, params)
+	if err := s.checkPostable(legs); err != nil {
+		return "", err
+	}
+	if err := s.appendEntry(year, month, legs); err != nil {
+		return "", err
+	}
+	return entryID, nil
+}
 
-	newLegs := []model.Leg{
+// SignWarnings returns the Soft CheckSigns warnings for the double entry
+// AddDouble booked from params as entryID, or nil when the Service's
+// accounts do not implement AccountTyper. The entry stays booked; callers
+// report the warnings so a flipped sign can be corrected.
+func (s *Service) SignWarnings(entryID string, params AddDoubleParams) ValidationErrors {
+	typer, ok := s.accounts.(AccountTyper)
+	if !ok {
+		return nil
+	}
+	return CheckSigns(doubleLegs(entryID, params), typer)
+}
+
+// doubleLegs builds the debit and credit legs of a double entry.
+func doubleLegs(entryID string, params AddDoubleParams) []model.Leg {
+	return []model.Leg{
 		{
-			EntryID:      debitLegID,
+			EntryID:      id.FormatLegID(entryID, 0),
 			Date:         params.Date,
 			AccountID:    params.DebitAccount,
 			Description:  params.Description,
@@ -71,9 +174,11 @@ func (s *Service) AddDouble(params AddDoubleParams) (string, error) {
 			Evidence:     params.Evidence,
 			Tags:         params.Tags,
 			Notes:        params.Notes,
+			Currency:     params.Currency,
+			SourceFile:   params.SourceFile,
 		},
 		{
-			EntryID:      creditLegID,
+			EntryID:      id.FormatLegID(entryID, 1),
 			Date:         params.Date,
 			AccountID:    params.CreditAccount,
 			Description:  params.Description,
@@ -85,73 +190,569 @@ func (s *Service) AddDouble(params AddDoubleParams) (string, error) {
 			Evidence:     params.Evidence,
 			Tags:         params.Tags,
 			Notes:        params.Notes,
+			Currency:     params.Currency,
+			SourceFile:   params.SourceFile,
 		},
 	}
+}
+
+// SplitLeg is one side of a split entry: an account and the amount posted to it.
+type SplitLeg struct {
+	AccountID int
+	Amount    decimal.Decimal
+}
+
+// SplitParams holds parameters for creating a multi-leg journal entry.
+// Debits and Credits must each have at least one leg and their totals must
+// match.
+type SplitParams struct {
+	Date         time.Time
+	Description  string
+	Debits       []SplitLeg
+	Credits      []SplitLeg
+	Counterparty string
+	Reference    string
+	Confidence   decimal.Decimal
+	Status       model.EntryStatus
+	Evidence     string
+	Tags         string
+	Notes        string
+	Currency     string // empty uses the Service currency
+	SourceFile   string // import file the entry was booked from, if any
+	Reopen       bool   // allow booking into a closed month, as for AddDouble
+}
+
+// AddSplit creates a multi-leg entry with one leg per debit and credit,
+// suffixed a,b,c... (debits first), validates it, and appends it to the
+// month's journal.csv. Returns the entry ID. Closed months are refused as by
+// AddDouble.
+func (s *Service) AddSplit(params SplitParams) (string, error) {
+	if len(params.Debits) == 0 || len(params.Credits) == 0 {
+		return "", errors.New("split entry needs at least one debit and one credit leg")
+	}
+	if err := s.checkOpen(params.Date, params.Reopen); err != nil {
+		return "", err
+	}
+
+	year := params.Date.Year()
+	month := int(params.Date.Month())
+
+	unlock := s.lockMonth(year, month)
+	defer unlock()
+
+	seq, err := s.NextEntrySeq(year, month)
+	if err != nil {
+		return "", err
+	}
+	entryID := id.FormatEntryID(year, month, seq)
+
+	newLegs := make([]model.Leg, 0, len(params.Debits)+len(params.Credits))
+	addLeg := func(sl SplitLeg, debit bool) {
+		leg := model.Leg{
+			EntryID:      id.FormatLegID(entryID, len(newLegs)),
+			Date:         params.Date,
+			AccountID:    sl.AccountID,
+			Description:  params.Description,
+			Counterparty: params.Counterparty,
+			Reference:    params.Reference,
+			Confidence:   params.Confidence,
+			Status:       params.Status,
+			Evidence:     params.Evidence,
+			Tags:         params.Tags,
+			Notes:        params.Notes,
+			Currency:     params.Currency,
+			SourceFile:   params.SourceFile,
+		}
+		if debit {
+			leg.Debit = sl.Amount
+		} else {
+			leg.Credit = sl.Amount
+		}
+		newLegs = append(newLegs, leg)
+	}
+	for _, d := range params.Debits {
+		addLeg(d, true)
+	}
+	for _, c := range params.Credits {
+		addLeg(c, false)
+	}
+	if err := s.checkPostable(newLegs); err != nil {
+		return "", err
+	}
+
+	if err := s.appendEntry(year, month, newLegs); err != nil {
+		return "", err
+	}
+	return entryID, nil
+}
+
+// VoidEntry reverses entryID by appending a new entry with each leg's debit
+// and credit swapped. The original legs are left untouched; the reversal
+// carries status "voided", the original entry ID as its reference, and reason
+// in its notes, so history stays auditable and the month still balances.
+// A closed month is refused with ErrMonthClosed unless reopen.
+func (s *Service) VoidEntry(year, month int, entryID, reason string, reopen bool) error {
+	if err := s.checkOpen(monthStart(year, month), reopen); err != nil {
+		return err
+	}
+	unlock := s.lockMonth(year, month)
+	defer unlock()
+
+	reversal, err := s.reversal(year, month, entryID, reason)
+	if err != nil {
+		return err
+	}
+	return s.appendEntry(year, month, reversal)
+}
+
+// CorrectEntry replaces entryID with a corrected double entry: it voids the
+// original (as VoidEntry does) and books newParams as a new entry with status
+// "user-corrected" whose notes reference the original. Both are written
+// together. The correction must be dated in the same month as the original.
+// A closed month is refused with ErrMonthClosed unless newParams.Reopen.
+func (s *Service) CorrectEntry(year, month int, entryID string, newParams AddDoubleParams) error {
+	if newParams.Date.Year() != year || int(newParams.Date.Month()) != month {
+		return fmt.Errorf("correction of %s must be dated in %04d-%02d", entryID, year, month)
+	}
+	if err := s.checkOpen(newParams.Date, newParams.Reopen); err != nil {
+		return err
+	}
+
+	unlock := s.lockMonth(year, month)
+	defer unlock()
+
+	seq, err := s.NextEntrySeq(year, month)
+	if err != nil {
+		return err
+	}
+	correctionID := id.FormatEntryID(year, month, seq+1) // seq goes to the void
+
+	reversal, err := s.reversal(year, month, entryID, "corrected by "+correctionID)
+	if err != nil {
+		return err
+	}
+
+	newParams.Status = model.StatusUserCorrected
+	note := "corrects " + entryID
+	if newParams.Notes != "" {
+		note += "; " + newParams.Notes
+	}
+	newParams.Notes = note
+
+	// The void may touch archived accounts; only the correction is a new posting.
+	correction := doubleLegs(correctionID, newParams)
+	if err := s.checkPostable(correction); err != nil {
+		return err
+	}
+	return s.appendEntry(year, month, append(reversal, correction...))
+}
+
+// checkPostable rejects new legs booked to an archived account. Unknown
+// accounts are left to ValidateLegs, which reports them with the others.
+func (s *Service) checkPostable(legs []model.Leg) error {
+	for _, leg := range legs {
+		if s.accounts.Exists(leg.AccountID) && !s.accounts.Postable(leg.AccountID) {
+			return fmt.Errorf("account %d is archived and does not accept new postings", leg.AccountID)
+		}
+	}
+	return nil
+}
+
+// AttachReceipt stamps hash, the SHA-256 of a stored receipt, onto every leg
+// of entryID and rewrites the month's journal. Unlike other edits this
+// changes existing rows, since a receipt is evidence for the entry rather
+// than a new booking. In dry-run mode the entry is checked but not changed.
+// An entry in a closed month is refused with ErrMonthClosed.
+func (s *Service) AttachReceipt(entryID, hash string) error {
+	if !receipts.ValidHash(hash) {
+		return fmt.Errorf("invalid receipt hash %q: want 64 lowercase hex characters", hash)
+	}
+	entryID = id.EntryGroup(entryID)
+	year, month, _, err := id.ParseEntryID(entryID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkOpen(monthStart(year, month), false); err != nil {
+		return err
+	}
+
+	unlock := s.lockMonth(year, month)
+	defer unlock()
+
+	legs, err := s.ReadMonth(year, month)
+	if err != nil {
+		return err
+	}
+	found := false
+	for i := range legs {
+		if legs[i].EntryGroup() == entryID {
+			legs[i].ReceiptHash = hash
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s: %w", entryID, ErrEntryNotFound)
+	}
+	if s.dryRun {
+		return nil
+	}
+
+	path := s.monthPath(year, month)
+	defer s.invalidate(path)
+	return writeFileAtomic(path, func(w io.Writer) error {
+		return WriteLegs(w, legs)
+	})
+}
 
+// FindByReceipt returns the legs, across all months, whose receipt_hash is
+// hash.
+func (s *Service) FindByReceipt(hash string) ([]model.Leg, error) {
+	if !receipts.ValidHash(hash) {
+		return nil, fmt.Errorf("invalid receipt hash %q: want 64 lowercase hex characters", hash)
+	}
+	months, err := s.Months()
+	if err != nil {
+		return nil, err
+	}
+	var found []model.Leg
+	for _, m := range months {
+		legs, err := s.ReadMonth(m.Year(), int(m.Month()))
+		if err != nil {
+			return nil, err
+		}
+		for _, leg := range legs {
+			if leg.ReceiptHash == hash {
+				found = append(found, leg)
+			}
+		}
+	}
+	return found, nil
+}
+
+// reversal builds the voiding entry for entryID: the original legs with
+// debits and credits swapped, status "voided", the original entry ID as the
+// reference, and reason in the notes. The caller must hold the month lock.
+func (s *Service) reversal(year, month int, entryID, reason string) ([]model.Leg, error) {
+	legs, err := s.ReadMonth(year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	var original []model.Leg
+	for _, leg := range legs {
+		if leg.EntryGroup() == entryID {
+			original = append(original, leg)
+		}
+		if leg.Status == model.StatusVoided && leg.Reference == entryID {
+			return nil, fmt.Errorf("entry %s is already voided", entryID)
+		}
+	}
+	if len(original) == 0 {
+		return nil, fmt.Errorf("%w: %s in %04d-%02d", ErrEntryNotFound, entryID, year, month)
+	}
+	if original[0].Status == model.StatusVoided {
+		return nil, fmt.Errorf("entry %s is itself a void and cannot be voided", entryID)
+	}
+
+	seq, err := s.NextEntrySeq(year, month)
+	if err != nil {
+		return nil, err
+	}
+	voidID := id.FormatEntryID(year, month, seq)
+
+	reversal := make([]model.Leg, len(original))
+	for i, leg := range original {
+		reversal[i] = model.Leg{
+			EntryID:      id.FormatLegID(voidID, i),
+			Date:         leg.Date,
+			AccountID:    leg.AccountID,
+			Description:  "VOID: " + leg.Description,
+			Debit:        leg.Credit,
+			Credit:       leg.Debit,
+			Counterparty: leg.Counterparty,
+			Reference:    entryID,
+			Confidence:   leg.Confidence,
+			Status:       model.StatusVoided,
+			Evidence:     leg.Evidence,
+			Tags:         leg.Tags,
+			Notes:        reason,
+			Currency:     leg.Currency,
+			SourceFile:   leg.SourceFile,
+		}
+	}
+	return reversal, nil
+}
+
+// lockMonth serializes writers to one month's journal so that reading the
+// next sequence number and appending happen as a unit. Returns the unlock func.
+func (s *Service) lockMonth(year, month int) func() {
+	key := s.monthPath(year, month)
+
+	s.mu.Lock()
+	if s.monthLocks == nil {
+		s.monthLocks = make(map[string]*sync.Mutex)
+	}
+	l, ok := s.monthLocks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		s.monthLocks[key] = l
+	}
+	s.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+// appendEntry validates newLegs together with the month's existing legs and,
+// only if everything passes, appends them to the month's journal.csv in a
+// single write (creating the directory and header if needed). New legs are
+// stamped with the Service currency and the current time where unset.
+func (s *Service) appendEntry(year, month int, newLegs []model.Leg) error {
 	// Read existing legs for validation.
 	existing, err := s.ReadMonth(year, month)
 	if err != nil {
-		return "", err
+		return err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second) // as precise as the CSV keeps it
+	for i := range newLegs {
+		if newLegs[i].Currency == "" {
+			newLegs[i].Currency = s.currency
+		}
+		if newLegs[i].CreatedAt.IsZero() {
+			newLegs[i].CreatedAt = now
+		}
 	}
 
 	// Validate ALL legs together.
 	allLegs := append(existing, newLegs...)
 	if verrs := ValidateLegs(allLegs, s.accounts, year, month); len(verrs) > 0 {
-		msgs := make([]string, len(verrs))
-		for i, ve := range verrs {
-			msgs[i] = ve.Error()
+		return verrs
+	}
+
+	if s.dryRun {
+		key := s.monthPath(year, month)
+		s.mu.Lock()
+		if s.staged == nil {
+			s.staged = make(map[string][]model.Leg)
 		}
-		return "", fmt.Errorf("validation failed: %s", strings.Join(msgs, "; "))
+		s.staged[key] = append(s.staged[key], newLegs...)
+		s.mu.Unlock()
+		return nil
 	}
 
-	// Append to journal file (create dir + header if new).
+	// Rewrite the month's file atomically: a crash mid-write leaves either
+	// the old file or the new one, never a half-written final row.
 	journalPath := s.monthPath(year, month)
-	dir := filepath.Dir(journalPath)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return "", fmt.Errorf("creating journal dir: %w", err)
+	if err := os.MkdirAll(filepath.Dir(journalPath), 0o755); err != nil {
+		return fmt.Errorf("creating journal dir: %w", err)
 	}
 
-	isNew := false
-	if _, err := os.Stat(journalPath); errors.Is(err, fs.ErrNotExist) {
-		isNew = true
+	current, err := os.ReadFile(journalPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("reading journal: %w", err)
 	}
 
-	f, err := os.OpenFile(journalPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
-	if err != nil {
-		return "", fmt.Errorf("opening journal: %w", err)
+	defer s.invalidate(journalPath)
+
+	// A file with an older header is rewritten in the current layout so its
+	// rows keep one column count.
+	if len(current) > 0 && !bytes.HasPrefix(current, []byte(Header+"\n")) {
+		return writeFileAtomic(journalPath, func(w io.Writer) error {
+			return WriteLegs(w, allLegs)
+		})
 	}
-	defer f.Close()
 
-	if isNew {
-		if _, err := fmt.Fprintln(f, Header); err != nil {
-			return "", fmt.Errorf("writing header: %w", err)
+	return writeFileAtomic(journalPath, func(w io.Writer) error {
+		if len(current) == 0 {
+			if _, err := fmt.Fprintln(w, Header); err != nil {
+				return fmt.Errorf("writing header: %w", err)
+			}
+		} else {
+			if _, err := w.Write(current); err != nil {
+				return fmt.Errorf("copying journal: %w", err)
+			}
+			if current[len(current)-1] != '\n' {
+				if _, err := io.WriteString(w, "\n"); err != nil {
+					return fmt.Errorf("copying journal: %w", err)
+				}
+			}
 		}
-	}
+		if err := AppendLegs(w, newLegs); err != nil {
+			return fmt.Errorf("appending legs: %w", err)
+		}
+		return nil
+	})
+}
 
-	if err := AppendLegs(f, newLegs); err != nil {
-		return "", fmt.Errorf("appending legs: %w", err)
+// ReadMonth reads all legs for a given year/month, followed by any legs
+// staged by a dry run.
+//
+// Parsed files are cached per Service and reused while the file's size and
+// modification time are unchanged; the returned slice is always a copy.
+func (s *Service) ReadMonth(year, month int) ([]model.Leg, error) {
+	path := s.monthPath(year, month)
+	legs, err := s.readCached(path)
+	if err != nil {
+		return nil, err
 	}
 
-	return entryID, nil
+	s.mu.Lock()
+	legs = append(legs, s.staged[path]...)
+	s.mu.Unlock()
+	return legs, nil
 }
 
-// ReadMonth reads all legs for a given year/month.
-func (s *Service) ReadMonth(year, month int) ([]model.Leg, error) {
-	path := s.monthPath(year, month)
-	f, err := os.Open(path)
+func (s *Service) readCached(path string) ([]model.Leg, error) {
+	info, err := os.Stat(path)
 	if errors.Is(err, fs.ErrNotExist) {
+		s.invalidate(path)
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("opening journal %s: %w", path, err)
 	}
+
+	s.mu.Lock()
+	c, ok := s.cache[path]
+	s.mu.Unlock()
+	if ok && c.size == info.Size() && c.modTime.Equal(info.ModTime()) {
+		return slices.Clone(c.legs), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening journal %s: %w", path, err)
+	}
 	defer f.Close()
 
 	legs, err := ReadLegs(f)
 	if err != nil {
 		return nil, fmt.Errorf("reading journal %s: %w", path, err)
 	}
-	return legs, nil
+
+	s.mu.Lock()
+	if s.cache == nil {
+		s.cache = make(map[string]cachedMonth)
+	}
+	s.cache[path] = cachedMonth{legs: legs, modTime: info.ModTime(), size: info.Size()}
+	s.parses++
+	s.mu.Unlock()
+	return slices.Clone(legs), nil
+}
+
+// invalidate drops the cached copy of a month file after it is rewritten.
+func (s *Service) invalidate(path string) {
+	s.mu.Lock()
+	delete(s.cache, path)
+	s.mu.Unlock()
+}
+
+// ReadByAccount returns the month's legs posted to accountID. Returns an
+// empty (non-nil) slice when the account has no activity.
+func (s *Service) ReadByAccount(year, month, accountID int) ([]model.Leg, error) {
+	legs, err := s.ReadMonth(year, month)
+	if err != nil {
+		return nil, err
+	}
+	result := []model.Leg{}
+	for _, leg := range legs {
+		if leg.AccountID == accountID {
+			result = append(result, leg)
+		}
+	}
+	return result, nil
+}
+
+// ReadRange reads legs dated between start and end (inclusive, by day)
+// across every month file in the range. Months with no journal are skipped.
+func (s *Service) ReadRange(start, end time.Time) ([]model.Leg, error) {
+	first := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, time.UTC)
+	startDay := start.Format("2006-01-02")
+	endDay := end.Format("2006-01-02")
+
+	var months []time.Time
+	for m := first; !m.After(last); m = m.AddDate(0, 1, 0) {
+		months = append(months, m)
+	}
+	legs, err := s.ReadMonths(months, ReadWorkers)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []model.Leg
+	for _, leg := range legs {
+		day := leg.Date.Format("2006-01-02")
+		if day < startDay || day > endDay {
+			continue
+		}
+		result = append(result, leg)
+	}
+	return result, nil
+}
+
+// ReadWorkers is how many month files ReadRange reads at once.
+const ReadWorkers = 8
+
+// ReadMonths reads each month in months (by year and month) with up to
+// workers files in flight, and returns their legs concatenated in the order
+// given, exactly as reading them one at a time would. If any month fails,
+// the error for the earliest such month is returned.
+func (s *Service) ReadMonths(months []time.Time, workers int) ([]model.Leg, error) {
+	workers = max(1, min(workers, len(months)))
+	perMonth := make([][]model.Leg, len(months))
+	errs := make([]error, len(months))
+
+	// Each worker writes only its own indexes of perMonth and errs; the
+	// cache behind ReadMonth has its own lock.
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for i := range next {
+				perMonth[i], errs[i] = s.ReadMonth(months[i].Year(), int(months[i].Month()))
+			}
+		})
+	}
+	for i := range months {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	var result []model.Leg
+	for i, legs := range perMonth {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		result = append(result, legs...)
+	}
+	return result, nil
+}
+
+// Months returns the first day of every month that has a journal file,
+// oldest first.
+func (s *Service) Months() ([]time.Time, error) {
+	matches, err := filepath.Glob(filepath.Join(s.repoRoot, "[0-9][0-9][0-9][0-9]", "[0-9][0-9]", "journal.csv"))
+	if err != nil {
+		return nil, fmt.Errorf("listing journal months: %w", err)
+	}
+
+	months := make([]time.Time, 0, len(matches))
+	for _, m := range matches {
+		rel, err := filepath.Rel(s.repoRoot, filepath.Dir(m))
+		if err != nil {
+			continue
+		}
+		t, err := time.Parse("2006/01", filepath.ToSlash(rel))
+		if err != nil {
+			continue
+		}
+		months = append(months, t)
+	}
+	slices.SortFunc(months, func(a, b time.Time) int { return a.Compare(b) })
+	return months, nil
 }
 
 // NextEntrySeq returns the next available sequence number for a month.
@@ -175,5 +776,11 @@ func (s *Service) NextEntrySeq(year, month int) (int, error) {
 }
 
 func (s *Service) monthPath(year, month int) string {
-	return filepath.Join(s.repoRoot, fmt.Sprintf("%04d", year), fmt.Sprintf("%02d", month), "journal.csv")
+	return filepath.Join(s.repoRoot, filepath.FromSlash(MonthFile(year, month)))
+}
+
+// MonthFile returns the journal path for a month relative to the repo root,
+// e.g. "2025/01/journal.csv".
+func MonthFile(year, month int) string {
+	return fmt.Sprintf("%04d/%02d/journal.csv", year, month)
 }
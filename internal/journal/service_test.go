@@ -1,10 +1,16 @@
 package journal
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -16,6 +22,7 @@ func TestAddDouble_NewMonth(t *testing.T) {
 	accts := newMockAccounts(1010, 5020)
 	svc := NewService(dir, accts)
 
+	before := time.Now().UTC().Truncate(time.Second)
 	entryID, err := svc.AddDouble(AddDoubleParams{
 		Date:          date(2025, 1, 15),
 		Description:   "GitHub subscription",
@@ -25,9 +32,10 @@ func TestAddDouble_NewMonth(t *testing.T) {
 		Counterparty:  "GitHub",
 		Status:        model.StatusAutoConfirmed,
 		Confidence:    dec("0.98"),
+		SourceFile:    "chase_checking.csv",
 	})
 	require.NoError(t, err)
-	assert.Equal(t, "2025-01-001", entryID)
+	assert.Equal(t, "2025-01-0001", entryID)
 
 	// Verify file was created.
 	path := filepath.Join(dir, "2025", "01", "journal.csv")
@@ -40,6 +48,10 @@ func TestAddDouble_NewMonth(t *testing.T) {
 	require.Len(t, legs, 2)
 	assert.True(t, legs[0].Debit.Equal(dec("4.00")))
 	assert.True(t, legs[1].Credit.Equal(dec("4.00")))
+	for _, leg := range legs {
+		assert.WithinRange(t, leg.CreatedAt, before, time.Now().UTC(), "stamped when written")
+		assert.Equal(t, "chase_checking.csv", leg.SourceFile)
+	}
 }
 
 func TestAddDouble_ExistingMonth(t *testing.T) {
@@ -70,7 +82,7 @@ func TestAddDouble_ExistingMonth(t *testing.T) {
 		Confidence:    dec("0.90"),
 	})
 	require.NoError(t, err)
-	assert.Equal(t, "2025-01-002", entryID)
+	assert.Equal(t, "2025-01-0002", entryID)
 
 	legs, err := svc.ReadMonth(2025, 1)
 	require.NoError(t, err)
@@ -93,6 +105,9 @@ func TestAddDouble_ValidationFailure(t *testing.T) {
 	})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "validation failed")
+	var verrs ValidationErrors
+	require.ErrorAs(t, err, &verrs)
+	assert.Equal(t, 3, verrs[0].Invariant)
 
 	// Verify nothing was written.
 	legs, err := svc.ReadMonth(2025, 1)
@@ -145,6 +160,489 @@ func TestAddDouble_DirectoryCreation(t *testing.T) {
 	assert.True(t, info.IsDir())
 }
 
+func TestAddSplit_ThreeLegs(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020, 5030)
+	svc := NewService(dir, accts)
+
+	entryID, err := svc.AddSplit(SplitParams{
+		Date:        date(2025, 2, 8),
+		Description: "Office Depot run",
+		Debits: []SplitLeg{
+			{AccountID: 5030, Amount: dec("45.00")},
+			{AccountID: 5020, Amount: dec("55.00")},
+		},
+		Credits:    []SplitLeg{{AccountID: 1010, Amount: dec("100.00")}},
+		Status:     model.StatusPendingReview,
+		Confidence: dec("0.80"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2025-02-0001", entryID)
+
+	legs, err := svc.ReadMonth(2025, 2)
+	require.NoError(t, err)
+	require.Len(t, legs, 3)
+	assert.Equal(t, "2025-02-0001a", legs[0].EntryID)
+	assert.Equal(t, "2025-02-0001b", legs[1].EntryID)
+	assert.Equal(t, "2025-02-0001c", legs[2].EntryID)
+	assert.Equal(t, 5030, legs[0].AccountID)
+	assert.True(t, legs[1].Debit.Equal(dec("55.00")))
+	assert.True(t, legs[2].Credit.Equal(dec("100.00")))
+	assert.Empty(t, ValidateLegs(legs, accts, 2025, 2))
+}
+
+func TestAddSplit_ManyLegs(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020)
+	svc := NewService(dir, accts)
+
+	debits := make([]SplitLeg, 30)
+	for i := range debits {
+		debits[i] = SplitLeg{AccountID: 5020, Amount: dec("1.00")}
+	}
+	entryID, err := svc.AddSplit(SplitParams{
+		Date:        date(2025, 2, 8),
+		Description: "Many small items",
+		Debits:      debits,
+		Credits:     []SplitLeg{{AccountID: 1010, Amount: dec("30.00")}},
+		Status:      model.StatusPendingReview,
+		Confidence:  dec("0.80"),
+	})
+	require.NoError(t, err)
+
+	legs, err := svc.ReadMonth(2025, 2)
+	require.NoError(t, err)
+	require.Len(t, legs, 31)
+	assert.Equal(t, entryID+"z", legs[25].EntryID)
+	assert.Equal(t, entryID+"aa", legs[26].EntryID)
+	assert.Equal(t, entryID+"ae", legs[30].EntryID)
+	for _, leg := range legs {
+		assert.Equal(t, entryID, leg.EntryGroup())
+	}
+	assert.Empty(t, ValidateLegs(legs, accts, 2025, 2))
+}
+
+func TestAddSplit_Unbalanced(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020, 5030)
+	svc := NewService(dir, accts)
+
+	_, err := svc.AddSplit(SplitParams{
+		Date:        date(2025, 2, 8),
+		Description: "Unbalanced",
+		Debits: []SplitLeg{
+			{AccountID: 5030, Amount: dec("45.00")},
+			{AccountID: 5020, Amount: dec("50.00")},
+		},
+		Credits:    []SplitLeg{{AccountID: 1010, Amount: dec("100.00")}},
+		Status:     model.StatusPendingReview,
+		Confidence: dec("0.80"),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invariant 1")
+
+	legs, err := svc.ReadMonth(2025, 2)
+	require.NoError(t, err)
+	assert.Empty(t, legs, "nothing written on failure")
+}
+
+func TestAddSplit_MissingSide(t *testing.T) {
+	svc := NewService(t.TempDir(), newMockAccounts(1010, 5020))
+
+	_, err := svc.AddSplit(SplitParams{
+		Date:   date(2025, 2, 8),
+		Debits: []SplitLeg{{AccountID: 5020, Amount: dec("10.00")}},
+	})
+	require.Error(t, err)
+}
+
+func TestVoidEntry(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020)
+	svc := NewService(dir, accts)
+
+	entryID, err := svc.AddDouble(AddDoubleParams{
+		Date:          date(2025, 1, 15),
+		Description:   "GitHub subscription",
+		DebitAccount:  5020,
+		CreditAccount: 1010,
+		Amount:        dec("4.00"),
+		Status:        model.StatusAutoConfirmed,
+		Confidence:    dec("0.98"),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.VoidEntry(2025, 1, entryID, "duplicate charge", false))
+
+	legs, err := svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	require.Len(t, legs, 4)
+
+	// Original legs are untouched.
+	assert.Equal(t, "2025-01-0001a", legs[0].EntryID)
+	assert.True(t, legs[0].Debit.Equal(dec("4.00")))
+	assert.Equal(t, model.StatusAutoConfirmed, legs[0].Status)
+	assert.True(t, legs[1].Credit.Equal(dec("4.00")))
+
+	// Reversal swaps sides and references the original.
+	assert.Equal(t, "2025-01-0002a", legs[2].EntryID)
+	assert.Equal(t, 5020, legs[2].AccountID)
+	assert.True(t, legs[2].Credit.Equal(dec("4.00")))
+	assert.True(t, legs[3].Debit.Equal(dec("4.00")))
+	for _, leg := range legs[2:] {
+		assert.Equal(t, model.StatusVoided, leg.Status)
+		assert.Equal(t, entryID, leg.Reference)
+		assert.Equal(t, "duplicate charge", leg.Notes)
+	}
+
+	assert.Empty(t, ValidateLegs(legs, accts, 2025, 1), "month still validates after void")
+}
+
+func TestVoidEntry_Errors(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020)
+	svc := NewService(dir, accts)
+
+	entryID, err := svc.AddDouble(AddDoubleParams{
+		Date:          date(2025, 1, 15),
+		Description:   "GitHub subscription",
+		DebitAccount:  5020,
+		CreditAccount: 1010,
+		Amount:        dec("4.00"),
+		Status:        model.StatusAutoConfirmed,
+		Confidence:    dec("0.98"),
+	})
+	require.NoError(t, err)
+
+	err = svc.VoidEntry(2025, 1, "2025-01-0009", "typo", false)
+	require.ErrorIs(t, err, ErrEntryNotFound)
+
+	require.NoError(t, svc.VoidEntry(2025, 1, entryID, "duplicate", false))
+
+	err = svc.VoidEntry(2025, 1, entryID, "again", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already voided")
+
+	err = svc.VoidEntry(2025, 1, "2025-01-0002", "void the void", false)
+	require.Error(t, err)
+}
+
+func TestAddDouble_Concurrent(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020)
+	svc := NewService(dir, accts)
+
+	const n = 20
+	ids := make([]string, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids[i], errs[i] = svc.AddDouble(AddDoubleParams{
+				Date:          date(2025, 1, 1+i),
+				Description:   "concurrent",
+				DebitAccount:  5020,
+				CreditAccount: 1010,
+				Amount:        dec("1.00"),
+				Status:        model.StatusAutoConfirmed,
+				Confidence:    dec("0.95"),
+			})
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	slices.Sort(ids)
+	for i, got := range ids {
+		assert.Equal(t, fmt.Sprintf("2025-01-%04d", i+1), got, "IDs must be unique and contiguous")
+	}
+
+	legs, err := svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	assert.Len(t, legs, 2*n)
+	assert.Empty(t, ValidateLegs(legs, accts, 2025, 1))
+}
+
+func TestAddDouble_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020)
+	svc := NewService(dir, accts)
+	svc.SetDryRun(true)
+
+	for i, want := range []string{"2025-01-0001", "2025-01-0002"} {
+		entryID, err := svc.AddDouble(AddDoubleParams{
+			Date:          date(2025, 1, 10+i),
+			Description:   "dry",
+			DebitAccount:  5020,
+			CreditAccount: 1010,
+			Amount:        dec("5.00"),
+			Status:        model.StatusAutoConfirmed,
+			Confidence:    dec("0.95"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, want, entryID)
+	}
+
+	_, err := os.Stat(filepath.Join(dir, "2025"))
+	assert.ErrorIs(t, err, os.ErrNotExist, "dry run must not touch disk")
+
+	legs, err := svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	assert.Len(t, legs, 4, "staged legs are visible")
+
+	// Validation still applies.
+	_, err = svc.AddDouble(AddDoubleParams{
+		Date:          date(2025, 1, 20),
+		DebitAccount:  9999,
+		CreditAccount: 1010,
+		Amount:        dec("5.00"),
+		Status:        model.StatusAutoConfirmed,
+	})
+	require.Error(t, err)
+}
+
+func TestAddDouble_ArchivedAccount(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020, 5030)
+	svc := NewService(dir, accts)
+
+	entryID, err := svc.AddDouble(AddDoubleParams{
+		Date:          date(2025, 1, 10),
+		Description:   "Old supplier",
+		DebitAccount:  5030,
+		CreditAccount: 1010,
+		Amount:        dec("20.00"),
+		Status:        model.StatusAutoConfirmed,
+	})
+	require.NoError(t, err)
+	accts.archived = map[int]bool{5030: true}
+
+	_, err = svc.AddDouble(AddDoubleParams{
+		Date:          date(2025, 1, 11),
+		Description:   "Old supplier again",
+		DebitAccount:  5030,
+		CreditAccount: 1010,
+		Amount:        dec("20.00"),
+		Status:        model.StatusAutoConfirmed,
+	})
+	require.EqualError(t, err, "account 5030 is archived and does not accept new postings")
+
+	_, err = svc.AddSplit(SplitParams{
+		Date:    date(2025, 1, 11),
+		Debits:  []SplitLeg{{AccountID: 5020, Amount: dec("10.00")}, {AccountID: 5030, Amount: dec("10.00")}},
+		Credits: []SplitLeg{{AccountID: 1010, Amount: dec("20.00")}},
+		Status:  model.StatusAutoConfirmed,
+	})
+	require.Error(t, err)
+
+	// History on the archived account still validates, and can be corrected
+	// away from it.
+	require.NoError(t, svc.CorrectEntry(2025, 1, entryID, AddDoubleParams{
+		Date:          date(2025, 1, 10),
+		Description:   "Old supplier - software",
+		DebitAccount:  5020,
+		CreditAccount: 1010,
+		Amount:        dec("20.00"),
+	}))
+	legs, err := svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	assert.Len(t, legs, 6)
+}
+
+func TestAddDouble_ClosedMonth(t *testing.T) {
+	dir := t.TempDir()
+	svc := NewService(dir, newMockAccounts(1010, 5020))
+	svc.SetClosedMonths([]time.Time{date(2025, 1, 1)})
+
+	params := AddDoubleParams{
+		Date:          date(2025, 1, 31),
+		Description:   "Late invoice",
+		DebitAccount:  5020,
+		CreditAccount: 1010,
+		Amount:        dec("20.00"),
+		Status:        model.StatusAutoConfirmed,
+	}
+	_, err := svc.AddDouble(params)
+	require.ErrorIs(t, err, ErrMonthClosed)
+	assert.EqualError(t, err, "month is closed: 2025-01")
+	_, err = svc.AddSplit(SplitParams{
+		Date:    date(2025, 1, 31),
+		Debits:  []SplitLeg{{AccountID: 5020, Amount: dec("20.00")}},
+		Credits: []SplitLeg{{AccountID: 1010, Amount: dec("20.00")}},
+	})
+	require.ErrorIs(t, err, ErrMonthClosed)
+	_, err = os.Stat(filepath.Join(dir, "2025", "01", "journal.csv"))
+	require.ErrorIs(t, err, os.ErrNotExist, "nothing written")
+
+	params.Reopen = true
+	entryID, err := svc.AddDouble(params)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01-0001", entryID)
+	second, err := svc.AddDouble(params)
+	require.NoError(t, err)
+
+	// Voids, corrections and receipts rewrite the closed month too.
+	require.ErrorIs(t, svc.VoidEntry(2025, 1, entryID, "duplicate", false), ErrMonthClosed)
+	correction := params
+	correction.Reopen = false
+	correction.Amount = dec("25.00")
+	require.ErrorIs(t, svc.CorrectEntry(2025, 1, second, correction), ErrMonthClosed)
+	require.ErrorIs(t, svc.AttachReceipt(entryID, strings.Repeat("a", 64)), ErrMonthClosed)
+	legs, err := svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	assert.Len(t, legs, 4, "nothing written")
+
+	require.NoError(t, svc.VoidEntry(2025, 1, entryID, "duplicate", true))
+	correction.Reopen = true
+	require.NoError(t, svc.CorrectEntry(2025, 1, second, correction))
+	legs, err = svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	assert.Len(t, legs, 10, "void, plus void and correction")
+
+	params.Reopen = false
+	params.Date = date(2025, 2, 1)
+	_, err = svc.AddDouble(params)
+	require.NoError(t, err, "the next month is open")
+}
+
+func TestAddDouble_SignCheck(t *testing.T) {
+	dir := t.TempDir()
+	svc := NewService(dir, newTypedAccounts())
+
+	flipped := AddDoubleParams{
+		Date:          date(2025, 1, 10),
+		Description:   "Client payment",
+		DebitAccount:  4010,
+		CreditAccount: 1010,
+		Amount:        dec("3500.00"),
+		Status:        model.StatusAutoConfirmed,
+	}
+	entryID, err := svc.AddDouble(flipped)
+	require.NoError(t, err, "a soft check never blocks the write")
+	legs, err := svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	assert.Len(t, legs, 2)
+
+	warnings := svc.SignWarnings(entryID, flipped)
+	require.Len(t, warnings, 1, "a deposit booked with its sign flipped")
+	assert.True(t, warnings[0].Soft)
+	assert.Equal(t, "2025-01-0001a", warnings[0].EntryID)
+
+	payment := flipped
+	payment.DebitAccount, payment.CreditAccount = 1010, 4010
+	entryID, err = svc.AddDouble(payment)
+	require.NoError(t, err)
+	assert.Empty(t, svc.SignWarnings(entryID, payment))
+
+	assert.Nil(t, NewService(dir, newMockAccounts(1010, 4010)).SignWarnings(entryID, flipped),
+		"no warnings without account types")
+}
+
+func TestCorrectEntry(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020, 5030)
+	svc := NewService(dir, accts)
+
+	entryID, err := svc.AddDouble(AddDoubleParams{
+		Date:          date(2025, 1, 18),
+		Description:   "Amazon",
+		DebitAccount:  5030,
+		CreditAccount: 1010,
+		Amount:        dec("42.99"),
+		Status:        model.StatusPendingReview,
+		Confidence:    dec("0.70"),
+	})
+	require.NoError(t, err)
+
+	err = svc.CorrectEntry(2025, 1, entryID, AddDoubleParams{
+		Date:          date(2025, 1, 18),
+		Description:   "Amazon - software license",
+		DebitAccount:  5020,
+		CreditAccount: 1010,
+		Amount:        dec("42.99"),
+		Status:        model.StatusAutoConfirmed, // overridden
+		Confidence:    dec("1.00"),
+	})
+	require.NoError(t, err)
+
+	legs, err := svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	require.Len(t, legs, 6, "original + void + correction")
+	assert.Empty(t, ValidateLegs(legs, accts, 2025, 1), "month remains balanced")
+
+	// Original untouched.
+	assert.Equal(t, "2025-01-0001a", legs[0].EntryID)
+	assert.Equal(t, 5030, legs[0].AccountID)
+	assert.Equal(t, model.StatusPendingReview, legs[0].Status)
+
+	// Void reverses it and points at the correction.
+	assert.Equal(t, "2025-01-0002a", legs[2].EntryID)
+	assert.Equal(t, model.StatusVoided, legs[2].Status)
+	assert.Equal(t, entryID, legs[2].Reference)
+	assert.Equal(t, "corrected by 2025-01-0003", legs[2].Notes)
+	assert.True(t, legs[2].Credit.Equal(dec("42.99")))
+
+	// Correction books the new account and references the original.
+	assert.Equal(t, "2025-01-0003a", legs[4].EntryID)
+	assert.Equal(t, 5020, legs[4].AccountID)
+	assert.Equal(t, model.StatusUserCorrected, legs[4].Status)
+	assert.Equal(t, "corrects 2025-01-0001", legs[4].Notes)
+
+	// Net effect: expense moved from 5030 to 5020.
+	net := map[int]decimal.Decimal{}
+	for _, leg := range legs {
+		net[leg.AccountID] = net[leg.AccountID].Add(leg.Debit).Sub(leg.Credit)
+	}
+	assert.True(t, net[5030].IsZero())
+	assert.True(t, net[5020].Equal(dec("42.99")))
+}
+
+func TestCorrectEntry_Errors(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020)
+	svc := NewService(dir, accts)
+
+	entryID, err := svc.AddDouble(AddDoubleParams{
+		Date:          date(2025, 1, 18),
+		Description:   "Amazon",
+		DebitAccount:  5020,
+		CreditAccount: 1010,
+		Amount:        dec("42.99"),
+		Status:        model.StatusPendingReview,
+		Confidence:    dec("0.70"),
+	})
+	require.NoError(t, err)
+
+	fix := AddDoubleParams{
+		Date:          date(2025, 1, 18),
+		Description:   "Amazon",
+		DebitAccount:  5020,
+		CreditAccount: 1010,
+		Amount:        dec("24.99"),
+	}
+
+	err = svc.CorrectEntry(2025, 1, "2025-01-0042", fix)
+	require.Error(t, err)
+
+	moved := fix
+	moved.Date = date(2025, 2, 1)
+	err = svc.CorrectEntry(2025, 1, entryID, moved)
+	require.Error(t, err)
+
+	badAcct := fix
+	badAcct.DebitAccount = 9999
+	err = svc.CorrectEntry(2025, 1, entryID, badAcct)
+	require.Error(t, err)
+
+	legs, err := svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	assert.Len(t, legs, 2, "failed corrections write nothing, not even the void")
+}
+
 func TestNextEntrySeq(t *testing.T) {
 	dir := t.TempDir()
 	accts := newMockAccounts(1010, 5020)
@@ -172,6 +670,227 @@ func TestNextEntrySeq(t *testing.T) {
 	assert.Equal(t, 2, seq)
 }
 
+func TestReadByAccount(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 4010, 5020, 5030)
+	svc := NewService(dir, accts)
+
+	for _, e := range []struct {
+		debit, credit int
+		amount        string
+	}{
+		{5020, 1010, "4.00"},
+		{5030, 1010, "42.99"},
+		{1010, 4010, "3500.00"},
+		{5020, 1010, "127.50"},
+	} {
+		_, err := svc.AddDouble(AddDoubleParams{
+			Date:          date(2025, 1, 10),
+			Description:   "entry",
+			DebitAccount:  e.debit,
+			CreditAccount: e.credit,
+			Amount:        dec(e.amount),
+			Status:        model.StatusAutoConfirmed,
+			Confidence:    dec("0.95"),
+		})
+		require.NoError(t, err)
+	}
+
+	legs, err := svc.ReadByAccount(2025, 1, 5020)
+	require.NoError(t, err)
+	require.Len(t, legs, 2)
+	for _, leg := range legs {
+		assert.Equal(t, 5020, leg.AccountID)
+	}
+	assert.Equal(t, "2025-01-0001a", legs[0].EntryID)
+	assert.Equal(t, "2025-01-0004a", legs[1].EntryID)
+
+	legs, err = svc.ReadByAccount(2025, 1, 1010)
+	require.NoError(t, err)
+	assert.Len(t, legs, 4)
+
+	legs, err = svc.ReadByAccount(2025, 1, 2010)
+	require.NoError(t, err)
+	assert.NotNil(t, legs)
+	assert.Empty(t, legs)
+
+	legs, err = svc.ReadByAccount(2025, 6, 5020)
+	require.NoError(t, err)
+	assert.Empty(t, legs, "month with no journal")
+}
+
+func TestReadRange_YearBoundary(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020)
+	svc := NewService(dir, accts)
+
+	// Dec 2024 and Feb 2025 have entries; Jan 2025 has no journal file.
+	for _, d := range []time.Time{date(2024, 11, 30), date(2024, 12, 5), date(2025, 2, 10), date(2025, 3, 1)} {
+		_, err := svc.AddDouble(AddDoubleParams{
+			Date:          d,
+			Description:   "entry " + d.Format("2006-01-02"),
+			DebitAccount:  5020,
+			CreditAccount: 1010,
+			Amount:        dec("10.00"),
+			Status:        model.StatusPendingReview,
+			Confidence:    dec("0.70"),
+		})
+		require.NoError(t, err)
+	}
+
+	legs, err := svc.ReadRange(date(2024, 12, 1), date(2025, 2, 28))
+	require.NoError(t, err)
+	require.Len(t, legs, 4, "Dec + Feb entries, Nov and Mar excluded")
+	assert.Equal(t, "2024-12-0001a", legs[0].EntryID)
+	assert.Equal(t, "2025-02-0001b", legs[3].EntryID)
+}
+
+func TestReadRange_DayBounds(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020)
+	svc := NewService(dir, accts)
+
+	for _, day := range []int{1, 15, 31} {
+		_, err := svc.AddDouble(AddDoubleParams{
+			Date:          date(2025, 1, day),
+			Description:   "entry",
+			DebitAccount:  5020,
+			CreditAccount: 1010,
+			Amount:        dec("1.00"),
+			Status:        model.StatusPendingReview,
+			Confidence:    dec("0.70"),
+		})
+		require.NoError(t, err)
+	}
+
+	legs, err := svc.ReadRange(date(2025, 1, 15), date(2025, 1, 31))
+	require.NoError(t, err)
+	assert.Len(t, legs, 4)
+}
+
+func TestReadRange_Empty(t *testing.T) {
+	svc := NewService(t.TempDir(), newMockAccounts())
+
+	legs, err := svc.ReadRange(date(2025, 1, 1), date(2025, 3, 31))
+	require.NoError(t, err)
+	assert.Empty(t, legs)
+}
+
+func TestMonths(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020)
+	svc := NewService(dir, accts)
+
+	months, err := svc.Months()
+	require.NoError(t, err)
+	assert.Empty(t, months)
+
+	for _, d := range []time.Time{date(2025, 2, 1), date(2024, 12, 31)} {
+		_, err := svc.AddDouble(AddDoubleParams{
+			Date:          d,
+			Description:   "entry",
+			DebitAccount:  5020,
+			CreditAccount: 1010,
+			Amount:        dec("1.00"),
+			Status:        model.StatusPendingReview,
+			Confidence:    dec("0.70"),
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "accounts"), 0o755))
+
+	months, err = svc.Months()
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{date(2024, 12, 1), date(2025, 2, 1)}, months)
+}
+
+func TestReadMonth_Cache(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020)
+	svc := NewService(dir, accts)
+
+	add := func(day int) {
+		t.Helper()
+		_, err := svc.AddDouble(AddDoubleParams{
+			Date:          date(2025, 1, day),
+			Description:   "entry",
+			DebitAccount:  5020,
+			CreditAccount: 1010,
+			Amount:        dec("1.00"),
+			Status:        model.StatusAutoConfirmed,
+			Confidence:    dec("0.95"),
+		})
+		require.NoError(t, err)
+	}
+
+	add(1)
+	legs, err := svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	require.Len(t, legs, 2)
+	parses := svc.parses
+
+	// Repeated reads of an unchanged file don't re-parse it.
+	for range 5 {
+		_, err := svc.ReadMonth(2025, 1)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, parses, svc.parses)
+
+	// An append invalidates the cache; the next read sees the new legs.
+	add(2)
+	legs, err = svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	assert.Len(t, legs, 4)
+
+	// Callers can't corrupt the cache by mutating the result.
+	legs[0].AccountID = 9999
+	legs, err = svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 5020, legs[0].AccountID)
+}
+
+func TestReadMonth_CacheSeesExternalEdits(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020)
+	svc := NewService(dir, accts)
+	other := NewService(dir, accts)
+
+	_, err := svc.AddDouble(AddDoubleParams{
+		Date:          date(2025, 1, 1),
+		Description:   "first",
+		DebitAccount:  5020,
+		CreditAccount: 1010,
+		Amount:        dec("1.00"),
+		Status:        model.StatusAutoConfirmed,
+		Confidence:    dec("0.95"),
+	})
+	require.NoError(t, err)
+	legs, err := svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	require.Len(t, legs, 2)
+
+	// A different writer (another Service, or a human edit) changes the file.
+	_, err = other.AddDouble(AddDoubleParams{
+		Date:          date(2025, 1, 2),
+		Description:   "second",
+		DebitAccount:  5020,
+		CreditAccount: 1010,
+		Amount:        dec("2.00"),
+		Status:        model.StatusAutoConfirmed,
+		Confidence:    dec("0.95"),
+	})
+	require.NoError(t, err)
+
+	legs, err = svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	assert.Len(t, legs, 4, "stale cache must not hide external writes")
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "2025", "01", "journal.csv")))
+	legs, err = svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	assert.Empty(t, legs)
+}
+
 func TestReadMonth_NonExistent(t *testing.T) {
 	dir := t.TempDir()
 	accts := newMockAccounts()
@@ -181,3 +900,206 @@ func TestReadMonth_NonExistent(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, legs)
 }
+
+func TestAddDouble_Currency(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "2025", "01", "journal.csv")
+	legacy := "entry_id,date,account_id,description,debit,credit,counterparty,reference,confidence,status,evidence,receipt_hash,tags,notes\n" +
+		"2025-01-0001a,2025-01-03,5020,GitHub,4.00,,,,,auto-confirmed,,,,\n" +
+		"2025-01-0001b,2025-01-03,1010,GitHub,,4.00,,,,auto-confirmed,,,,\n"
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(legacy), 0o644))
+
+	svc := NewService(dir, newMockAccounts(1010, 5020))
+	svc.SetCurrency("CAD")
+
+	_, err := svc.AddDouble(AddDoubleParams{
+		Date:          date(2025, 1, 10),
+		Description:   "Hosting",
+		DebitAccount:  5020,
+		CreditAccount: 1010,
+		Amount:        dec("20.00"),
+		Status:        model.StatusAutoConfirmed,
+	})
+	require.NoError(t, err)
+	_, err = svc.AddDouble(AddDoubleParams{
+		Date:          date(2025, 1, 11),
+		Description:   "US vendor",
+		DebitAccount:  5020,
+		CreditAccount: 1010,
+		Amount:        dec("15.00"),
+		Status:        model.StatusAutoConfirmed,
+		Currency:      "USD",
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), Header+"\n"), "legacy file is rewritten with the current header")
+
+	legs, err := svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	require.Len(t, legs, 6)
+	assert.Equal(t, []string{"", "", "CAD", "CAD", "USD", "USD"},
+		[]string{legs[0].Currency, legs[1].Currency, legs[2].Currency, legs[3].Currency, legs[4].Currency, legs[5].Currency})
+}
+
+func TestVoidEntry_KeepsCurrency(t *testing.T) {
+	svc := NewService(t.TempDir(), newMockAccounts(1010, 5020))
+	svc.SetCurrency("CAD")
+
+	entryID, err := svc.AddDouble(AddDoubleParams{
+		Date:          date(2025, 1, 11),
+		Description:   "US vendor",
+		DebitAccount:  5020,
+		CreditAccount: 1010,
+		Amount:        dec("15.00"),
+		Status:        model.StatusAutoConfirmed,
+		Evidence:      "rule: US VENDOR*",
+		Currency:      "USD",
+		SourceFile:    "chase_checking.csv",
+	})
+	require.NoError(t, err)
+	require.NoError(t, svc.VoidEntry(2025, 1, entryID, "duplicate", false))
+
+	legs, err := svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	require.Len(t, legs, 4)
+	for _, leg := range legs[2:] {
+		assert.Equal(t, model.StatusVoided, leg.Status)
+		assert.Equal(t, "USD", leg.Currency, "the void is in the entry's currency, not the business's")
+		assert.Equal(t, "rule: US VENDOR*", leg.Evidence)
+		assert.Equal(t, "chase_checking.csv", leg.SourceFile)
+	}
+	assert.Empty(t, ValidateLegs(legs, newMockAccounts(1010, 5020), 2025, 1))
+}
+
+func TestAttachReceipt(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020)
+	svc := NewService(dir, accts)
+
+	addEntry := func(day int) string {
+		entryID, err := svc.AddDouble(AddDoubleParams{
+			Date:          date(2025, 1, day),
+			Description:   "GitHub subscription",
+			DebitAccount:  5020,
+			CreditAccount: 1010,
+			Amount:        dec("4.00"),
+			Status:        model.StatusAutoConfirmed,
+			Confidence:    dec("0.98"),
+		})
+		require.NoError(t, err)
+		return entryID
+	}
+	first, second := addEntry(10), addEntry(15)
+	hash := strings.Repeat("ab", 32)
+
+	require.NoError(t, svc.AttachReceipt(second, hash))
+
+	legs, err := svc.ReadMonth(2025, 1)
+	require.NoError(t, err)
+	require.Len(t, legs, 4)
+	assert.Empty(t, legs[0].ReceiptHash)
+	assert.Empty(t, legs[1].ReceiptHash)
+	assert.Equal(t, hash, legs[2].ReceiptHash)
+	assert.Equal(t, hash, legs[3].ReceiptHash)
+
+	found, err := svc.FindByReceipt(hash)
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, second+"a", found[0].EntryID)
+
+	found, err = svc.FindByReceipt(strings.Repeat("cd", 32))
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	err = svc.AttachReceipt(first, "not-a-hash")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid receipt hash")
+
+	err = svc.AttachReceipt("2025-01-0099", hash)
+	require.ErrorIs(t, err, ErrEntryNotFound)
+
+	_, err = svc.FindByReceipt("")
+	require.Error(t, err)
+}
+
+// seedMonths writes perMonth balanced entries into each of n consecutive
+// months starting January 2024, bypassing AddDouble for speed.
+func seedMonths(t testing.TB, dir string, n, perMonth int) []time.Time {
+	t.Helper()
+	var months []time.Time
+	for i := range n {
+		m := date(2024, 1, 1).AddDate(0, i, 0)
+		months = append(months, m)
+		var legs []model.Leg
+		for e := range perMonth {
+			entryID := fmt.Sprintf("%d-%02d-%04d", m.Year(), m.Month(), e+1)
+			amount := dec(fmt.Sprintf("%d.%02d", i+1, e%100))
+			base := model.Leg{Date: m.AddDate(0, 0, e%28), Description: "seeded", Status: model.StatusAutoConfirmed}
+			debit, credit := base, base
+			debit.EntryID, debit.AccountID, debit.Debit = entryID+"a", 5020, amount
+			credit.EntryID, credit.AccountID, credit.Credit = entryID+"b", 1010, amount
+			legs = append(legs, debit, credit)
+		}
+		path := filepath.Join(dir, m.Format("2006"), m.Format("01"), "journal.csv")
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		f, err := os.Create(path)
+		require.NoError(t, err)
+		require.NoError(t, WriteLegs(f, legs))
+		require.NoError(t, f.Close())
+	}
+	return months
+}
+
+func TestReadMonths_ParallelMatchesSerial(t *testing.T) {
+	dir := t.TempDir()
+	accts := newMockAccounts(1010, 5020)
+	months := seedMonths(t, dir, 24, 20)
+
+	serial, err := NewService(dir, accts).ReadMonths(months, 1)
+	require.NoError(t, err)
+	require.Len(t, serial, 24*20*2)
+
+	for _, workers := range []int{2, 8, 64} {
+		parallel, err := NewService(dir, accts).ReadMonths(months, workers)
+		require.NoError(t, err)
+		assert.Equal(t, serial, parallel, "%d workers", workers)
+	}
+
+	legs, err := NewService(dir, accts).ReadRange(months[0], months[23].AddDate(0, 1, -1))
+	require.NoError(t, err)
+	assert.Equal(t, serial, legs, "ReadRange reads in parallel with the same result")
+}
+
+func TestReadMonths_Error(t *testing.T) {
+	dir := t.TempDir()
+	months := seedMonths(t, dir, 6, 2)
+	for _, m := range []time.Time{months[2], months[4]} {
+		path := filepath.Join(dir, m.Format("2006"), m.Format("01"), "journal.csv")
+		require.NoError(t, os.WriteFile(path, []byte(Header+"\nnot,enough,fields\n"), 0o644))
+	}
+
+	_, err := NewService(dir, newMockAccounts(1010, 5020)).ReadMonths(months, 4)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), filepath.Join("2024", "03"), "earliest failing month is reported")
+}
+
+func BenchmarkReadMonths(b *testing.B) {
+	dir := b.TempDir()
+	accts := newMockAccounts(1010, 5020)
+	months := seedMonths(b, dir, 36, 200)
+
+	for _, workers := range []int{1, ReadWorkers} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for b.Loop() {
+				// A fresh service each time so the month cache doesn't hide
+				// the parsing.
+				if _, err := NewService(dir, accts).ReadMonths(months, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
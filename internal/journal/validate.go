@@ -2,6 +2,9 @@ package journal
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/shopspring/decimal"
 
@@ -9,25 +12,124 @@ import (
 	"github.com/cleared-dev/cleared/internal/model"
 )
 
-// ValidationError describes a single invariant violation.
+// ValidationError describes a single invariant violation. A Soft error is a
+// warning from a check the caller may override, not a broken invariant.
 type ValidationError struct {
 	Invariant   int
 	EntryID     string
 	Description string
+	Soft        bool
 }
 
 func (e ValidationError) Error() string {
+	if e.Soft {
+		return fmt.Sprintf("check %d [%s]: %s", e.Invariant, e.EntryID, e.Description)
+	}
 	return fmt.Sprintf("invariant %d [%s]: %s", e.Invariant, e.EntryID, e.Description)
 }
 
-// AccountChecker tests whether an account ID exists in the chart of accounts.
+// ValidationErrors is returned when a write would violate invariants. Use
+// errors.As to get it from a Service error and inspect the failures.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, ve := range e {
+		msgs[i] = ve.Error()
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// ByInvariant returns the failures of invariant (or soft check) n.
+func (e ValidationErrors) ByInvariant(n int) ValidationErrors {
+	var result ValidationErrors
+	for _, ve := range e {
+		if ve.Invariant == n {
+			result = append(result, ve)
+		}
+	}
+	return result
+}
+
+// Invariants returns the distinct invariant numbers that failed, in
+// ascending order.
+func (e ValidationErrors) Invariants() []int {
+	var result []int
+	for _, ve := range e {
+		if !slices.Contains(result, ve.Invariant) {
+			result = append(result, ve.Invariant)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// Soft reports whether every failure is Soft, so the write would be
+// accepted with the check overridden.
+func (e ValidationErrors) Soft() bool {
+	for _, ve := range e {
+		if !ve.Soft {
+			return false
+		}
+	}
+	return len(e) > 0
+}
+
+// AccountChecker tests whether an account ID exists in the chart of
+// accounts, and whether it still accepts new postings.
 type AccountChecker interface {
 	Exists(id int) bool
+	Postable(id int) bool
+}
+
+// AccountTyper looks up an account, including its type. An AccountChecker
+// that also implements it enables Service.SignWarnings.
+type AccountTyper interface {
+	Get(id int) (model.Account, bool)
 }
 
-// ValidateLegs enforces 6 invariants on a set of journal legs for a given month.
-func ValidateLegs(legs []model.Leg, accounts AccountChecker, year, month int) []ValidationError {
-	var errs []ValidationError
+// SignCheck numbers the soft check made by CheckSigns.
+const SignCheck = 10
+
+// CheckSigns warns about legs posted to the unusual side of an income
+// statement account: a credit to an expense account or a debit to a revenue
+// account. Refunds and reversals do this legitimately, but more often it
+// means a bank amount's sign was flipped, so the warnings are Soft and the
+// caller decides whether to honor them.
+func CheckSigns(legs []model.Leg, accounts AccountTyper) ValidationErrors {
+	var errs ValidationErrors
+	for _, leg := range legs {
+		acct, ok := accounts.Get(leg.AccountID)
+		if !ok {
+			continue
+		}
+		var side string
+		switch {
+		case acct.Type == model.AccountTypeExpense && leg.Credit.IsPositive():
+			side = "expense account %d (%s) is credited"
+		case acct.Type == model.AccountTypeRevenue && leg.Debit.IsPositive():
+			side = "revenue account %d (%s) is debited"
+		default:
+			continue
+		}
+		errs = append(errs, ValidationError{
+			Invariant:   SignCheck,
+			EntryID:     leg.EntryID,
+			Description: fmt.Sprintf(side, acct.ID, acct.Name) + "; check the amount's sign",
+			Soft:        true,
+		})
+	}
+	return errs
+}
+
+// now returns the reference "today" for invariant 8. Tests replace it to
+// get deterministic results.
+var now = time.Now
+
+// ValidateLegs enforces 9 invariants on a set of journal legs for a given month.
+func ValidateLegs(legs []model.Leg, accounts AccountChecker, year, month int) ValidationErrors {
+	var errs ValidationErrors
+	today := now().Format(dateFormat)
 
 	// Group legs by entry.
 	groups := make(map[string][]model.Leg)
@@ -58,6 +160,25 @@ func ValidateLegs(legs []model.Leg, accounts AccountChecker, year, month int) []
 		}
 	}
 
+	// Invariant 9: An entry is booked in one currency. Legs with no currency
+	// are in the business currency and are not compared.
+	for _, g := range groupOrder {
+		var currencies []string
+		for _, leg := range groups[g] {
+			if leg.Currency != "" && !slices.Contains(currencies, leg.Currency) {
+				currencies = append(currencies, leg.Currency)
+			}
+		}
+		if len(currencies) > 1 {
+			slices.Sort(currencies)
+			errs = append(errs, ValidationError{
+				Invariant:   9,
+				EntryID:     g,
+				Description: "entry mixes currencies " + strings.Join(currencies, ", "),
+			})
+		}
+	}
+
 	for _, leg := range legs {
 		// Invariant 2: Exactly one of debit/credit per row.
 		hasDebit := !leg.Debit.IsZero()
@@ -88,6 +209,24 @@ func ValidateLegs(legs []model.Leg, accounts AccountChecker, year, month int) []
 			})
 		}
 
+		// Invariant 7: Status is a known EntryStatus.
+		if !leg.Status.Valid() {
+			errs = append(errs, ValidationError{
+				Invariant:   7,
+				EntryID:     leg.EntryID,
+				Description: fmt.Sprintf("unknown status %q", leg.Status),
+			})
+		}
+
+		// Invariant 8: Not dated in the future.
+		if d := leg.Date.Format(dateFormat); d > today {
+			errs = append(errs, ValidationError{
+				Invariant:   8,
+				EntryID:     leg.EntryID,
+				Description: fmt.Sprintf("date %s is after today (%s)", d, today),
+			})
+		}
+
 		// Invariant 6: Exact decimals — no more than 2 decimal places.
 		two := decimal.NewFromInt(100)
 		if !leg.Debit.IsZero() && !leg.Debit.Mul(two).Equal(leg.Debit.Mul(two).Floor()) {
@@ -2,6 +2,7 @@ package journal
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,13 +15,42 @@ import (
 
 // mockAccounts implements AccountChecker for testing.
 type mockAccounts struct {
-	ids map[int]bool
+	ids      map[int]bool
+	archived map[int]bool
 }
 
 func (m *mockAccounts) Exists(id int) bool {
 	return m.ids[id]
 }
 
+func (m *mockAccounts) Postable(id int) bool {
+	return m.ids[id] && !m.archived[id]
+}
+
+// typedAccounts adds account types to mockAccounts, implementing AccountTyper.
+type typedAccounts struct {
+	*mockAccounts
+	types map[int]model.AccountType
+}
+
+func (m *typedAccounts) Get(id int) (model.Account, bool) {
+	if !m.ids[id] {
+		return model.Account{}, false
+	}
+	return model.Account{ID: id, Name: fmt.Sprintf("Account %d", id), Type: m.types[id]}, true
+}
+
+func newTypedAccounts() *typedAccounts {
+	return &typedAccounts{
+		mockAccounts: newMockAccounts(1010, 4010, 5020),
+		types: map[int]model.AccountType{
+			1010: model.AccountTypeAsset,
+			4010: model.AccountTypeRevenue,
+			5020: model.AccountTypeExpense,
+		},
+	}
+}
+
 func newMockAccounts(ids ...int) *mockAccounts {
 	m := &mockAccounts{ids: make(map[int]bool)}
 	for _, id := range ids {
@@ -258,3 +288,117 @@ func TestValidate_MultiLegBalanced(t *testing.T) {
 	errs := ValidateLegs(legs, defaultAccounts, 2025, 1)
 	assert.Empty(t, errs)
 }
+
+func TestValidate_KnownStatus(t *testing.T) {
+	legs := balancedEntry(1, 5020, 1010, "10.00")
+	legs[0].Status = model.StatusBootstrapConfirmed
+	legs[1].Status = model.StatusPendingReview
+
+	errs := ValidateLegs(legs, defaultAccounts, 2025, 1)
+	assert.Empty(t, errs)
+}
+
+func TestValidate_UnknownStatus(t *testing.T) {
+	legs := balancedEntry(1, 5020, 1010, "10.00")
+	legs[1].Status = "auto-confirmd"
+
+	errs := ValidateLegs(legs, defaultAccounts, 2025, 1)
+	require.Len(t, errs, 1)
+	assert.Equal(t, 7, errs[0].Invariant)
+	assert.Equal(t, "2025-01-001b", errs[0].EntryID)
+	assert.Contains(t, errs[0].Description, "auto-confirmd")
+}
+
+// setToday pins the validator's notion of today for the rest of the test.
+func setToday(t *testing.T, today time.Time) {
+	t.Helper()
+	orig := now
+	now = func() time.Time { return today }
+	t.Cleanup(func() { now = orig })
+}
+
+func TestValidate_DatedToday(t *testing.T) {
+	setToday(t, time.Date(2025, 1, 15, 18, 30, 0, 0, time.UTC))
+	legs := balancedEntry(1, 5020, 1010, "10.00") // dated 2025-01-15
+
+	errs := ValidateLegs(legs, defaultAccounts, 2025, 1)
+	assert.Empty(t, errs)
+}
+
+func TestValidate_FutureDate(t *testing.T) {
+	setToday(t, time.Date(2025, 1, 14, 9, 0, 0, 0, time.UTC))
+	legs := balancedEntry(1, 5020, 1010, "10.00") // dated 2025-01-15, tomorrow
+
+	errs := ValidateLegs(legs, defaultAccounts, 2025, 1)
+	require.Len(t, errs, 2, "one per leg")
+	for _, e := range errs {
+		assert.Equal(t, 8, e.Invariant)
+		assert.Contains(t, e.Description, "after today")
+	}
+}
+
+func TestValidate_SameCurrency(t *testing.T) {
+	legs := balancedEntry(1, 5020, 1010, "10.00")
+	legs[0].Currency, legs[1].Currency = "CAD", "CAD"
+	other := balancedEntry(2, 5020, 1010, "5.00")
+	other[0].Currency, other[1].Currency = "USD", "USD"
+
+	errs := ValidateLegs(append(legs, other...), defaultAccounts, 2025, 1)
+	assert.Empty(t, errs, "separate entries may use different currencies")
+}
+
+func TestValidate_Invariant9_MixedCurrency(t *testing.T) {
+	legs := balancedEntry(1, 5020, 1010, "10.00")
+	legs[0].Currency, legs[1].Currency = "USD", "CAD"
+
+	errs := ValidateLegs(legs, defaultAccounts, 2025, 1)
+	require.Len(t, errs, 1)
+	assert.Equal(t, 9, errs[0].Invariant)
+	assert.Equal(t, "2025-01-001", errs[0].EntryID)
+	assert.Equal(t, "entry mixes currencies CAD, USD", errs[0].Description)
+}
+
+func TestCheckSigns(t *testing.T) {
+	accts := newTypedAccounts()
+
+	assert.Empty(t, CheckSigns(balancedEntry(1, 5020, 1010, "4.00"), accts), "expense paid from the bank")
+	assert.Empty(t, CheckSigns(balancedEntry(2, 1010, 4010, "3500.00"), accts), "revenue deposited")
+	assert.Empty(t, CheckSigns(balancedEntry(3, 9999, 1010, "1.00"), accts), "unknown accounts are left to ValidateLegs")
+
+	errs := CheckSigns(balancedEntry(4, 1010, 5020, "4.00"), accts)
+	require.Len(t, errs, 1)
+	assert.Equal(t, SignCheck, errs[0].Invariant)
+	assert.Equal(t, "2025-01-004b", errs[0].EntryID)
+	assert.True(t, errs[0].Soft)
+	assert.Contains(t, errs[0].Description, "expense account 5020 (Account 5020) is credited")
+	assert.Contains(t, errs[0].Error(), "check 10 [2025-01-004b]")
+
+	errs = CheckSigns(balancedEntry(5, 4010, 1010, "3500.00"), accts)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "2025-01-005a", errs[0].EntryID)
+	assert.Contains(t, errs[0].Description, "revenue account 4010 (Account 4010) is debited")
+}
+
+func TestValidationErrors_Inspect(t *testing.T) {
+	legs := balancedEntry(1, 9999, 1010, "10.005")
+
+	errs := ValidateLegs(legs, defaultAccounts, 2025, 1)
+	assert.Equal(t, []int{3, 6}, errs.Invariants())
+	require.Len(t, errs.ByInvariant(6), 2, "one per leg")
+	unknown := errs.ByInvariant(3)
+	require.Len(t, unknown, 1)
+	assert.Equal(t, "2025-01-001a", unknown[0].EntryID)
+	assert.Empty(t, errs.ByInvariant(1))
+	assert.False(t, errs.Soft())
+	assert.True(t, strings.HasPrefix(errs.Error(), "validation failed: invariant 3 [2025-01-001a]: unknown account 9999; "),
+		"message format is unchanged: %s", errs.Error())
+
+	var err error = errs
+	var verrs ValidationErrors
+	require.ErrorAs(t, fmt.Errorf("booking: %w", err), &verrs)
+	assert.Len(t, verrs, 3)
+
+	warnings := CheckSigns(balancedEntry(2, 1010, 5020, "4.00"), newTypedAccounts())
+	assert.True(t, warnings.Soft())
+	assert.False(t, ValidationErrors(nil).Soft())
+}
@@ -11,6 +11,16 @@ const (
 	AccountTypeExpense   AccountType = "expense"
 )
 
+// Valid reports whether t is one of the five account types.
+func (t AccountType) Valid() bool {
+	switch t {
+	case AccountTypeAsset, AccountTypeLiability, AccountTypeEquity,
+		AccountTypeRevenue, AccountTypeExpense:
+		return true
+	}
+	return false
+}
+
 // Account represents a row in chart-of-accounts.csv.
 type Account struct {
 	ID          int
@@ -19,4 +29,5 @@ type Account struct {
 	ParentID    int // 0 = top-level
 	TaxLine     string
 	Description string
+	Archived    bool // closed to new postings; history still validates
 }
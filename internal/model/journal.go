@@ -19,9 +19,19 @@ const (
 	StatusBootstrapConfirmed EntryStatus = "bootstrap-confirmed"
 )
 
+// Valid reports whether s is one of the known entry statuses.
+func (s EntryStatus) Valid() bool {
+	switch s {
+	case StatusAutoConfirmed, StatusPendingReview, StatusUserConfirmed,
+		StatusUserCorrected, StatusVoided, StatusBootstrapConfirmed:
+		return true
+	}
+	return false
+}
+
 // Leg is a single row in journal.csv (one side of a double-entry).
 type Leg struct {
-	EntryID      string          // "YYYY-MM-NNNx" where x = a,b,c...
+	EntryID      string          // "YYYY-MM-NNNNx" where x = a,b,c...
 	Date         time.Time       //nolint:revive // plain field name is clearest
 	AccountID    int             //nolint:revive
 	Description  string          //nolint:revive
@@ -35,10 +45,13 @@ type Leg struct {
 	ReceiptHash  string
 	Tags         string // semicolon-separated
 	Notes        string
+	Currency     string    // ISO 4217 code, e.g. "CAD"; empty means the business currency
+	CreatedAt    time.Time // when the leg was written; zero in journals that predate it
+	SourceFile   string    // import file the leg was booked from, e.g. "chase_checking.csv"
 }
 
 // EntryGroup returns the base entry ID (without leg suffix).
-// "2025-01-001a" -> "2025-01-001"
+// "2025-01-0001a" -> "2025-01-0001"
 func (l Leg) EntryGroup() string {
 	id := l.EntryID
 	if len(id) == 0 {
@@ -22,3 +22,25 @@ func TestLegEntryGroup(t *testing.T) {
 		assert.Equal(t, tt.want, leg.EntryGroup(), "EntryGroup(%q)", tt.entryID)
 	}
 }
+
+func TestEntryStatusValid(t *testing.T) {
+	for _, s := range []EntryStatus{
+		StatusAutoConfirmed, StatusPendingReview, StatusUserConfirmed,
+		StatusUserCorrected, StatusVoided, StatusBootstrapConfirmed,
+	} {
+		assert.True(t, s.Valid(), "%q should be valid", s)
+	}
+	assert.False(t, EntryStatus("auto-confirmd").Valid())
+	assert.False(t, EntryStatus("").Valid())
+}
+
+func TestAccountTypeValid(t *testing.T) {
+	for _, at := range []AccountType{
+		AccountTypeAsset, AccountTypeLiability, AccountTypeEquity,
+		AccountTypeRevenue, AccountTypeExpense,
+	} {
+		assert.True(t, at.Valid(), "%q should be valid", at)
+	}
+	assert.False(t, AccountType("income").Valid())
+	assert.False(t, AccountType("").Valid())
+}
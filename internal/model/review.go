@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// ReviewItem is a journal entry an agent wants the user to review, as stored
+// in the review queue.
+type ReviewItem struct {
+	EntryID          string    `json:"entry_id"`
+	Reason           string    `json:"reason"` // why it needs review, e.g. the bank description
+	SuggestedAccount int       `json:"suggested_account,omitempty"`
+	Confidence       float64   `json:"confidence"`
+	CreatedAt        time.Time `json:"created_at"`
+}
@@ -13,4 +13,10 @@ type BankTransaction struct {
 	Amount      decimal.Decimal // negative = expense, positive = income
 	Reference   string
 	Type        string // bank transaction type (ACH_DEBIT, etc.)
+	Notes       string // set on import, e.g. the amount before rounding
+
+	// BankAccountID is the chart-of-accounts ID of the bank or card account
+	// the file came from, resolved from cleared.yaml bank_accounts. Zero when
+	// no mapping matched.
+	BankAccountID int
 }
@@ -0,0 +1,149 @@
+// Package queue persists the review queue: entries an agent could not
+// categorize confidently and wants the user to look at. The queue lives in
+// the gitignored queue/ directory, so it is local working state rather than
+// part of the books.
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// File is the review queue path relative to the repo root.
+const File = "queue/pending.json"
+
+// ErrNotFound is returned by Resolve for an unknown item ID.
+var ErrNotFound = errors.New("queue item not found")
+
+// Item is one entry awaiting review: the review item plus what the queue
+// tracks about it.
+type Item struct {
+	ID string `json:"id"` // "q001", "q002", ...
+	model.ReviewItem
+	Agent string `json:"agent,omitempty"`
+	// Resolution is what the user decided, e.g. "confirmed" or
+	// "recategorized to 5030". Empty while the item is pending.
+	Resolution string     `json:"resolution,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// Pending reports whether the item still needs review.
+func (i Item) Pending() bool {
+	return i.ResolvedAt == nil
+}
+
+// legacyItem reads items written before the review item fields were named
+// reason and created_at.
+type legacyItem struct {
+	Item
+	Description string    `json:"description"`
+	Created     time.Time `json:"created"`
+}
+
+// Load reads <repoRoot>/queue/pending.json, oldest item first.
+// Returns an empty slice if the file does not exist.
+func Load(repoRoot string) ([]Item, error) {
+	data, err := os.ReadFile(filepath.Join(repoRoot, File))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading review queue: %w", err)
+	}
+
+	var stored []legacyItem
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("parsing review queue: %w", err)
+	}
+	items := make([]Item, len(stored))
+	for i, s := range stored {
+		items[i] = s.Item
+		if items[i].Reason == "" {
+			items[i].Reason = s.Description
+		}
+		if items[i].CreatedAt.IsZero() {
+			items[i].CreatedAt = s.Created
+		}
+	}
+	return items, nil
+}
+
+// Save writes items to <repoRoot>/queue/pending.json.
+func Save(repoRoot string, items []Item) error {
+	if err := os.MkdirAll(filepath.Join(repoRoot, filepath.Dir(File)), 0o755); err != nil {
+		return fmt.Errorf("creating queue dir: %w", err)
+	}
+	if items == nil {
+		items = []Item{} // keep "[]" rather than "null"
+	}
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling review queue: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoRoot, File), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing review queue: %w", err)
+	}
+	return nil
+}
+
+// NextID returns the ID the next item added to items will get.
+func NextID(items []Item) string {
+	last := 0
+	for _, it := range items {
+		if n, err := strconv.Atoi(strings.TrimPrefix(it.ID, "q")); err == nil && n > last {
+			last = n
+		}
+	}
+	return fmt.Sprintf("q%03d", last+1)
+}
+
+// Add appends item to the queue with the next ID and, if unset, the current
+// time as CreatedAt. Returns the stored item.
+func Add(repoRoot string, item Item) (Item, error) {
+	items, err := Load(repoRoot)
+	if err != nil {
+		return Item{}, err
+	}
+	item.ID = NextID(items)
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = time.Now().UTC()
+	}
+	item.Resolution, item.ResolvedAt = "", nil
+	if err := Save(repoRoot, append(items, item)); err != nil {
+		return Item{}, err
+	}
+	return item, nil
+}
+
+// Resolve marks the item with the given ID as reviewed. Resolved items stay
+// in the file so the decision remains visible.
+func Resolve(repoRoot, id, resolution string) (Item, error) {
+	if strings.TrimSpace(resolution) == "" {
+		return Item{}, errors.New("resolving a queue item requires a resolution")
+	}
+	items, err := Load(repoRoot)
+	if err != nil {
+		return Item{}, err
+	}
+	for i := range items {
+		if items[i].ID != id {
+			continue
+		}
+		if !items[i].Pending() {
+			return Item{}, fmt.Errorf("queue item %s is already resolved", id)
+		}
+		now := time.Now().UTC()
+		items[i].Resolution, items[i].ResolvedAt = resolution, &now
+		return items[i], Save(repoRoot, items)
+	}
+	return Item{}, fmt.Errorf("%s: %w", id, ErrNotFound)
+}
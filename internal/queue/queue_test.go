@@ -0,0 +1,111 @@
+package queue
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+func review(entryID, reason string) model.ReviewItem {
+	return model.ReviewItem{EntryID: entryID, Reason: reason}
+}
+
+func TestAddAndLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	items, err := Load(dir)
+	require.NoError(t, err)
+	assert.Empty(t, items, "missing file is an empty queue")
+
+	staples := review("2025-01-0003", "STAPLES #1234")
+	staples.Confidence = 0.4
+	first, err := Add(dir, Item{ReviewItem: staples, Agent: "ingest"})
+	require.NoError(t, err)
+	assert.Equal(t, "q001", first.ID)
+	assert.False(t, first.CreatedAt.IsZero())
+	second, err := Add(dir, Item{ReviewItem: review("2025-01-0004", "SQ *COFFEE")})
+	require.NoError(t, err)
+	assert.Equal(t, "q002", second.ID)
+
+	// A fresh load sees what earlier calls wrote.
+	items, err = Load(dir)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "STAPLES #1234", items[0].Reason)
+	assert.Equal(t, "ingest", items[0].Agent)
+	assert.InDelta(t, 0.4, items[0].Confidence, 1e-9)
+	assert.True(t, items[1].Pending())
+}
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	for _, desc := range []string{"one", "two"} {
+		_, err := Add(dir, Item{ReviewItem: review("", desc)})
+		require.NoError(t, err)
+	}
+
+	item, err := Resolve(dir, "q001", "confirmed")
+	require.NoError(t, err)
+	assert.False(t, item.Pending())
+
+	items, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, items, 2, "resolved items are kept")
+	assert.Equal(t, "confirmed", items[0].Resolution)
+	assert.NotNil(t, items[0].ResolvedAt)
+	assert.True(t, items[1].Pending())
+
+	_, err = Resolve(dir, "q001", "confirmed")
+	require.Error(t, err, "already resolved")
+	_, err = Resolve(dir, "q002", "")
+	require.Error(t, err, "resolution required")
+	_, err = Resolve(dir, "q099", "confirmed")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// IDs keep increasing after resolution.
+	next, err := Add(dir, Item{ReviewItem: review("", "three")})
+	require.NoError(t, err)
+	assert.Equal(t, "q003", next.ID)
+}
+
+func TestReviewItemRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	want := model.ReviewItem{
+		EntryID:          "2025-01-0005",
+		Reason:           "AMZN MKTP US*ABC123",
+		SuggestedAccount: 5030,
+		Confidence:       0.35,
+		CreatedAt:        time.Date(2025, 1, 18, 9, 30, 0, 0, time.UTC),
+	}
+	_, err := Add(dir, Item{ReviewItem: want, Agent: "ingest"})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, File))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"reason": "AMZN MKTP US*ABC123"`)
+	assert.Contains(t, string(data), `"created_at": "2025-01-18T09:30:00Z"`)
+
+	items, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, want, items[0].ReviewItem)
+}
+
+func TestLoad_LegacyFields(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "queue"), 0o755))
+	legacy := `[{"id": "q001", "entry_id": "2025-01-0003", "description": "STAPLES #1234", "created": "2025-01-03T06:00:00Z"}]`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, File), []byte(legacy), 0o644))
+
+	items, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "STAPLES #1234", items[0].Reason)
+	assert.Equal(t, time.Date(2025, 1, 3, 6, 0, 0, 0, time.UTC), items[0].CreatedAt)
+}
@@ -0,0 +1,86 @@
+// Package receipts stores receipt files under the gitignored receipts/
+// directory, named by the SHA-256 of their contents so journal legs can
+// reference them by receipt_hash.
+package receipts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Dir is the receipts directory relative to the repo root.
+const Dir = "receipts"
+
+// ErrNotFound is returned by Path when no receipt has the hash.
+var ErrNotFound = errors.New("receipt not found")
+
+// ValidHash reports whether h is a lowercase hex SHA-256 digest.
+func ValidHash(h string) bool {
+	if len(h) != sha256.Size*2 {
+		return false
+	}
+	for _, c := range h {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash returns the hex SHA-256 of the file at path without storing it.
+func Hash(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading receipt: %w", err)
+	}
+	return hashOf(data), nil
+}
+
+func hashOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Store copies the file at src into receipts/<hash><ext> and returns the
+// hash. Storing the same contents twice is a no-op.
+func Store(repoRoot, src string) (string, error) {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("reading receipt: %w", err)
+	}
+	hash := hashOf(data)
+
+	if _, err := Path(repoRoot, hash); err == nil {
+		return hash, nil
+	}
+
+	dir := filepath.Join(repoRoot, Dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating receipts dir: %w", err)
+	}
+	dst := filepath.Join(dir, hash+strings.ToLower(filepath.Ext(src)))
+	if err := os.WriteFile(dst, data, 0o644); err != nil {
+		return "", fmt.Errorf("writing receipt: %w", err)
+	}
+	return hash, nil
+}
+
+// Path returns the stored file for hash, whatever its extension.
+func Path(repoRoot, hash string) (string, error) {
+	if !ValidHash(hash) {
+		return "", fmt.Errorf("invalid receipt hash %q", hash)
+	}
+	matches, err := filepath.Glob(filepath.Join(repoRoot, Dir, hash+"*"))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%s: %w", hash, ErrNotFound)
+	}
+	return matches[0], nil
+}
@@ -0,0 +1,47 @@
+package receipts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	repo := t.TempDir()
+	src := filepath.Join(t.TempDir(), "Receipt.PDF")
+	require.NoError(t, os.WriteFile(src, []byte("receipt"), 0o644))
+
+	hash, err := Store(repo, src)
+	require.NoError(t, err)
+	assert.True(t, ValidHash(hash))
+
+	want, err := Hash(src)
+	require.NoError(t, err)
+	assert.Equal(t, want, hash)
+
+	path, err := Path(repo, hash)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo, Dir, hash+".pdf"), path)
+
+	again, err := Store(repo, src)
+	require.NoError(t, err)
+	assert.Equal(t, hash, again, "storing the same contents twice is a no-op")
+	entries, err := os.ReadDir(filepath.Join(repo, Dir))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	_, err = Path(repo, strings.Repeat("0", 64))
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestValidHash(t *testing.T) {
+	assert.True(t, ValidHash(strings.Repeat("a1", 32)))
+	assert.False(t, ValidHash(strings.Repeat("A1", 32)), "uppercase")
+	assert.False(t, ValidHash(strings.Repeat("a1", 31)), "too short")
+	assert.False(t, ValidHash(strings.Repeat("g1", 32)), "not hex")
+	assert.False(t, ValidHash(""))
+}
@@ -0,0 +1,124 @@
+// Package reconcile matches bank statement lines against the journal legs
+// booked to that bank account.
+package reconcile
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// DateWindow is how many days apart a statement line and a leg with the same
+// amount may be dated and still match, to allow for posting delays.
+const DateWindow = 3
+
+// Match pairs a statement line with the leg that books it.
+type Match struct {
+	Statement model.BankTransaction
+	Leg       model.Leg
+}
+
+// Result is the outcome of reconciling one statement.
+type Result struct {
+	Matched []Match
+	// UnmatchedStatement holds statement lines with no booking.
+	UnmatchedStatement []model.BankTransaction
+	// UnmatchedLegs holds bookings with no statement line.
+	UnmatchedLegs []model.Leg
+}
+
+// Reconciled reports whether every statement line and leg matched.
+func (r Result) Reconciled() bool {
+	return len(r.UnmatchedStatement) == 0 && len(r.UnmatchedLegs) == 0
+}
+
+// Reconcile matches statement lines to legs booked to the statement's
+// account. A leg's amount is its debit minus its credit, which has the
+// statement's sign for both bank and card accounts. Lines match first on
+// Reference and amount, then on amount and the closest date within
+// DateWindow. Voided entries and their voids cancel out and are ignored.
+func Reconcile(statementTxns []model.BankTransaction, legs []model.Leg) Result {
+	legs = dropVoided(legs)
+	used := make([]bool, len(legs))
+	matched := make([]int, len(statementTxns))
+	for i := range matched {
+		matched[i] = -1
+	}
+
+	for i, txn := range statementTxns {
+		if txn.Reference == "" {
+			continue
+		}
+		for j, leg := range legs {
+			if !used[j] && leg.Reference == txn.Reference && legAmount(leg).Equal(txn.Amount) {
+				matched[i], used[j] = j, true
+				break
+			}
+		}
+	}
+
+	for i, txn := range statementTxns {
+		if matched[i] >= 0 {
+			continue
+		}
+		best, bestGap := -1, 0
+		for j, leg := range legs {
+			if used[j] || !legAmount(leg).Equal(txn.Amount) {
+				continue
+			}
+			gap := daysApart(txn.Date, leg.Date)
+			if gap <= DateWindow && (best < 0 || gap < bestGap) {
+				best, bestGap = j, gap
+			}
+		}
+		if best >= 0 {
+			matched[i], used[best] = best, true
+		}
+	}
+
+	var result Result
+	for i, txn := range statementTxns {
+		if matched[i] < 0 {
+			result.UnmatchedStatement = append(result.UnmatchedStatement, txn)
+			continue
+		}
+		result.Matched = append(result.Matched, Match{Statement: txn, Leg: legs[matched[i]]})
+	}
+	for j, leg := range legs {
+		if !used[j] {
+			result.UnmatchedLegs = append(result.UnmatchedLegs, leg)
+		}
+	}
+	return result
+}
+
+// dropVoided removes void legs and the legs of the entries they void.
+func dropVoided(legs []model.Leg) []model.Leg {
+	voided := make(map[string]bool)
+	for _, leg := range legs {
+		if leg.Status == model.StatusVoided {
+			voided[leg.Reference] = true
+		}
+	}
+	var kept []model.Leg
+	for _, leg := range legs {
+		if leg.Status != model.StatusVoided && !voided[leg.EntryGroup()] {
+			kept = append(kept, leg)
+		}
+	}
+	return kept
+}
+
+func legAmount(leg model.Leg) decimal.Decimal {
+	return leg.Debit.Sub(leg.Credit)
+}
+
+func daysApart(a, b time.Time) int {
+	days := int(a.Sub(b).Hours() / 24)
+	if days < 0 {
+		return -days
+	}
+	return days
+}
@@ -0,0 +1,102 @@
+package reconcile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+func day(d int) time.Time {
+	return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+func txn(d int, amount, ref string) model.BankTransaction {
+	return model.BankTransaction{Date: day(d), Amount: decimal.RequireFromString(amount), Reference: ref}
+}
+
+// bankLeg books amount to the bank account: positive debits it (money in),
+// negative credits it (money out).
+func bankLeg(id string, d int, amount, ref string) model.Leg {
+	leg := model.Leg{EntryID: id, Date: day(d), AccountID: 1010, Reference: ref, Status: model.StatusAutoConfirmed}
+	a := decimal.RequireFromString(amount)
+	if a.IsNegative() {
+		leg.Credit = a.Neg()
+	} else {
+		leg.Debit = a
+	}
+	return leg
+}
+
+func statement() []model.BankTransaction {
+	return []model.BankTransaction{
+		txn(3, "-4.00", "plaid_abc123"),
+		txn(5, "-127.50", ""),
+		txn(15, "3500.00", "ach_1042"),
+	}
+}
+
+func TestReconcile_PerfectMatch(t *testing.T) {
+	legs := []model.Leg{
+		bankLeg("2025-01-0001b", 3, "-4.00", "plaid_abc123"),
+		bankLeg("2025-01-0002b", 6, "-127.50", "posted late"),
+		bankLeg("2025-01-0003a", 15, "3500.00", "ach_1042"),
+	}
+
+	result := Reconcile(statement(), legs)
+	assert.True(t, result.Reconciled())
+	require.Len(t, result.Matched, 3)
+	assert.Equal(t, "2025-01-0001b", result.Matched[0].Leg.EntryID, "matched on reference")
+	assert.Equal(t, "2025-01-0002b", result.Matched[1].Leg.EntryID, "matched on amount within the date window")
+	assert.Equal(t, "2025-01-0003a", result.Matched[2].Leg.EntryID)
+}
+
+func TestReconcile_MissingBooking(t *testing.T) {
+	legs := []model.Leg{
+		bankLeg("2025-01-0001b", 3, "-4.00", "plaid_abc123"),
+		bankLeg("2025-01-0003a", 15, "3500.00", "ach_1042"),
+	}
+
+	result := Reconcile(statement(), legs)
+	assert.False(t, result.Reconciled())
+	require.Len(t, result.UnmatchedStatement, 1)
+	assert.Equal(t, "-127.5", result.UnmatchedStatement[0].Amount.String())
+	assert.Empty(t, result.UnmatchedLegs)
+}
+
+func TestReconcile_ExtraBooking(t *testing.T) {
+	legs := []model.Leg{
+		bankLeg("2025-01-0001b", 3, "-4.00", "plaid_abc123"),
+		bankLeg("2025-01-0002b", 5, "-127.50", ""),
+		bankLeg("2025-01-0003a", 15, "3500.00", "ach_1042"),
+		// Booked twice, and an amount outside the window.
+		bankLeg("2025-01-0004b", 3, "-4.00", "plaid_abc123"),
+		bankLeg("2025-01-0005b", 20, "-127.50", ""),
+	}
+
+	result := Reconcile(statement(), legs)
+	assert.False(t, result.Reconciled())
+	assert.Empty(t, result.UnmatchedStatement)
+	require.Len(t, result.UnmatchedLegs, 2)
+	assert.Equal(t, "2025-01-0004b", result.UnmatchedLegs[0].EntryID)
+	assert.Equal(t, "2025-01-0005b", result.UnmatchedLegs[1].EntryID)
+}
+
+func TestReconcile_IgnoresVoided(t *testing.T) {
+	legs := []model.Leg{
+		bankLeg("2025-01-0001b", 3, "-4.00", "plaid_abc123"),
+		bankLeg("2025-01-0002b", 5, "-127.50", ""),
+		bankLeg("2025-01-0003a", 15, "3500.00", "ach_1042"),
+		bankLeg("2025-01-0004b", 9, "-50.00", ""),
+	}
+	void := bankLeg("2025-01-0005b", 9, "50.00", "2025-01-0004")
+	void.Status = model.StatusVoided
+	legs = append(legs, void)
+
+	result := Reconcile(statement(), legs)
+	assert.True(t, result.Reconciled(), "a voided booking and its void cancel out")
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// BalanceSheet is the financial position as of a date.
+type BalanceSheet struct {
+	AsOf             time.Time
+	Assets           []AccountTotal // parents before their children, siblings by account ID
+	Liabilities      []AccountTotal
+	Equity           []AccountTotal
+	TotalAssets      decimal.Decimal
+	TotalLiabilities decimal.Decimal
+	// NetIncome is revenue less expenses through AsOf. The journal has no
+	// closing entries, so it is reported as part of equity.
+	NetIncome   decimal.Decimal
+	TotalEquity decimal.Decimal // equity accounts plus NetIncome
+	// Discrepancy is TotalAssets - (TotalLiabilities + TotalEquity). It is
+	// zero for a balanced journal; anything else means legs that do not
+	// balance or are booked to accounts missing from the chart.
+	Discrepancy decimal.Decimal
+}
+
+// Balanced reports whether assets equal liabilities plus equity.
+func (b BalanceSheet) Balanced() bool {
+	return b.Discrepancy.IsZero()
+}
+
+// BalanceSheet classifies every account's balance as of asOf (inclusive) by
+// its type in the chart and checks the accounting equation. The result is
+// returned even when it does not balance; callers check Balanced.
+func (s *Service) BalanceSheet(asOf time.Time) (BalanceSheet, error) {
+	bs := BalanceSheet{AsOf: asOf}
+
+	balances, err := s.TrialBalance(asOf)
+	if err != nil {
+		return bs, err
+	}
+
+	assets := make(map[int]decimal.Decimal)
+	liabilities := make(map[int]decimal.Decimal)
+	equity := make(map[int]decimal.Decimal)
+	// Accounts missing from the chart can't be classified; TrialBalance
+	// signs them as debits, so they count against the asset side.
+	unclassified := decimal.Zero
+	for id, balance := range balances {
+		acct, ok := s.accounts.Get(id)
+		if !ok {
+			unclassified = unclassified.Add(balance)
+			continue
+		}
+		switch acct.Type {
+		case model.AccountTypeAsset:
+			assets[id] = balance
+		case model.AccountTypeLiability:
+			liabilities[id] = balance
+		case model.AccountTypeEquity:
+			equity[id] = balance
+		case model.AccountTypeRevenue:
+			bs.NetIncome = bs.NetIncome.Add(balance)
+		case model.AccountTypeExpense:
+			bs.NetIncome = bs.NetIncome.Sub(balance)
+		}
+	}
+
+	var equityTotal decimal.Decimal
+	bs.Assets, bs.TotalAssets = s.accountTotals(assets)
+	bs.Liabilities, bs.TotalLiabilities = s.accountTotals(liabilities)
+	bs.Equity, equityTotal = s.accountTotals(equity)
+	bs.TotalEquity = equityTotal.Add(bs.NetIncome)
+	bs.Discrepancy = bs.TotalAssets.Add(unclassified).Sub(bs.TotalLiabilities.Add(bs.TotalEquity))
+	return bs, nil
+}
@@ -0,0 +1,66 @@
+package report
+
+import (
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cleared-dev/cleared/internal/accounts"
+	"github.com/cleared-dev/cleared/internal/journal"
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// LedgerLine is one leg in an account's general ledger.
+type LedgerLine struct {
+	Leg model.Leg
+	// Amount is the leg signed by the account's normal side, so it adds to
+	// the balance: a debit to an asset is positive, a debit to revenue
+	// negative.
+	Amount decimal.Decimal
+	// Balance is the account's running balance after this leg, starting
+	// from its balance at the close of the day before the range.
+	Balance decimal.Decimal
+}
+
+// GeneralLedger lists each account's legs between from and to (inclusive)
+// in date order, with a running balance signed as in TrialBalance. Accounts
+// with no legs in the range are omitted. Legs on the same day keep their
+// journal order.
+func GeneralLedger(svc *journal.Service, accts *accounts.Service, from, to time.Time) (map[int][]LedgerLine, error) {
+	reports := NewService(svc, accts)
+	balances, err := reports.TrialBalance(from.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+
+	legs, err := svc.ReadRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(legs, func(a, b model.Leg) int { return a.Date.Compare(b.Date) })
+
+	ledger := make(map[int][]LedgerLine)
+	for _, leg := range legs {
+		amount := leg.Debit.Sub(leg.Credit)
+		if !reports.debitNormal(leg.AccountID) {
+			amount = amount.Neg()
+		}
+		balances[leg.AccountID] = balances[leg.AccountID].Add(amount)
+		ledger[leg.AccountID] = append(ledger[leg.AccountID], LedgerLine{
+			Leg:     leg,
+			Amount:  amount,
+			Balance: balances[leg.AccountID],
+		})
+	}
+	return ledger, nil
+}
+
+// Opening returns the balance before the first line, or zero for an empty
+// ledger.
+func Opening(lines []LedgerLine) decimal.Decimal {
+	if len(lines) == 0 {
+		return decimal.Zero
+	}
+	return lines[0].Balance.Sub(lines[0].Amount)
+}
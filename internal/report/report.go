@@ -0,0 +1,274 @@
+package report
+
+import (
+	"cmp"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cleared-dev/cleared/internal/accounts"
+	"github.com/cleared-dev/cleared/internal/fiscal"
+	"github.com/cleared-dev/cleared/internal/journal"
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// Service computes financial reports from the journal and chart of accounts.
+type Service struct {
+	journal  *journal.Service
+	accounts *accounts.Service
+	fiscal   fiscal.Year
+}
+
+// NewService creates a report Service that reports by calendar year until
+// SetFiscalYear says otherwise.
+func NewService(j *journal.Service, accts *accounts.Service) *Service {
+	return &Service{journal: j, accounts: accts, fiscal: fiscal.Calendar}
+}
+
+// SetFiscalYear sets when the business's fiscal year starts, for reports
+// that take a year.
+func (s *Service) SetFiscalYear(y fiscal.Year) {
+	s.fiscal = y
+}
+
+// FiscalBounds returns the first and last day of fiscal year fy.
+func (s *Service) FiscalBounds(fy int) (from, to time.Time) {
+	return s.fiscal.Bounds(fy)
+}
+
+// TrialBalance returns each account's balance as of asOf (inclusive), summed
+// across all months. Balances are signed by the account's normal side:
+// assets and expenses are debits minus credits; liabilities, equity, and
+// revenue are credits minus debits. Accounts with no activity are omitted.
+func (s *Service) TrialBalance(asOf time.Time) (map[int]decimal.Decimal, error) {
+	months, err := s.journal.Months()
+	if err != nil {
+		return nil, err
+	}
+	balances := make(map[int]decimal.Decimal)
+	if len(months) == 0 || months[0].After(asOf) {
+		return balances, nil
+	}
+
+	legs, err := s.journal.ReadRange(months[0], asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, leg := range legs {
+		net := leg.Debit.Sub(leg.Credit)
+		if !s.debitNormal(leg.AccountID) {
+			net = net.Neg()
+		}
+		balances[leg.AccountID] = balances[leg.AccountID].Add(net)
+	}
+	return balances, nil
+}
+
+// AccountTotal is one account's activity over a period, signed by the
+// account's normal side.
+type AccountTotal struct {
+	AccountID int
+	Name      string
+	Amount    decimal.Decimal // this account's own activity
+	Total     decimal.Decimal // Amount plus every descendant's activity
+	Depth     int             // 0 for top-level rows, 1 for their children, ...
+}
+
+// ProfitAndLoss is an income statement for a period.
+type ProfitAndLoss struct {
+	From, To      time.Time
+	Revenue       []AccountTotal // parents before their children, siblings by account ID
+	Expenses      []AccountTotal // parents before their children, siblings by account ID
+	TotalRevenue  decimal.Decimal
+	TotalExpenses decimal.Decimal
+	NetIncome     decimal.Decimal // TotalRevenue - TotalExpenses
+}
+
+// ProfitAndLoss sums revenue and expense account activity between from and
+// to (inclusive) and computes net income. Accounts are classified by their
+// type in the chart of accounts; months with no journal count as zero.
+func (s *Service) ProfitAndLoss(from, to time.Time) (ProfitAndLoss, error) {
+	pnl := ProfitAndLoss{From: from, To: to}
+
+	legs, err := s.journal.ReadRange(from, to)
+	if err != nil {
+		return pnl, err
+	}
+
+	revenue := make(map[int]decimal.Decimal)
+	expenses := make(map[int]decimal.Decimal)
+	for _, leg := range legs {
+		acct, ok := s.accounts.Get(leg.AccountID)
+		if !ok {
+			continue
+		}
+		switch acct.Type {
+		case model.AccountTypeRevenue:
+			revenue[acct.ID] = revenue[acct.ID].Add(leg.Credit.Sub(leg.Debit))
+		case model.AccountTypeExpense:
+			expenses[acct.ID] = expenses[acct.ID].Add(leg.Debit.Sub(leg.Credit))
+		}
+	}
+
+	pnl.Revenue, pnl.TotalRevenue = s.accountTotals(revenue)
+	pnl.Expenses, pnl.TotalExpenses = s.accountTotals(expenses)
+	pnl.NetIncome = pnl.TotalRevenue.Sub(pnl.TotalExpenses)
+	return pnl, nil
+}
+
+// UnknownAccountName names the AccountTotal rows for accounts missing from
+// the chart of accounts.
+const UnknownAccountName = "(unknown account)"
+
+// accountTotals converts per-account sums into rows and their total. Parents
+// of accounts with activity get a row too, with Total rolling up their
+// subtree; the returned sum counts each account's own activity once.
+// Accounts missing from the chart get a top-level row of their own after
+// the rest, so the top-level rows always add up to the sum.
+func (s *Service) accountTotals(sums map[int]decimal.Decimal) ([]AccountTotal, decimal.Decimal) {
+	sum := decimal.Zero
+	included := make(map[int]bool, len(sums))
+	var unknown []int
+	for id, amount := range sums {
+		sum = sum.Add(amount)
+		if _, ok := s.accounts.Get(id); !ok {
+			unknown = append(unknown, id)
+			continue
+		}
+		for a, ok := s.accounts.Get(id); ok && !included[a.ID]; a, ok = s.accounts.Get(a.ParentID) {
+			included[a.ID] = true
+		}
+	}
+
+	var roots []int
+	for id := range included {
+		acct, _ := s.accounts.Get(id)
+		if !included[acct.ParentID] || acct.ParentID == id {
+			roots = append(roots, id)
+		}
+	}
+	slices.Sort(roots)
+
+	totals := make([]AccountTotal, 0, len(included))
+	visited := make(map[int]bool, len(included))
+	var walk func(id, depth int)
+	walk = func(id, depth int) {
+		if visited[id] {
+			return
+		}
+		visited[id] = true
+		acct, _ := s.accounts.Get(id)
+		row := AccountTotal{AccountID: id, Name: acct.Name, Amount: sums[id], Depth: depth}
+		for _, a := range s.accounts.Subtree(id) {
+			row.Total = row.Total.Add(sums[a.ID])
+		}
+		totals = append(totals, row)
+
+		children := s.accounts.Children(id)
+		slices.SortFunc(children, func(a, b model.Account) int { return cmp.Compare(a.ID, b.ID) })
+		for _, c := range children {
+			if included[c.ID] {
+				walk(c.ID, depth+1)
+			}
+		}
+	}
+	for _, id := range roots {
+		walk(id, 0)
+	}
+	// A parent cycle has no root; list any such accounts at the top level.
+	for _, id := range slices.Sorted(maps.Keys(included)) {
+		walk(id, 0)
+	}
+	slices.Sort(unknown)
+	for _, id := range unknown {
+		totals = append(totals, AccountTotal{AccountID: id, Name: UnknownAccountName, Amount: sums[id], Total: sums[id]})
+	}
+	return totals, sum
+}
+
+// UnmappedTaxLine is the Schedule C bucket for expense accounts with no
+// tax_line, so their totals are reported rather than silently dropped.
+const UnmappedTaxLine = "unmapped"
+
+// TaxLineTotal is the expense total for one Schedule C line.
+type TaxLineTotal struct {
+	Line     string // e.g. "schedule_c_18", or UnmappedTaxLine
+	Amount   decimal.Decimal
+	Accounts []int // contributing account IDs, ascending
+}
+
+// ScheduleC groups a fiscal year's expense totals (debits minus credits) by
+// the accounts' tax_line. Lines are ordered by line number, with the
+// unmapped bucket last. Lines with no activity are omitted.
+func (s *Service) ScheduleC(year int) ([]TaxLineTotal, error) {
+	legs, err := s.journal.ReadRange(s.fiscal.Bounds(year))
+	if err != nil {
+		return nil, err
+	}
+
+	byLine := make(map[string]*TaxLineTotal)
+	for _, leg := range legs {
+		acct, ok := s.accounts.Get(leg.AccountID)
+		if !ok || acct.Type != model.AccountTypeExpense {
+			continue
+		}
+		line := acct.TaxLine
+		if line == "" {
+			line = UnmappedTaxLine
+		}
+		t, ok := byLine[line]
+		if !ok {
+			t = &TaxLineTotal{Line: line}
+			byLine[line] = t
+		}
+		t.Amount = t.Amount.Add(leg.Debit.Sub(leg.Credit))
+		if !slices.Contains(t.Accounts, acct.ID) {
+			t.Accounts = append(t.Accounts, acct.ID)
+		}
+	}
+
+	totals := make([]TaxLineTotal, 0, len(byLine))
+	for _, t := range byLine {
+		slices.Sort(t.Accounts)
+		totals = append(totals, *t)
+	}
+	slices.SortFunc(totals, func(a, b TaxLineTotal) int {
+		return cmp.Or(
+			cmp.Compare(lineNumber(a.Line), lineNumber(b.Line)),
+			cmp.Compare(a.Line, b.Line),
+		)
+	})
+	return totals, nil
+}
+
+// lineNumber extracts the numeric part of "schedule_c_18" so line 8 sorts
+// before line 17. Lines without one (including unmapped) sort last.
+func lineNumber(line string) int {
+	i := strings.LastIndexByte(line, '_')
+	n, err := strconv.Atoi(line[i+1:])
+	if err != nil {
+		return math.MaxInt
+	}
+	return n
+}
+
+// debitNormal reports whether an account's balance increases with debits.
+// Accounts missing from the chart are treated as debit-normal.
+func (s *Service) debitNormal(accountID int) bool {
+	acct, ok := s.accounts.Get(accountID)
+	if !ok {
+		return true
+	}
+	switch acct.Type {
+	case model.AccountTypeLiability, model.AccountTypeEquity, model.AccountTypeRevenue:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,400 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cleared-dev/cleared/internal/accounts"
+	"github.com/cleared-dev/cleared/internal/fiscal"
+	"github.com/cleared-dev/cleared/internal/journal"
+	"github.com/cleared-dev/cleared/internal/model"
+)
+
+// newTestdataService sets up a repo holding the 12-leg testdata journal in
+// 2025/01 and the testdata chart of accounts.
+func newTestdataService(t *testing.T) *Service {
+	t.Helper()
+	dir := t.TempDir()
+
+	copyFile(t, filepath.Join("..", "..", "testdata", "journal.csv"), filepath.Join(dir, "2025", "01", "journal.csv"))
+	copyFile(t, filepath.Join("..", "..", "testdata", "chart-of-accounts.csv"), filepath.Join(dir, "accounts", "chart-of-accounts.csv"))
+
+	accts, err := accounts.Load(dir)
+	require.NoError(t, err)
+	return NewService(journal.NewService(dir, accts), accts)
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+	data, err := os.ReadFile(src)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(dst), 0o755))
+	require.NoError(t, os.WriteFile(dst, data, 0o644))
+}
+
+func date(y, m, d int) time.Time {
+	return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+}
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func TestTrialBalance(t *testing.T) {
+	svc := newTestdataService(t)
+
+	bal, err := svc.TrialBalance(date(2025, 1, 31))
+	require.NoError(t, err)
+
+	// 3500.00 in - (4.00 + 127.50 + 15.00 + 42.99 + 8.75) out.
+	assert.Equal(t, "3301.76", bal[1010].StringFixed(2), "checking")
+	assert.Equal(t, "3500.00", bal[4010].StringFixed(2), "revenue is credit-normal")
+	assert.Equal(t, "146.50", bal[5020].StringFixed(2), "software")
+	assert.Equal(t, "42.99", bal[5030].StringFixed(2), "office supplies")
+	assert.Equal(t, "8.75", bal[5050].StringFixed(2), "shipping")
+	assert.NotContains(t, bal, 2010, "untouched accounts are omitted")
+
+	expenses := decimal.Zero
+	for _, id := range []int{5020, 5030, 5050} {
+		expenses = expenses.Add(bal[id])
+	}
+	assert.True(t, expenses.Equal(dec("198.24")))
+}
+
+func TestTrialBalance_AsOf(t *testing.T) {
+	svc := newTestdataService(t)
+
+	bal, err := svc.TrialBalance(date(2025, 1, 10))
+	require.NoError(t, err)
+	assert.Equal(t, "-146.50", bal[1010].StringFixed(2), "only the first three entries")
+	assert.NotContains(t, bal, 4010)
+
+	bal, err = svc.TrialBalance(date(2024, 12, 31))
+	require.NoError(t, err)
+	assert.Empty(t, bal)
+}
+
+func TestTrialBalance_EmptyRepo(t *testing.T) {
+	dir := t.TempDir()
+	accts := accounts.NewService(accounts.DefaultChart("llc_single_member"))
+	svc := NewService(journal.NewService(dir, accts), accts)
+
+	bal, err := svc.TrialBalance(date(2025, 12, 31))
+	require.NoError(t, err)
+	assert.Empty(t, bal)
+}
+
+func TestScheduleC(t *testing.T) {
+	dir := t.TempDir()
+	chart := append(accounts.DefaultChart("llc_single_member"),
+		model.Account{ID: 5090, Name: "Miscellaneous", Type: model.AccountTypeExpense})
+	accts := accounts.NewService(chart)
+	j := journal.NewService(dir, accts)
+	svc := NewService(j, accts)
+
+	book := func(d time.Time, debit, credit int, amount string) {
+		t.Helper()
+		_, err := j.AddDouble(journal.AddDoubleParams{
+			Date:          d,
+			Description:   "test",
+			DebitAccount:  debit,
+			CreditAccount: credit,
+			Amount:        dec(amount),
+			Status:        model.StatusAutoConfirmed,
+			Confidence:    dec("0.95"),
+		})
+		require.NoError(t, err)
+	}
+	book(date(2025, 1, 3), 5020, 1010, "4.00")    // line 18
+	book(date(2025, 2, 5), 5030, 1010, "42.99")   // line 18
+	book(date(2025, 3, 9), 5040, 1010, "500.00")  // line 17
+	book(date(2025, 4, 1), 5010, 1010, "120.00")  // line 8
+	book(date(2025, 5, 1), 5090, 1010, "12.34")   // unmapped
+	book(date(2025, 6, 1), 1010, 4010, "1000.00") // revenue, ignored
+	book(date(2024, 12, 31), 5020, 1010, "99.00") // prior year
+	book(date(2026, 1, 1), 5020, 1010, "99.00")   // next year
+
+	lines, err := svc.ScheduleC(2025)
+	require.NoError(t, err)
+	require.Len(t, lines, 4)
+
+	assert.Equal(t, "schedule_c_8", lines[0].Line)
+	assert.Equal(t, "120.00", lines[0].Amount.StringFixed(2))
+	assert.Equal(t, "schedule_c_17", lines[1].Line)
+	assert.Equal(t, "500.00", lines[1].Amount.StringFixed(2))
+	assert.Equal(t, "schedule_c_18", lines[2].Line)
+	assert.Equal(t, "46.99", lines[2].Amount.StringFixed(2))
+	assert.Equal(t, []int{5020, 5030}, lines[2].Accounts)
+	assert.Equal(t, UnmappedTaxLine, lines[3].Line)
+	assert.Equal(t, "12.34", lines[3].Amount.StringFixed(2))
+}
+
+func TestScheduleC_NoJournal(t *testing.T) {
+	dir := t.TempDir()
+	accts := accounts.NewService(accounts.DefaultChart("llc_single_member"))
+	svc := NewService(journal.NewService(dir, accts), accts)
+
+	lines, err := svc.ScheduleC(2025)
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+}
+
+func TestScheduleC_FiscalYear(t *testing.T) {
+	dir := t.TempDir()
+	accts := accounts.NewService(accounts.DefaultChart("llc_single_member"))
+	jrnl := journal.NewService(dir, accts)
+	for _, d := range []time.Time{date(2024, 6, 30), date(2024, 7, 1), date(2025, 6, 30), date(2025, 7, 1)} {
+		_, err := jrnl.AddDouble(journal.AddDoubleParams{
+			Date:          d,
+			Description:   "Software",
+			DebitAccount:  5020,
+			CreditAccount: 1010,
+			Amount:        dec("10.00"),
+			Status:        model.StatusAutoConfirmed,
+		})
+		require.NoError(t, err)
+	}
+	svc := NewService(jrnl, accts)
+	july, err := fiscal.Parse("07-01")
+	require.NoError(t, err)
+	svc.SetFiscalYear(july)
+
+	// Fiscal 2025 runs 2024-07-01 to 2025-06-30.
+	lines, err := svc.ScheduleC(2025)
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "20.00", lines[0].Amount.StringFixed(2))
+
+	lines, err = svc.ScheduleC(2026)
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "10.00", lines[0].Amount.StringFixed(2))
+}
+
+func TestProfitAndLoss(t *testing.T) {
+	svc := newTestdataService(t)
+
+	pnl, err := svc.ProfitAndLoss(date(2025, 1, 1), date(2025, 1, 31))
+	require.NoError(t, err)
+
+	require.Len(t, pnl.Revenue, 1)
+	assert.Equal(t, 4010, pnl.Revenue[0].AccountID)
+	assert.Equal(t, "Service Revenue", pnl.Revenue[0].Name)
+	assert.Equal(t, "3500.00", pnl.TotalRevenue.StringFixed(2))
+
+	require.Len(t, pnl.Expenses, 3)
+	assert.Equal(t, []int{5020, 5030, 5050}, []int{pnl.Expenses[0].AccountID, pnl.Expenses[1].AccountID, pnl.Expenses[2].AccountID})
+	assert.Equal(t, "198.24", pnl.TotalExpenses.StringFixed(2))
+
+	assert.Equal(t, "3301.76", pnl.NetIncome.StringFixed(2))
+	assert.True(t, pnl.TotalRevenue.Sub(pnl.TotalExpenses).Equal(pnl.NetIncome))
+}
+
+func TestProfitAndLoss_MissingMonths(t *testing.T) {
+	svc := newTestdataService(t)
+
+	// Only January has a journal; the rest of the year counts as zero.
+	full, err := svc.ProfitAndLoss(date(2024, 11, 1), date(2025, 6, 30))
+	require.NoError(t, err)
+	assert.Equal(t, "3301.76", full.NetIncome.StringFixed(2))
+
+	empty, err := svc.ProfitAndLoss(date(2025, 2, 1), date(2025, 3, 31))
+	require.NoError(t, err)
+	assert.Empty(t, empty.Revenue)
+	assert.Empty(t, empty.Expenses)
+	assert.True(t, empty.NetIncome.IsZero())
+}
+
+func TestProfitAndLoss_ParentTotals(t *testing.T) {
+	dir := t.TempDir()
+	accts := accounts.NewService([]model.Account{
+		{ID: 1010, Name: "Checking", Type: model.AccountTypeAsset},
+		{ID: 5000, Name: "Operating Expenses", Type: model.AccountTypeExpense},
+		{ID: 5100, Name: "Technology", Type: model.AccountTypeExpense, ParentID: 5000},
+		{ID: 5110, Name: "Software", Type: model.AccountTypeExpense, ParentID: 5100},
+		{ID: 5120, Name: "Hosting", Type: model.AccountTypeExpense, ParentID: 5100},
+		{ID: 5200, Name: "Travel", Type: model.AccountTypeExpense, ParentID: 5000},
+		{ID: 6000, Name: "Other", Type: model.AccountTypeExpense},
+	})
+	jrnl := journal.NewService(dir, accts)
+	for _, e := range []struct {
+		acct   int
+		amount string
+	}{{5110, "40.00"}, {5120, "25.00"}, {5100, "5.00"}, {6000, "10.00"}} {
+		_, err := jrnl.AddDouble(journal.AddDoubleParams{
+			Date:          date(2025, 1, 10),
+			Description:   "expense",
+			DebitAccount:  e.acct,
+			CreditAccount: 1010,
+			Amount:        dec(e.amount),
+			Status:        model.StatusAutoConfirmed,
+		})
+		require.NoError(t, err)
+	}
+	svc := NewService(jrnl, accts)
+
+	pnl, err := svc.ProfitAndLoss(date(2025, 1, 1), date(2025, 1, 31))
+	require.NoError(t, err)
+
+	type row struct {
+		id            int
+		depth         int
+		amount, total string
+	}
+	var got []row
+	for _, a := range pnl.Expenses {
+		got = append(got, row{a.AccountID, a.Depth, a.Amount.StringFixed(2), a.Total.StringFixed(2)})
+	}
+	assert.Equal(t, []row{
+		{5000, 0, "0.00", "70.00"},
+		{5100, 1, "5.00", "70.00"},
+		{5110, 2, "40.00", "40.00"},
+		{5120, 2, "25.00", "25.00"},
+		{6000, 0, "10.00", "10.00"},
+	}, got, "parents roll up their subtree; 5200 has no activity")
+	assert.Equal(t, "80.00", pnl.TotalExpenses.StringFixed(2), "each leg counted once")
+}
+
+func TestGeneralLedger(t *testing.T) {
+	svc := newTestdataService(t)
+
+	ledger, err := GeneralLedger(svc.journal, svc.accounts, date(2025, 1, 5), date(2025, 1, 31))
+	require.NoError(t, err)
+
+	checking := ledger[1010]
+	require.Len(t, checking, 5, "the 2025-01-03 leg is before the range")
+	assert.Equal(t, "-4.00", Opening(checking).StringFixed(2), "opening balance carries the earlier leg")
+
+	var amounts, balances []string
+	for _, l := range checking {
+		amounts = append(amounts, l.Amount.StringFixed(2))
+		balances = append(balances, l.Balance.StringFixed(2))
+	}
+	assert.Equal(t, []string{"-127.50", "-15.00", "3500.00", "-42.99", "-8.75"}, amounts)
+	assert.Equal(t, []string{"-131.50", "-146.50", "3353.50", "3310.51", "3301.76"}, balances)
+
+	// Revenue is credit-normal, so its credit increases the balance.
+	require.Len(t, ledger[4010], 1)
+	assert.Equal(t, "3500.00", ledger[4010][0].Amount.StringFixed(2))
+	assert.Equal(t, "3500.00", ledger[4010][0].Balance.StringFixed(2))
+
+	assert.NotContains(t, ledger, 2010, "accounts without legs in the range are omitted")
+}
+
+func TestGeneralLedger_DateOrder(t *testing.T) {
+	dir := t.TempDir()
+	accts := accounts.NewService(accounts.DefaultChart("llc_single_member"))
+	jrnl := journal.NewService(dir, accts)
+	for _, e := range []struct {
+		day    int
+		amount string
+	}{{20, "10.00"}, {5, "3.00"}, {12, "1.50"}} {
+		_, err := jrnl.AddDouble(journal.AddDoubleParams{
+			Date:          date(2025, 1, e.day),
+			Description:   "supplies",
+			DebitAccount:  5030,
+			CreditAccount: 1010,
+			Amount:        dec(e.amount),
+			Status:        model.StatusAutoConfirmed,
+		})
+		require.NoError(t, err)
+	}
+
+	ledger, err := GeneralLedger(jrnl, accts, date(2025, 1, 1), date(2025, 1, 31))
+	require.NoError(t, err)
+	require.Len(t, ledger[5030], 3)
+	assert.Equal(t, date(2025, 1, 5), ledger[5030][0].Leg.Date, "sorted by date, not booking order")
+	assert.Equal(t, "3.00", ledger[5030][0].Balance.StringFixed(2))
+	assert.Equal(t, "4.50", ledger[5030][1].Balance.StringFixed(2))
+	assert.Equal(t, "14.50", ledger[5030][2].Balance.StringFixed(2))
+}
+
+func TestBalanceSheet(t *testing.T) {
+	svc := newTestdataService(t)
+	for _, e := range []journal.AddDoubleParams{
+		{Description: "Owner contribution", DebitAccount: 1010, CreditAccount: 3010, Amount: dec("1000.00")},
+		{Description: "Printer paper", DebitAccount: 5030, CreditAccount: 2010, Amount: dec("50.00")},
+	} {
+		e.Date = date(2025, 2, 10)
+		e.Status = model.StatusAutoConfirmed
+		_, err := svc.journal.AddDouble(e)
+		require.NoError(t, err)
+	}
+
+	bs, err := svc.BalanceSheet(date(2025, 2, 28))
+	require.NoError(t, err)
+
+	require.Len(t, bs.Assets, 1)
+	assert.Equal(t, 1010, bs.Assets[0].AccountID)
+	assert.Equal(t, "4301.76", bs.TotalAssets.StringFixed(2))
+	assert.Equal(t, "50.00", bs.TotalLiabilities.StringFixed(2))
+	require.Len(t, bs.Equity, 1)
+	assert.Equal(t, "1000.00", bs.Equity[0].Total.StringFixed(2))
+	assert.Equal(t, "3251.76", bs.NetIncome.StringFixed(2), "3500.00 revenue less 248.24 expenses")
+	assert.Equal(t, "4251.76", bs.TotalEquity.StringFixed(2))
+
+	// Assets = liabilities + equity.
+	assert.True(t, bs.Balanced())
+	assert.True(t, bs.TotalAssets.Equal(bs.TotalLiabilities.Add(bs.TotalEquity)))
+
+	// As of January the February entries are excluded.
+	bs, err = svc.BalanceSheet(date(2025, 1, 31))
+	require.NoError(t, err)
+	assert.Equal(t, "3301.76", bs.TotalAssets.StringFixed(2))
+	assert.True(t, bs.TotalLiabilities.IsZero())
+	assert.True(t, bs.Balanced())
+}
+
+func TestBalanceSheet_Discrepancy(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "2025", "01", "journal.csv")
+	copyFile(t, filepath.Join("..", "..", "testdata", "journal.csv"), path)
+	copyFile(t, filepath.Join("..", "..", "testdata", "chart-of-accounts.csv"), filepath.Join(dir, "accounts", "chart-of-accounts.csv"))
+
+	// A hand-edited leg with no offsetting side.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString("2025-01-099a,2025-01-20,1010,Stray deposit,25.00,,,,,user-confirmed,,,,\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	accts, err := accounts.Load(dir)
+	require.NoError(t, err)
+	svc := NewService(journal.NewService(dir, accts), accts)
+
+	bs, err := svc.BalanceSheet(date(2025, 1, 31))
+	require.NoError(t, err)
+	assert.False(t, bs.Balanced())
+	assert.Equal(t, "25.00", bs.Discrepancy.StringFixed(2))
+}
+
+func TestAccountTotals_UnknownAccount(t *testing.T) {
+	accts := accounts.NewService([]model.Account{
+		{ID: 5000, Name: "Operating Expenses", Type: model.AccountTypeExpense},
+		{ID: 5100, Name: "Technology", Type: model.AccountTypeExpense, ParentID: 5000},
+	})
+	svc := NewService(journal.NewService(t.TempDir(), accts), accts)
+
+	rows, sum := svc.accountTotals(map[int]decimal.Decimal{5100: dec("40.00"), 5999: dec("15.00")})
+
+	assert.Equal(t, "55.00", sum.StringFixed(2))
+	require.Len(t, rows, 3)
+	assert.Equal(t, 5999, rows[2].AccountID)
+	assert.Equal(t, UnknownAccountName, rows[2].Name)
+	assert.Equal(t, 0, rows[2].Depth)
+	assert.Equal(t, "15.00", rows[2].Total.StringFixed(2))
+
+	top := decimal.Zero
+	for _, r := range rows {
+		if r.Depth == 0 {
+			top = top.Add(r.Total)
+		}
+	}
+	assert.True(t, top.Equal(sum), "top-level rows add up to the sum")
+}
@@ -0,0 +1,130 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is the categorization rules path relative to the repo root.
+const File = "rules/categorization-rules.yaml"
+
+// Rule maps a bank description pattern to a vendor and expense account.
+type Rule struct {
+	VendorPattern string  `yaml:"vendor_pattern"` // "GITHUB*" = prefix match, otherwise exact
+	VendorName    string  `yaml:"vendor_name"`
+	AccountID     int     `yaml:"account_id"`
+	Confidence    float64 `yaml:"confidence"`
+	Source        string  `yaml:"source"` // "seed", "learned", ...
+}
+
+// ruleSet is the on-disk shape of categorization-rules.yaml.
+type ruleSet struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Load reads <repoRoot>/rules/categorization-rules.yaml.
+// Returns an empty slice if the file does not exist.
+func Load(repoRoot string) ([]Rule, error) {
+	data, err := os.ReadFile(filepath.Join(repoRoot, File))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading rules: %w", err)
+	}
+
+	var rs ruleSet
+	if err := yaml.Unmarshal(data, &rs); err != nil {
+		return nil, fmt.Errorf("parsing rules: %w", err)
+	}
+	return rs.Rules, nil
+}
+
+// Save writes rules to <repoRoot>/rules/categorization-rules.yaml.
+func Save(repoRoot string, rules []Rule) error {
+	if err := os.MkdirAll(filepath.Join(repoRoot, "rules"), 0o755); err != nil {
+		return fmt.Errorf("creating rules dir: %w", err)
+	}
+	if rules == nil {
+		rules = []Rule{} // keep "rules: []" rather than "rules: null"
+	}
+	data, err := yaml.Marshal(ruleSet{Rules: rules})
+	if err != nil {
+		return fmt.Errorf("marshaling rules: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoRoot, File), data, 0o644); err != nil {
+		return fmt.Errorf("writing rules: %w", err)
+	}
+	return nil
+}
+
+// AppendRule persists a rule learned from a user correction, with source
+// "learned". If a rule with the same pattern (case-insensitive) already
+// exists it is replaced in place rather than duplicated, since the newer
+// correction supersedes it. Reports whether an existing rule was replaced.
+func AppendRule(repoRoot string, rule Rule) (bool, error) {
+	if strings.TrimSpace(rule.VendorPattern) == "" {
+		return false, errors.New("rule requires a vendor pattern")
+	}
+	rule.Source = "learned"
+
+	existing, err := Load(repoRoot)
+	if err != nil {
+		return false, err
+	}
+
+	replaced := false
+	for i, r := range existing {
+		if strings.EqualFold(strings.TrimSpace(r.VendorPattern), strings.TrimSpace(rule.VendorPattern)) {
+			existing[i] = rule
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		existing = append(existing, rule)
+	}
+
+	return replaced, Save(repoRoot, existing)
+}
+
+// Matches reports whether the rule's pattern matches description.
+// Matching is case-insensitive; a trailing "*" makes it a prefix match.
+func (r Rule) Matches(description string) bool {
+	pattern := strings.ToUpper(strings.TrimSpace(r.VendorPattern))
+	desc := strings.ToUpper(strings.TrimSpace(description))
+	if pattern == "" {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(desc, prefix)
+	}
+	return desc == pattern
+}
+
+// Match returns the best rule for description. An exact match beats any
+// wildcard; among wildcards the longest prefix wins, then the highest
+// confidence. The bool is false when no rule matches.
+func Match(rules []Rule, description string) (Rule, bool) {
+	var best Rule
+	bestScore := -1
+	for _, r := range rules {
+		if !r.Matches(description) {
+			continue
+		}
+		score := len(strings.TrimSuffix(strings.TrimSpace(r.VendorPattern), "*"))
+		if !strings.HasSuffix(r.VendorPattern, "*") {
+			score += 1 << 20 // exact matches always win
+		}
+		if score > bestScore || (score == bestScore && r.Confidence > best.Confidence) {
+			best, bestScore = r, score
+		}
+	}
+	return best, bestScore >= 0
+}
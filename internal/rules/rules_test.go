@@ -0,0 +1,179 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const seedRules = `rules:
+  - vendor_pattern: "GITHUB*"
+    vendor_name: "GitHub"
+    account_id: 5020
+    confidence: 0.98
+    source: "seed"
+  - vendor_pattern: "AWS*"
+    vendor_name: "Amazon Web Services"
+    account_id: 5020
+    confidence: 0.96
+    source: "seed"
+  - vendor_pattern: "USPS PO 1234567890"
+    vendor_name: "USPS"
+    account_id: 5050
+    confidence: 0.99
+    source: "seed"
+`
+
+func writeRules(t *testing.T, contents string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "rules"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, File), []byte(contents), 0o644))
+	return dir
+}
+
+func TestLoad(t *testing.T) {
+	dir := writeRules(t, seedRules)
+
+	rules, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+	assert.Equal(t, "GITHUB*", rules[0].VendorPattern)
+	assert.Equal(t, "GitHub", rules[0].VendorName)
+	assert.Equal(t, 5020, rules[0].AccountID)
+	assert.InDelta(t, 0.98, rules[0].Confidence, 0.001)
+	assert.Equal(t, "seed", rules[0].Source)
+}
+
+func TestLoad_Empty(t *testing.T) {
+	dir := writeRules(t, "rules: []\n")
+	rules, err := Load(dir)
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+}
+
+func TestLoad_NotFound(t *testing.T) {
+	rules, err := Load(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, rules)
+}
+
+func TestLoad_BadYAML(t *testing.T) {
+	dir := writeRules(t, "rules: [unterminated\n")
+	_, err := Load(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parsing rules")
+}
+
+func TestMatch_Exact(t *testing.T) {
+	dir := writeRules(t, seedRules)
+	rules, err := Load(dir)
+	require.NoError(t, err)
+
+	r, ok := Match(rules, "usps po 1234567890")
+	require.True(t, ok)
+	assert.Equal(t, "USPS", r.VendorName)
+	assert.Equal(t, 5050, r.AccountID)
+
+	_, ok = Match(rules, "USPS PO 1234567890 EXTRA")
+	assert.False(t, ok, "patterns without * must match the whole description")
+}
+
+func TestMatch_Wildcard(t *testing.T) {
+	dir := writeRules(t, seedRules)
+	rules, err := Load(dir)
+	require.NoError(t, err)
+
+	r, ok := Match(rules, "GITHUB *PRO SUBSCRIPTION")
+	require.True(t, ok)
+	assert.Equal(t, "GitHub", r.VendorName)
+
+	r, ok = Match(rules, "AWS *SERVICES")
+	require.True(t, ok)
+	assert.Equal(t, "Amazon Web Services", r.VendorName)
+}
+
+func TestMatch_NoMatch(t *testing.T) {
+	dir := writeRules(t, seedRules)
+	rules, err := Load(dir)
+	require.NoError(t, err)
+
+	_, ok := Match(rules, "AMZN MKTP US*ABC123")
+	assert.False(t, ok)
+
+	_, ok = Match(nil, "GITHUB")
+	assert.False(t, ok)
+}
+
+func TestMatch_Precedence(t *testing.T) {
+	rules := []Rule{
+		{VendorPattern: "AMZN*", VendorName: "Amazon", Confidence: 0.80},
+		{VendorPattern: "AMZN MKTP*", VendorName: "Amazon Marketplace", Confidence: 0.70},
+		{VendorPattern: "AMZN MKTP US*ABC123", VendorName: "Exact", Confidence: 0.50},
+	}
+
+	r, ok := Match(rules, "AMZN MKTP US*XYZ")
+	require.True(t, ok)
+	assert.Equal(t, "Amazon Marketplace", r.VendorName, "longest prefix wins")
+
+	r, ok = Match(rules, "AMZN MKTP US*ABC123")
+	require.True(t, ok)
+	assert.Equal(t, "Exact", r.VendorName, "exact beats wildcard")
+}
+
+func TestAppendRule(t *testing.T) {
+	dir := writeRules(t, seedRules)
+
+	replaced, err := AppendRule(dir, Rule{
+		VendorPattern: "DROPBOX*",
+		VendorName:    "Dropbox",
+		AccountID:     5020,
+		Confidence:    0.90,
+		Source:        "ignored",
+	})
+	require.NoError(t, err)
+	assert.False(t, replaced)
+
+	rules, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, rules, 4)
+	assert.Equal(t, "learned", rules[3].Source)
+
+	r, ok := Match(rules, "DROPBOX *BUSINESS PLAN")
+	require.True(t, ok, "learned rule should be matchable after reload")
+	assert.Equal(t, "Dropbox", r.VendorName)
+}
+
+func TestAppendRule_DedupesPattern(t *testing.T) {
+	dir := writeRules(t, seedRules)
+
+	replaced, err := AppendRule(dir, Rule{VendorPattern: "github*", VendorName: "GitHub", AccountID: 5040, Confidence: 0.99})
+	require.NoError(t, err)
+	assert.True(t, replaced)
+
+	rules, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, rules, 3, "no duplicate rule added")
+	assert.Equal(t, 5040, rules[0].AccountID, "correction replaces the old rule in place")
+	assert.Equal(t, "learned", rules[0].Source)
+}
+
+func TestAppendRule_NoFile(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := AppendRule(dir, Rule{VendorPattern: "ZOOM*", AccountID: 5020, Confidence: 0.9})
+	require.NoError(t, err)
+
+	rules, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "ZOOM*", rules[0].VendorPattern)
+}
+
+func TestAppendRule_EmptyPattern(t *testing.T) {
+	_, err := AppendRule(t.TempDir(), Rule{AccountID: 5020})
+	require.Error(t, err)
+}
@@ -0,0 +1,110 @@
+package sandbox
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// AgentMeta is the metadata an agent script declares in its leading
+// docstring as "key: value" lines:
+//
+//	"""
+//	name: Daily Ingest
+//	trigger: schedule
+//	primitives: importer_scan, importer_parse, journal_add_double
+//	"""
+type AgentMeta struct {
+	Name        string
+	Trigger     string
+	Schedule    string
+	Description string
+	// Primitives lists the external functions the agent may call. Nil means
+	// the agent declared none and gets every registered primitive.
+	Primitives []string
+}
+
+// ParseAgentMeta reads the metadata docstring at the top of an agent
+// script. Scripts without one yield a zero AgentMeta. Unknown keys are
+// ignored. Without a description key, the first other non-blank line of the
+// docstring is the description, so a plain prose docstring still reads well
+// in cleared agent list.
+func ParseAgentMeta(script string) AgentMeta {
+	var meta AgentMeta
+	body, ok := leadingDocstring(script)
+	if !ok {
+		return meta
+	}
+
+	var summary string
+	for _, line := range strings.Split(body, "\n") {
+		key, value, _ := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "name":
+			meta.Name = value
+		case "trigger":
+			meta.Trigger = value
+		case "schedule":
+			meta.Schedule = value
+		case "description":
+			meta.Description = value
+		case "primitives":
+			meta.Primitives = []string{}
+			for _, name := range strings.Split(value, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					meta.Primitives = append(meta.Primitives, name)
+				}
+			}
+		default:
+			if summary == "" {
+				summary = strings.TrimSpace(line)
+			}
+		}
+	}
+	if meta.Description == "" {
+		meta.Description = summary
+	}
+	return meta
+}
+
+// Externals returns the primitives to expose to the agent: its declared
+// subset, or all of available when it declared none. Declaring a primitive
+// that is not available is an error.
+func (m AgentMeta) Externals(available []string) ([]string, error) {
+	if m.Primitives == nil {
+		return available, nil
+	}
+	for _, name := range m.Primitives {
+		if !slices.Contains(available, name) {
+			return nil, fmt.Errorf("unknown primitive %q in agent primitives", name)
+		}
+	}
+	return m.Primitives, nil
+}
+
+// leadingDocstring returns the body of a triple-quoted string that opens the
+// script, skipping leading blank and comment lines.
+func leadingDocstring(script string) (string, bool) {
+	rest := script
+	for {
+		trimmed := strings.TrimLeft(rest, " \t\r\n")
+		if !strings.HasPrefix(trimmed, "#") {
+			rest = trimmed
+			break
+		}
+		_, after, found := strings.Cut(trimmed, "\n")
+		if !found {
+			return "", false
+		}
+		rest = after
+	}
+
+	for _, quote := range []string{`"""`, `'''`} {
+		if after, ok := strings.CutPrefix(rest, quote); ok {
+			body, _, closed := strings.Cut(after, quote)
+			return body, closed
+		}
+	}
+	return "", false
+}
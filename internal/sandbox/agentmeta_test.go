@@ -0,0 +1,86 @@
+package sandbox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAgentMeta(t *testing.T) {
+	script := `# agents/ingest.py
+"""
+name: Daily Ingest
+trigger: schedule
+schedule: 0 6 * * *
+description: Import new bank transactions: classify inline
+primitives: importer_scan, ctx_log ,
+"""
+files = importer_scan()
+`
+	meta := ParseAgentMeta(script)
+	assert.Equal(t, "Daily Ingest", meta.Name)
+	assert.Equal(t, "schedule", meta.Trigger)
+	assert.Equal(t, "0 6 * * *", meta.Schedule)
+	assert.Equal(t, "Import new bank transactions: classify inline", meta.Description)
+	assert.Equal(t, []string{"importer_scan", "ctx_log"}, meta.Primitives)
+}
+
+func TestParseAgentMeta_NoDocstring(t *testing.T) {
+	meta := ParseAgentMeta("x = 1\n\"\"\"primitives: git_commit\"\"\"\n")
+	assert.Equal(t, AgentMeta{}, meta, "only a leading docstring counts")
+
+	meta = ParseAgentMeta(`"""unterminated`)
+	assert.Equal(t, AgentMeta{}, meta)
+}
+
+func TestParseAgentMeta_ProseDescription(t *testing.T) {
+	meta := ParseAgentMeta("\"\"\"\nCategorize pending entries.\n\nprimitives: ctx_log\n\"\"\"\n")
+	assert.Equal(t, "Categorize pending entries.", meta.Description)
+	assert.Equal(t, []string{"ctx_log"}, meta.Primitives)
+
+	meta = ParseAgentMeta("\"\"\"\nNot this one.\ndescription: Explicit wins\n\"\"\"\n")
+	assert.Equal(t, "Explicit wins", meta.Description)
+}
+
+func TestAgentMeta_Externals(t *testing.T) {
+	available := []string{"ctx_log", "git_commit", "journal_query"}
+
+	all, err := AgentMeta{}.Externals(available)
+	require.NoError(t, err)
+	assert.Equal(t, available, all, "no declaration exposes everything")
+
+	subset, err := ParseAgentMeta("\"\"\"\nprimitives: journal_query, ctx_log\n\"\"\"\n").Externals(available)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"journal_query", "ctx_log"}, subset)
+
+	none, err := ParseAgentMeta("'''\nprimitives:\n'''\n").Externals(available)
+	require.NoError(t, err)
+	assert.Empty(t, none, "an empty declaration allows nothing")
+
+	_, err = AgentMeta{Primitives: []string{"journal_nuke"}}.Externals(available)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"journal_nuke"`)
+}
+
+func TestBridge_DisallowedPrimitive(t *testing.T) {
+	b := newFakeBridge(t)
+	committed := false
+	b.RegisterPrimitive("ctx_log", func(_ []any, _ map[string]any) (any, error) { return true, nil })
+	b.RegisterPrimitive("git_commit", func(_ []any, _ map[string]any) (any, error) {
+		committed = true
+		return true, nil
+	})
+
+	script := "\"\"\"\nprimitives: ctx_log\n\"\"\"\nctx_log(\"hi\")\ngit_commit(\"sneaky\")\n"
+	externals, err := ParseAgentMeta(script).Externals(b.PrimitiveNames())
+	require.NoError(t, err)
+
+	_, err = b.RunScript(script, externals)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "git_commit")
+	assert.False(t, committed)
+
+	_, err = b.RunScript("\"\"\"\nprimitives: ctx_log\n\"\"\"\nctx_log(\"hi\")\n", externals)
+	require.NoError(t, err, "declared primitives still work")
+}
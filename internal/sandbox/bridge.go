@@ -2,14 +2,18 @@ package sandbox
 
 import (
 	"bufio"
+	"context"
 	_ "embed"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -17,6 +21,33 @@ import (
 //go:embed bridge.py
 var bridgeScript []byte
 
+// DefaultScriptTimeout bounds RunScript. Use RunScriptContext for a
+// different deadline.
+const DefaultScriptTimeout = 30 * time.Second
+
+// DefaultMaxScripts is how many scripts a Bridge runs at once unless
+// WithMaxScripts says otherwise. Further RunScript calls wait their turn.
+const DefaultMaxScripts = 8
+
+// DefaultShutdownTimeout is how long Shutdown waits for the bridge to exit
+// before killing it.
+const DefaultShutdownTimeout = 5 * time.Second
+
+// DefaultReadyTimeout is how long cleared waits for a newly started bridge
+// to answer its first ping. A cold uv cache can take a while to install
+// pydantic-monty.
+const DefaultReadyTimeout = 60 * time.Second
+
+// errBridgeExited is returned for requests in flight when the bridge
+// process dies.
+var errBridgeExited = errors.New("bridge process exited unexpectedly")
+
+// bridgeCommand builds the command that runs bridge.py. Tests replace it to
+// run the bridge without uv.
+var bridgeCommand = func(bridgePath string) *exec.Cmd {
+	return exec.Command("uv", "run", "--with", "pydantic-monty", "--no-project", "python3", bridgePath)
+}
+
 // JSON-RPC 2.0 message types.
 
 type Request struct {
@@ -61,71 +92,194 @@ type PrimitiveHandler func(args []any, kwargs map[string]any) (any, error)
 
 // Bridge manages the Python bridge subprocess and JSON-RPC communication.
 type Bridge struct {
-	cmd      *exec.Cmd
-	stdin    io.WriteCloser
-	reader   *bufio.Reader
-	mu       sync.Mutex
-	nextID   int
-	pending  map[int]chan *Response
-	handlers map[string]PrimitiveHandler
-	tmpDir   string
-	done     chan struct{}
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	mu        sync.Mutex
+	nextID    int
+	pending   map[int]chan *Response
+	abandoned map[int]chan *Response // given up on by their caller, until answered
+	handlers  map[string]PrimitiveHandler
+	metrics   map[string]PrimitiveMetrics
+	tmpDir    string
+	done      chan struct{} // closed when the current process's output ends
+	scripts   chan struct{} // semaphore: one slot per running script
+
+	stderrHandler func(line string)
+
+	restartMu sync.Mutex // serializes Restart and auto-restart
+}
+
+// PrimitiveMetrics is the call count and cumulative handler time for one
+// primitive, failed calls included.
+type PrimitiveMetrics struct {
+	Calls    int
+	Duration time.Duration
+}
+
+// BridgeOption configures NewBridge.
+type BridgeOption func(*bridgeOptions)
+
+type bridgeOptions struct {
+	readyTimeout time.Duration
+	maxScripts   int
+}
+
+// WithReadyTimeout makes NewBridge wait up to timeout for the bridge to
+// answer a ping, so a slow start fails clearly instead of timing out the
+// first script.
+func WithReadyTimeout(timeout time.Duration) BridgeOption {
+	return func(o *bridgeOptions) { o.readyTimeout = timeout }
+}
+
+// WithMaxScripts bounds how many scripts run on the bridge at once; n < 1
+// means DefaultMaxScripts. The bridge is a single Python interpreter, so
+// more concurrency only adds contention.
+func WithMaxScripts(n int) BridgeOption {
+	return func(o *bridgeOptions) { o.maxScripts = n }
 }
 
 // NewBridge starts the Monty sandbox bridge subprocess.
 // The embedded bridge.py is written to a temp directory and run via uv.
-func NewBridge() (*Bridge, error) {
+func NewBridge(opts ...BridgeOption) (*Bridge, error) {
+	var o bridgeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxScripts < 1 {
+		o.maxScripts = DefaultMaxScripts
+	}
+
 	tmpDir, err := os.MkdirTemp("", "cleared-bridge-*")
 	if err != nil {
 		return nil, fmt.Errorf("creating temp dir: %w", err)
 	}
 
-	bridgePath := filepath.Join(tmpDir, "bridge.py")
-	if err := os.WriteFile(bridgePath, bridgeScript, 0o644); err != nil {
+	b := &Bridge{
+		pending:   make(map[int]chan *Response),
+		abandoned: make(map[int]chan *Response),
+		handlers:  make(map[string]PrimitiveHandler),
+		metrics:   make(map[string]PrimitiveMetrics),
+		tmpDir:    tmpDir,
+		scripts:   make(chan struct{}, o.maxScripts),
+	}
+	if err := b.start(); err != nil {
 		os.RemoveAll(tmpDir)
-		return nil, fmt.Errorf("writing bridge.py: %w", err)
+		return nil, err
+	}
+	if o.readyTimeout > 0 {
+		if err := b.Ping(o.readyTimeout); err != nil {
+			_ = b.cmd.Process.Kill()
+			_ = b.cmd.Wait()
+			os.RemoveAll(tmpDir)
+			return nil, fmt.Errorf("bridge not ready: %w", err)
+		}
+	}
+	return b, nil
+}
+
+// start writes bridge.py and launches a fresh subprocess with its own read
+// loop. Registered handlers are kept.
+func (b *Bridge) start() error {
+	bridgePath := filepath.Join(b.tmpDir, "bridge.py")
+	if err := os.WriteFile(bridgePath, bridgeScript, 0o644); err != nil {
+		return fmt.Errorf("writing bridge.py: %w", err)
 	}
 
-	cmd := exec.Command("uv", "run", "--with", "pydantic-monty", "--no-project", "python3", bridgePath)
-	cmd.Dir = tmpDir
-	cmd.Stderr = os.Stderr
+	cmd := bridgeCommand(bridgePath)
+	cmd.Dir = b.tmpDir
 
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
-		os.RemoveAll(tmpDir)
-		return nil, fmt.Errorf("stdin pipe: %w", err)
+		return fmt.Errorf("stdin pipe: %w", err)
 	}
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		os.RemoveAll(tmpDir)
-		return nil, fmt.Errorf("stdout pipe: %w", err)
+		return fmt.Errorf("stdout pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("stderr pipe: %w", err)
 	}
 
 	if err := cmd.Start(); err != nil {
-		os.RemoveAll(tmpDir)
-		return nil, fmt.Errorf("start bridge: %w", err)
+		return fmt.Errorf("start bridge: %w", err)
 	}
 
-	b := &Bridge{
-		cmd:      cmd,
-		stdin:    stdin,
-		reader:   bufio.NewReader(stdout),
-		pending:  make(map[int]chan *Response),
-		handlers: make(map[string]PrimitiveHandler),
-		tmpDir:   tmpDir,
-		done:     make(chan struct{}),
-	}
-	go b.readLoop()
-	return b, nil
+	done := make(chan struct{})
+	b.mu.Lock()
+	b.cmd = cmd
+	b.stdin = stdin
+	b.done = done
+	b.mu.Unlock()
+
+	go b.readLoop(bufio.NewReader(stdout), done)
+	go b.readStderr(stderr)
+	return nil
+}
+
+// Restart kills the bridge subprocess (if still running) and launches a new
+// one. Scripts in flight on the old process fail with "bridge process exited
+// unexpectedly" and may be retried.
+func (b *Bridge) Restart() error {
+	b.restartMu.Lock()
+	defer b.restartMu.Unlock()
+	return b.restartLocked()
 }
 
-// RegisterPrimitive registers a handler for a named primitive.
+func (b *Bridge) restartLocked() error {
+	b.mu.Lock()
+	cmd, stdin := b.cmd, b.stdin
+	b.mu.Unlock()
+
+	_ = stdin.Close()
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait() // reaps the process and closes stdout, ending the old read loop
+
+	if err := b.start(); err != nil {
+		return fmt.Errorf("restarting bridge: %w", err)
+	}
+	return nil
+}
+
+// ensureRunning restarts the bridge if its process has exited.
+func (b *Bridge) ensureRunning() error {
+	b.restartMu.Lock()
+	defer b.restartMu.Unlock()
+
+	b.mu.Lock()
+	done := b.done
+	b.mu.Unlock()
+
+	select {
+	case <-done:
+		return b.restartLocked()
+	default:
+		return nil
+	}
+}
+
+// RegisterPrimitive registers a handler for a named primitive, replacing any
+// earlier handler with the same name.
 func (b *Bridge) RegisterPrimitive(name string, handler PrimitiveHandler) {
+	b.mu.Lock()
 	b.handlers[name] = handler
+	b.mu.Unlock()
+}
+
+// SetStderrHandler registers fn to receive each non-blank line the bridge
+// writes to stderr, such as Python tracebacks and uv output. Without a
+// handler the lines are dropped; a failed script's error carries its
+// traceback either way.
+func (b *Bridge) SetStderrHandler(fn func(line string)) {
+	b.mu.Lock()
+	b.stderrHandler = fn
+	b.mu.Unlock()
 }
 
 // PrimitiveNames returns the names of all registered primitives.
 func (b *Bridge) PrimitiveNames() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	names := make([]string, 0, len(b.handlers))
 	for name := range b.handlers {
 		names = append(names, name)
@@ -133,44 +287,191 @@ func (b *Bridge) PrimitiveNames() []string {
 	return names
 }
 
+// Metrics returns per-primitive call counts and durations since the bridge
+// started or since the last ResetMetrics.
+func (b *Bridge) Metrics() map[string]PrimitiveMetrics {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return maps.Clone(b.metrics)
+}
+
+// ResetMetrics clears the recorded metrics.
+func (b *Bridge) ResetMetrics() {
+	b.mu.Lock()
+	clear(b.metrics)
+	b.mu.Unlock()
+}
+
+func (b *Bridge) recordCall(name string, d time.Duration) {
+	b.mu.Lock()
+	m := b.metrics[name]
+	m.Calls++
+	m.Duration += d
+	b.metrics[name] = m
+	b.mu.Unlock()
+}
+
 // RunScript sends a script to the bridge for execution. The externals list
-// declares which primitive functions the script may call. Times out after 30s.
+// declares which primitive functions the script may call. Times out after
+// DefaultScriptTimeout.
 func (b *Bridge) RunScript(script string, externals []string) (any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultScriptTimeout)
+	defer cancel()
+	return b.RunScriptContext(ctx, script, externals)
+}
+
+// RunScriptContext is RunScript bounded by ctx instead of the default
+// timeout. If ctx ends first the script's result is discarded, but the
+// script keeps its slot until the bridge finishes it, since the bridge has
+// no way to stop a running script. Time spent waiting for a free script
+// slot counts against ctx.
+//
+// If the bridge process has died since the last call it is restarted first.
+func (b *Bridge) RunScriptContext(ctx context.Context, script string, externals []string) (any, error) {
+	select {
+	case b.scripts <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting to run script: %w", ctx.Err())
+	}
+	release := func() { <-b.scripts }
+
+	if err := b.ensureRunning(); err != nil {
+		release()
+		return nil, err
+	}
+
+	resp, err := b.call(ctx, "run", map[string]any{"script": script, "external_functions": externals}, release)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return nil, fmt.Errorf("script execution timed out: %w", err)
+	case errors.Is(err, context.Canceled):
+		return nil, fmt.Errorf("script execution cancelled: %w", err)
+	case err != nil:
+		return nil, err
+	}
+	if resp.Error != nil {
+		data, _ := resp.Error.Data.(map[string]any)
+		if resp.Error.Code != CodePrimitiveFailed {
+			return nil, &PrimitiveError{Code: resp.Error.Code, Message: resp.Error.Message, Data: data}
+		}
+		// Errors raised in the script itself carry their traceback.
+		if tb, _ := data["traceback"].(string); strings.TrimSpace(tb) != "" {
+			lines := strings.Split(strings.TrimRight(tb, "\n"), "\n")
+			return nil, fmt.Errorf("%s\ntraceback:\n  %s", resp.Error.Message, strings.Join(lines, "\n  "))
+		}
+		return nil, errors.New(resp.Error.Message)
+	}
+	return resp.Result, nil
+}
+
+// Ping checks that the bridge process is up and reading requests: it sends
+// a ping, which bridge.py answers without running anything, and waits up to
+// timeout for the reply.
+func (b *Bridge) Ping(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	resp, err := b.call(ctx, "ping", nil, nil)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("ping timed out after %s", timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("ping: %s", resp.Error.Message)
+	}
+	return nil
+}
+
+// call sends a request and waits for its response. It returns
+// errBridgeExited if the process dies first, or ctx's error if ctx ends
+// first; either way a late response is discarded. settled, if not nil, runs
+// once the bridge has answered or exited, which is after call returns when
+// ctx ends first.
+func (b *Bridge) call(ctx context.Context, method string, params any, settled func()) (*Response, error) {
+	if settled == nil {
+		settled = func() {}
+	}
 	b.mu.Lock()
 	b.nextID++
 	id := b.nextID
 	ch := make(chan *Response, 1)
 	b.pending[id] = ch
+	done := b.done
 	b.mu.Unlock()
 
-	if err := b.send(Request{
-		JSONRPC: "2.0",
-		Method:  "run",
-		Params:  map[string]any{"script": script, "external_functions": externals},
-		ID:      id,
-	}); err != nil {
+	if err := b.send(Request{JSONRPC: "2.0", Method: method, Params: params, ID: id}); err != nil {
+		b.forget(id)
+		settled()
 		return nil, err
 	}
 
 	select {
 	case resp := <-ch:
-		if resp.Error != nil {
-			return nil, fmt.Errorf("%s", resp.Error.Message)
+		settled()
+		return resp, nil
+	case <-done:
+		b.forget(id)
+		settled()
+		return nil, errBridgeExited
+	case <-ctx.Done():
+		// The bridge is still working on the request; wait for its late
+		// response, or its exit, before settling.
+		b.mu.Lock()
+		if _, ok := b.pending[id]; ok {
+			delete(b.pending, id)
+			b.abandoned[id] = ch
 		}
-		return resp.Result, nil
-	case <-b.done:
-		return nil, errors.New("bridge process exited unexpectedly")
-	case <-time.After(30 * time.Second):
-		return nil, errors.New("script execution timed out after 30s")
+		b.mu.Unlock()
+		go func() {
+			select {
+			case <-ch:
+			case <-done:
+			}
+			b.forget(id)
+			settled()
+		}()
+		return nil, ctx.Err()
 	}
 }
 
-// Shutdown sends the shutdown notification and cleans up.
+// forget drops a pending or abandoned request so a late response is
+// discarded.
+func (b *Bridge) forget(id int) {
+	b.mu.Lock()
+	delete(b.pending, id)
+	delete(b.abandoned, id)
+	b.mu.Unlock()
+}
+
+// Shutdown sends the shutdown notification and cleans up, killing the
+// bridge if it has not exited after DefaultShutdownTimeout.
 func (b *Bridge) Shutdown() error {
+	return b.ShutdownTimeout(DefaultShutdownTimeout)
+}
+
+// ShutdownTimeout is Shutdown with a different grace period. If the process
+// is still running after timeout it is killed and an error returned. The
+// temp dir is removed either way.
+func (b *Bridge) ShutdownTimeout(timeout time.Duration) error {
+	defer os.RemoveAll(b.tmpDir)
+
 	_ = b.send(Request{JSONRPC: "2.0", Method: "shutdown"})
-	err := b.cmd.Wait()
-	os.RemoveAll(b.tmpDir)
-	return err
+	b.mu.Lock()
+	cmd := b.cmd
+	b.mu.Unlock()
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	select {
+	case err := <-exited:
+		return err
+	case <-time.After(timeout):
+		_ = cmd.Process.Kill()
+		<-exited
+		return fmt.Errorf("bridge did not exit within %s and was killed", timeout)
+	}
 }
 
 func (b *Bridge) send(msg any) error {
@@ -179,21 +480,66 @@ func (b *Bridge) send(msg any) error {
 		return fmt.Errorf("marshal: %w", err)
 	}
 	b.mu.Lock()
-	_, err = fmt.Fprintf(b.stdin, "%s\n", data)
+	err = writeMessage(b.stdin, data)
 	b.mu.Unlock()
 	return err
 }
 
-func (b *Bridge) readLoop() {
-	defer close(b.done)
+// writeMessage frames body LSP-style: a Content-Length header, a blank
+// line, then exactly that many bytes of JSON.
+func writeMessage(w io.Writer, body []byte) error {
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err := w.Write(body)
+	return err
+}
+
+// readMessage reads one message body. Content-Length framed messages are
+// read by size, so bodies may hold any bytes. A line starting with '{' is
+// accepted as a legacy newline-delimited message.
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	length := -1
 	for {
-		line, err := b.reader.ReadString('\n')
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" && length < 0:
+			continue // stray blank line between messages
+		case trimmed == "":
+			body := make([]byte, length)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return nil, fmt.Errorf("reading %d-byte body: %w", length, err)
+			}
+			return body, nil
+		case length < 0 && strings.HasPrefix(trimmed, "{"):
+			return []byte(trimmed), nil
+		}
+
+		name, value, ok := strings.Cut(trimmed, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid Content-Length %q", value)
+			}
+			length = n
+		}
+	}
+}
+
+func (b *Bridge) readLoop(reader *bufio.Reader, done chan struct{}) {
+	defer close(done)
+	for {
+		body, err := readMessage(reader)
 		if err != nil {
 			return
 		}
 
 		var msg rawMessage
-		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+		if err := json.Unmarshal(body, &msg); err != nil {
 			continue
 		}
 
@@ -204,6 +550,8 @@ func (b *Bridge) readLoop() {
 			ch, ok := b.pending[id]
 			if ok {
 				delete(b.pending, id)
+			} else if ch, ok = b.abandoned[id]; ok {
+				delete(b.abandoned, id)
 			}
 			b.mu.Unlock()
 			if ok {
@@ -225,29 +573,61 @@ func (b *Bridge) readLoop() {
 	}
 }
 
+// readStderr reads the bridge's stderr until the process exits, passing
+// complete lines to addStderr.
+func (b *Bridge) readStderr(r io.Reader) {
+	buf := make([]byte, 64<<10)
+	var partial string
+	for {
+		n, err := r.Read(buf)
+		lines := strings.Split(partial+string(buf[:n]), "\n")
+		if err != nil {
+			b.addStderr(lines)
+			return
+		}
+		partial = lines[len(lines)-1]
+		b.addStderr(lines[:len(lines)-1])
+	}
+}
+
+// addStderr passes the non-blank lines to the stderr handler.
+func (b *Bridge) addStderr(lines []string) {
+	b.mu.Lock()
+	handler := b.stderrHandler
+	b.mu.Unlock()
+	if handler == nil {
+		return
+	}
+	for _, line := range lines {
+		if line = strings.TrimRight(line, "\r"); strings.TrimSpace(line) != "" {
+			handler(line)
+		}
+	}
+}
+
 func (b *Bridge) handleCallback(msg rawMessage) {
 	var params PrimitiveParams
 	if msg.Params != nil {
 		_ = json.Unmarshal(msg.Params, &params)
 	}
 
+	b.mu.Lock()
 	handler, ok := b.handlers[msg.Method]
+	b.mu.Unlock()
 	if !ok {
 		_ = b.send(Response{
 			JSONRPC: "2.0",
-			Error:   &RPCError{Code: -32601, Message: "unknown primitive: " + msg.Method},
+			Error:   &RPCError{Code: codeMethodNotFound, Message: "unknown primitive: " + msg.Method},
 			ID:      msg.ID,
 		})
 		return
 	}
 
+	start := time.Now()
 	result, err := handler(params.Args, params.Kwargs)
+	b.recordCall(msg.Method, time.Since(start))
 	if err != nil {
-		_ = b.send(Response{
-			JSONRPC: "2.0",
-			Error:   &RPCError{Code: -32000, Message: err.Error()},
-			ID:      msg.ID,
-		})
+		_ = b.send(Response{JSONRPC: "2.0", Error: toRPCError(err), ID: msg.ID})
 		return
 	}
 
@@ -5,6 +5,7 @@ The bridge is the JSON-RPC *server*. Go is the *client*.
 
 Methods (Go → Bridge):
   run(script, external_functions) → starts script execution, returns final output
+  ping() → "pong", answered immediately; a readiness check
   shutdown() → clean exit (notification, no response)
 
 During script execution, the bridge becomes a *client* calling back to Go:
@@ -13,6 +14,11 @@ During script execution, the bridge becomes a *client* calling back to Go:
 
 This allows pipelining: multiple scripts can run concurrently, each making
 external function calls with unique IDs. Go responds by ID, not by order.
+
+Framing (both directions), LSP-style so bodies may contain any bytes:
+  Content-Length: <n>\r\n\r\n<n bytes of UTF-8 JSON>
+A line starting with '{' is still accepted as a legacy newline-delimited
+message.
 """
 
 import json
@@ -23,6 +29,17 @@ import traceback
 from pydantic_monty import Monty, MontyComplete, MontySnapshot
 
 
+class PrimitiveError(Exception):
+    """A primitive failed on the Go side. code and data come from the JSON-RPC
+    error so callers can branch on the kind of failure."""
+
+    def __init__(self, primitive, code, message, data=None):
+        super().__init__(message)
+        self.primitive = primitive
+        self.code = code
+        self.data = data
+
+
 class JsonRpcBridge:
     def __init__(self):
         self._next_id = 0
@@ -37,9 +54,12 @@ class JsonRpcBridge:
             return self._next_id
 
     def send(self, msg: dict) -> None:
+        body = json.dumps(msg).encode("utf-8")
         with self._write_lock:
-            sys.stdout.write(json.dumps(msg) + "\n")
-            sys.stdout.flush()
+            out = sys.stdout.buffer
+            out.write(b"Content-Length: %d\r\n\r\n" % len(body))
+            out.write(body)
+            out.flush()
 
     def send_result(self, id, result) -> None:
         self.send({"jsonrpc": "2.0", "result": result, "id": id})
@@ -69,8 +89,9 @@ class JsonRpcBridge:
         with self._pending_lock:
             entry = self._pending.pop(call_id)
 
-        if entry["error"] is not None:
-            raise Exception(entry["error"]["message"])
+        err = entry["error"]
+        if err is not None:
+            raise PrimitiveError(method, err.get("code", -32000), err["message"], err.get("data"))
         return entry["result"]
 
     def handle_response(self, msg: dict) -> None:
@@ -97,6 +118,8 @@ class JsonRpcBridge:
         progress = m.start()
 
         while isinstance(progress, MontySnapshot):
+            if progress.function_name not in external_functions:
+                raise NameError(f"primitive not allowed: {progress.function_name}")
             rpc_params = {"args": list(progress.args)}
             if progress.kwargs:
                 rpc_params["kwargs"] = dict(progress.kwargs)
@@ -108,13 +131,13 @@ class JsonRpcBridge:
 
     def run(self) -> None:
         """Main loop: read JSON-RPC messages, dispatch requests and responses."""
-        for line in sys.stdin:
-            line = line.strip()
-            if not line:
-                continue
+        while True:
+            body = read_message(sys.stdin.buffer)
+            if body is None:
+                return
 
             try:
-                msg = json.loads(line)
+                msg = json.loads(body)
             except json.JSONDecodeError:
                 self.send_error(None, -32700, "Parse error")
                 continue
@@ -132,6 +155,10 @@ class JsonRpcBridge:
             if method == "shutdown":
                 return
 
+            if method == "ping":
+                self.send_result(request_id, "pong")
+                continue
+
             if method == "run":
                 # Run in a thread to allow concurrent scripts
                 t = threading.Thread(
@@ -146,13 +173,45 @@ class JsonRpcBridge:
     def _safe_run(self, params, request_id):
         try:
             self.handle_run(params, request_id)
+        except PrimitiveError as e:
+            # Pass typed failures through so the host sees the original code.
+            self.send_error(request_id, e.code, str(e), e.data)
         except Exception as e:
+            # Also written to stderr so the host records it in the agent log.
+            sys.stderr.write(traceback.format_exc())
+            sys.stderr.flush()
             self.send_error(request_id, -32000, str(e), {
                 "type": type(e).__name__,
                 "traceback": traceback.format_exc(),
             })
 
 
+def read_message(stream):
+    """Read one message body, or None at EOF.
+
+    Content-Length framed bodies are read by size. A line starting with '{'
+    is returned as a legacy newline-delimited message.
+    """
+    length = None
+    while True:
+        line = stream.readline()
+        if not line:
+            return None
+        line = line.strip()
+        if not line:
+            if length is None:
+                continue  # stray blank line between messages
+            body = stream.read(length)
+            if len(body) < length:
+                return None
+            return body
+        if length is None and line.startswith(b"{"):
+            return line
+        name, sep, value = line.partition(b":")
+        if sep and name.strip().lower() == b"content-length":
+            length = int(value.strip())
+
+
 def convert_output(value):
     """Convert Monty output to JSON-serializable form."""
     if value is None:
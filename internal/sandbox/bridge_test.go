@@ -1,8 +1,19 @@
 package sandbox
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -126,3 +137,403 @@ func TestBridge_TrueResult(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, true, result)
 }
+
+// newFakeBridge starts the real bridge.py under plain python3 with the
+// testdata stand-in for pydantic_monty, so protocol behavior can be tested
+// without uv.
+func newFakeBridge(t *testing.T, opts ...BridgeOption) *Bridge {
+	t.Helper()
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available, skipping bridge test")
+	}
+	fakeMonty, err := filepath.Abs(filepath.Join("testdata", "fakemonty"))
+	require.NoError(t, err)
+
+	orig := bridgeCommand
+	bridgeCommand = func(bridgePath string) *exec.Cmd {
+		cmd := exec.Command("python3", bridgePath)
+		cmd.Env = append(os.Environ(), "PYTHONPATH="+fakeMonty)
+		return cmd
+	}
+	t.Cleanup(func() { bridgeCommand = orig })
+
+	b, err := NewBridge(opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = b.Shutdown() })
+	return b
+}
+
+func (b *Bridge) pendingCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.pending)
+}
+
+func TestBridge_FakeMonty(t *testing.T) {
+	b := newFakeBridge(t)
+	b.RegisterPrimitive("add", func(args []any, _ map[string]any) (any, error) {
+		return args[0].(float64) + args[1].(float64), nil
+	})
+
+	result, err := b.RunScript("x = 2\nadd(x, 3)", []string{"add"})
+	require.NoError(t, err)
+	assert.InDelta(t, float64(5), result, 0.001)
+}
+
+func TestBridge_Ping(t *testing.T) {
+	b := newFakeBridge(t, WithReadyTimeout(10*time.Second))
+
+	start := time.Now()
+	require.NoError(t, b.Ping(5*time.Second))
+	assert.Less(t, time.Since(start), time.Second, "answered without running a script")
+	assert.Zero(t, b.pendingCount())
+}
+
+func TestNewBridge_NotReady(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available, skipping bridge test")
+	}
+	orig := bridgeCommand
+	bridgeCommand = func(string) *exec.Cmd {
+		return exec.Command("python3", "-c", "import time; time.sleep(30)")
+	}
+	t.Cleanup(func() { bridgeCommand = orig })
+
+	start := time.Now()
+	_, err := NewBridge(WithReadyTimeout(200 * time.Millisecond))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bridge not ready: ping timed out")
+	assert.Less(t, time.Since(start), 5*time.Second, "the unresponsive process is killed")
+}
+
+func TestBridge_ShutdownTimeout(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available, skipping bridge test")
+	}
+	orig := bridgeCommand
+	bridgeCommand = func(string) *exec.Cmd {
+		// Never reads stdin, so the shutdown notification is ignored.
+		return exec.Command("python3", "-c", "import time; time.sleep(30)")
+	}
+	t.Cleanup(func() { bridgeCommand = orig })
+
+	b, err := NewBridge()
+	require.NoError(t, err)
+
+	start := time.Now()
+	err = b.ShutdownTimeout(200 * time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "was killed")
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.NoDirExists(t, b.tmpDir)
+}
+
+func TestBridge_ScriptErrorIncludesTraceback(t *testing.T) {
+	b := newFakeBridge(t)
+	var lines []string
+	var mu sync.Mutex
+	b.SetStderrHandler(func(line string) {
+		mu.Lock()
+		lines = append(lines, line)
+		mu.Unlock()
+	})
+
+	_, err := b.RunScript("x = 1\ny = x / 0", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "division by zero")
+	assert.Contains(t, err.Error(), "\ntraceback:\n  Traceback (most recent call last):")
+	assert.Contains(t, err.Error(), "ZeroDivisionError")
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Contains(lines, "Traceback (most recent call last):")
+	}, 5*time.Second, 10*time.Millisecond, "stderr routed to the handler")
+
+	// A primitive's error has no traceback.
+	b.RegisterPrimitive("fail", func(_ []any, _ map[string]any) (any, error) {
+		return nil, errors.New("no such file")
+	})
+	_, err = b.RunScript("fail()", []string{"fail"})
+	require.EqualError(t, err, "no such file")
+}
+
+func TestBridge_MaxScripts(t *testing.T) {
+	b := newFakeBridge(t, WithMaxScripts(2))
+	var mu sync.Mutex
+	running, peak := 0, 0
+	b.RegisterPrimitive("work", func(_ []any, _ map[string]any) (any, error) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return true, nil
+	})
+
+	var wg sync.WaitGroup
+	errs := make([]error, 6)
+	for i := range errs {
+		wg.Go(func() {
+			_, errs[i] = b.RunScript("work()", []string{"work"})
+		})
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, peak, "the limit is reached and never exceeded")
+}
+
+func TestBridge_MaxScripts_TimeoutKeepsSlot(t *testing.T) {
+	b := newFakeBridge(t, WithMaxScripts(1))
+	release := make(chan struct{})
+	b.RegisterPrimitive("block", func(_ []any, _ map[string]any) (any, error) {
+		<-release
+		return true, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := b.RunScriptContext(ctx, "block()", []string{"block"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The timed-out script is still running in the bridge.
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = b.RunScriptContext(ctx, "1 + 1", nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "waiting to run script")
+
+	close(release)
+	result, err := b.RunScript("1 + 1", nil)
+	require.NoError(t, err, "the slot frees once the bridge finishes")
+	assert.EqualValues(t, 2, result)
+}
+
+func TestBridge_MaxScripts_QueuedTimeout(t *testing.T) {
+	b := newFakeBridge(t, WithMaxScripts(1))
+	called := make(chan struct{})
+	release := make(chan struct{})
+	b.RegisterPrimitive("block", func(_ []any, _ map[string]any) (any, error) {
+		close(called)
+		<-release
+		return true, nil
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := b.RunScript("block()", []string{"block"})
+		done <- err
+	}()
+	<-called
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := b.RunScriptContext(ctx, "1 + 1", nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "waiting to run script")
+
+	close(release)
+	require.NoError(t, <-done)
+}
+
+func TestBridge_RunScriptContext_Deadline(t *testing.T) {
+	b := newFakeBridge(t)
+	release := make(chan struct{})
+	defer close(release)
+	b.RegisterPrimitive("block", func(_ []any, _ map[string]any) (any, error) {
+		<-release
+		return true, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := b.RunScriptContext(ctx, "block()", []string{"block"})
+	require.Error(t, err)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "timed out")
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Zero(t, b.pendingCount(), "pending entry removed")
+}
+
+func TestBridge_RunScriptContext_Cancel(t *testing.T) {
+	b := newFakeBridge(t)
+	called := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	b.RegisterPrimitive("block", func(_ []any, _ map[string]any) (any, error) {
+		close(called)
+		<-release
+		return true, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-called
+		cancel()
+	}()
+
+	_, err := b.RunScriptContext(ctx, "block()", []string{"block"})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Contains(t, err.Error(), "cancelled")
+	assert.Zero(t, b.pendingCount(), "pending entry removed")
+
+	// The bridge is still usable afterwards.
+	result, err := b.RunScript("1 + 1", nil)
+	require.NoError(t, err)
+	assert.InDelta(t, float64(2), result, 0.001)
+}
+
+func TestBridge_TimeoutLateResponseDropped(t *testing.T) {
+	b := newFakeBridge(t)
+	release := make(chan struct{})
+	b.RegisterPrimitive("block", func(_ []any, _ map[string]any) (any, error) {
+		<-release
+		return true, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := b.RunScriptContext(ctx, "block()", []string{"block"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, b.pendingCount())
+
+	// Let the abandoned script finish; its response must be discarded.
+	close(release)
+	result, err := b.RunScript("40 + 2", nil)
+	require.NoError(t, err)
+	assert.InDelta(t, float64(42), result, 0.001)
+	assert.Zero(t, b.pendingCount(), "pending map returns to empty")
+}
+
+func TestBridge_ProcessExitCleansPending(t *testing.T) {
+	b := newFakeBridge(t)
+	called := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	b.RegisterPrimitive("block", func(_ []any, _ map[string]any) (any, error) {
+		close(called)
+		<-release // the process dies first
+		return true, nil
+	})
+
+	go func() {
+		<-called
+		_ = b.cmd.Process.Kill()
+	}()
+
+	_, err := b.RunScript("block()", []string{"block"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exited unexpectedly")
+	assert.Zero(t, b.pendingCount())
+}
+
+func TestBridge_Restart(t *testing.T) {
+	b := newFakeBridge(t)
+	called := make(chan struct{})
+	b.RegisterPrimitive("block", func(_ []any, _ map[string]any) (any, error) {
+		close(called)
+		return true, nil
+	})
+	b.RegisterPrimitive("double", func(args []any, _ map[string]any) (any, error) {
+		return args[0].(float64) * 2, nil
+	})
+
+	go func() {
+		<-called
+		_ = b.cmd.Process.Kill()
+	}()
+	_, err := b.RunScript("block()\nwhile True: pass", []string{"block"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exited unexpectedly")
+
+	require.NoError(t, b.Restart())
+	result, err := b.RunScript("double(21)", []string{"double"})
+	require.NoError(t, err)
+	assert.InDelta(t, 42.0, result, 0)
+	assert.Zero(t, b.pendingCount())
+}
+
+func TestBridge_AutoRestartAfterExit(t *testing.T) {
+	b := newFakeBridge(t)
+	b.RegisterPrimitive("echo", func(args []any, _ map[string]any) (any, error) {
+		return args[0], nil
+	})
+
+	require.NoError(t, b.cmd.Process.Kill())
+	<-b.done
+
+	result, err := b.RunScript(`echo("back")`, []string{"echo"})
+	require.NoError(t, err)
+	assert.Equal(t, "back", result)
+}
+
+func TestBridge_LargeMultilinePayload(t *testing.T) {
+	b := newFakeBridge(t)
+	big := "line one\nline two\r\n\n" + strings.Repeat("x", 4<<20) + "\nContent-Length: 3\n\n{}"
+	b.RegisterPrimitive("big", func(_ []any, _ map[string]any) (any, error) {
+		return big, nil
+	})
+	var got string
+	b.RegisterPrimitive("echo", func(args []any, _ map[string]any) (any, error) {
+		got = args[0].(string)
+		return len(got), nil
+	})
+
+	result, err := b.RunScript("s = big()\necho(s)\ns", []string{"big", "echo"})
+	require.NoError(t, err)
+	assert.Equal(t, big, got, "primitive result reached the script intact")
+	assert.Equal(t, big, result, "script output reached Go intact")
+}
+
+func TestReadMessage(t *testing.T) {
+	body := `{"jsonrpc":"2.0","result":"a\nb","id":1}`
+	input := "Content-Length: " + strconv.Itoa(len(body)) + "\r\n\r\n" + body +
+		"\n" + `{"jsonrpc":"2.0","result":2,"id":2}` + "\n"
+	r := bufio.NewReader(strings.NewReader(input))
+
+	msg, err := readMessage(r)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(msg))
+
+	msg, err = readMessage(r)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","result":2,"id":2}`, string(msg), "legacy newline-delimited message")
+
+	_, err = readMessage(r)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestReadMessage_ShortBody(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("Content-Length: 10\r\n\r\n{}"))
+	_, err := readMessage(r)
+	require.Error(t, err)
+}
+
+func TestBridge_Metrics(t *testing.T) {
+	b := newFakeBridge(t)
+	b.RegisterPrimitive("tick", func(_ []any, _ map[string]any) (any, error) { return true, nil })
+	b.RegisterPrimitive("slow", func(_ []any, _ map[string]any) (any, error) {
+		time.Sleep(10 * time.Millisecond)
+		return true, nil
+	})
+
+	_, err := b.RunScript("for i in range(3):\n    tick()\nslow()", []string{"tick", "slow"})
+	require.NoError(t, err)
+
+	m := b.Metrics()
+	assert.Equal(t, 3, m["tick"].Calls)
+	assert.Equal(t, 1, m["slow"].Calls)
+	assert.GreaterOrEqual(t, m["slow"].Duration, 10*time.Millisecond)
+	assert.NotContains(t, m, "unused")
+
+	b.ResetMetrics()
+	assert.Empty(t, b.Metrics())
+}
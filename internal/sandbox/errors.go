@@ -0,0 +1,86 @@
+package sandbox
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/cleared-dev/cleared/internal/id"
+	"github.com/cleared-dev/cleared/internal/journal"
+	"github.com/cleared-dev/cleared/internal/queue"
+)
+
+// Error codes carried by PrimitiveError, in JSON-RPC's server-defined range.
+const (
+	CodePrimitiveFailed  = -32000 // untyped failure; plain errors use this
+	CodeNotFound         = -32002
+	CodeValidationFailed = -32003
+)
+
+// PrimitiveError is an error with a machine-readable code and structured
+// data. A primitive returning one has both surfaced in the JSON-RPC error,
+// and RunScript returns it when it aborts a script.
+type PrimitiveError struct {
+	Code    int
+	Message string
+	Data    map[string]any
+}
+
+func (e *PrimitiveError) Error() string { return e.Message }
+
+// toRPCError converts a primitive's error into a JSON-RPC error, keeping a
+// PrimitiveError's code and data.
+func toRPCError(err error) *RPCError {
+	rpcErr := &RPCError{Code: CodePrimitiveFailed, Message: err.Error()}
+	var pe *PrimitiveError
+	if errors.As(err, &pe) {
+		rpcErr.Code = pe.Code
+		if pe.Data != nil {
+			rpcErr.Data = pe.Data
+		}
+	}
+	return rpcErr
+}
+
+// classifyError converts known service errors into PrimitiveErrors so the
+// bridge can tell them apart. Other errors are returned unchanged.
+func classifyError(err error) error {
+	var pe *PrimitiveError
+	if errors.As(err, &pe) {
+		return err
+	}
+
+	var verrs journal.ValidationErrors
+	if errors.As(err, &verrs) {
+		entryIDs := []string{}
+		for _, ve := range verrs {
+			if g := id.EntryGroup(ve.EntryID); !slices.Contains(entryIDs, g) {
+				entryIDs = append(entryIDs, g)
+			}
+		}
+		return &PrimitiveError{
+			Code:    CodeValidationFailed,
+			Message: err.Error(),
+			Data:    map[string]any{"entry_ids": entryIDs, "violations": violationMaps(verrs), "soft": verrs.Soft()},
+		}
+	}
+
+	if errors.Is(err, journal.ErrEntryNotFound) || errors.Is(err, queue.ErrNotFound) {
+		return &PrimitiveError{Code: CodeNotFound, Message: err.Error()}
+	}
+	return err
+}
+
+// violationMaps converts validation errors into the maps scripts see, both
+// in a failed primitive's data and in journal_add_double's warnings.
+func violationMaps(verrs journal.ValidationErrors) []map[string]any {
+	violations := make([]map[string]any, len(verrs))
+	for i, ve := range verrs {
+		violations[i] = map[string]any{
+			"invariant":   ve.Invariant,
+			"entry_id":    ve.EntryID,
+			"description": ve.Description,
+			"soft":        ve.Soft,
+		}
+	}
+	return violations
+}
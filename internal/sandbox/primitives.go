@@ -1,10 +1,14 @@
 package sandbox
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -13,21 +17,36 @@ import (
 	"github.com/cleared-dev/cleared/internal/agentlog"
 	"github.com/cleared-dev/cleared/internal/config"
 	"github.com/cleared-dev/cleared/internal/gitops"
+	"github.com/cleared-dev/cleared/internal/id"
 	"github.com/cleared-dev/cleared/internal/importer"
 	"github.com/cleared-dev/cleared/internal/journal"
 	"github.com/cleared-dev/cleared/internal/model"
+	"github.com/cleared-dev/cleared/internal/queue"
+	"github.com/cleared-dev/cleared/internal/receipts"
+	"github.com/cleared-dev/cleared/internal/rules"
 )
 
 // Runtime holds references to all services and registers primitives on a Bridge.
 type Runtime struct {
-	repoRoot   string
-	cfg        *config.Config
-	accounts   *accounts.Service
-	journal    *journal.Service
-	agentLog   []agentlog.Entry
-	agentName  string
-	dryRun     bool
-	queueItems []map[string]any
+	repoRoot  string
+	cfg       *config.Config
+	accounts  *accounts.Service
+	journal   *journal.Service
+	logMu     sync.Mutex // guards agentLog, which bridge stderr also appends to
+	agentLog  []agentlog.Entry
+	logSink   func(agentlog.Entry)
+	agentName string
+	dryRun    bool
+	args      map[string]any
+	// touched lists the repo-relative paths written since the last
+	// git_commit, which commits only those.
+	touched []string
+	// booked lists the entries added since the last importer_mark_processed,
+	// which records them in the manifest as that file's entries.
+	booked []string
+	// committedLogs counts the agent log entries already stamped with the
+	// commit that contains their work.
+	committedLogs int
 }
 
 // NewRuntime loads config, accounts, and journal services from a repo root.
@@ -43,6 +62,13 @@ func NewRuntime(repoRoot, agentName string, dryRun bool) (*Runtime, error) {
 	}
 
 	jrnl := journal.NewService(repoRoot, accts)
+	jrnl.SetDryRun(dryRun)
+	jrnl.SetCurrency(cfg.Business.Currency)
+	closed, err := gitops.ClosedMonths(repoRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading closed months: %w", err)
+	}
+	jrnl.SetClosedMonths(closed)
 
 	return &Runtime{
 		repoRoot:  repoRoot,
@@ -56,26 +82,116 @@ func NewRuntime(repoRoot, agentName string, dryRun bool) (*Runtime, error) {
 
 // AgentLog returns the collected agent log entries.
 func (rt *Runtime) AgentLog() []agentlog.Entry {
-	return rt.agentLog
+	rt.logMu.Lock()
+	defer rt.logMu.Unlock()
+	return slices.Clone(rt.agentLog)
+}
+
+// SetLogSink registers fn to receive each agent log entry as it is
+// recorded, so callers can stream progress while a script runs. Entries are
+// still collected for AgentLog. With a sink set, ctx_log no longer echoes to
+// stderr itself.
+func (rt *Runtime) SetLogSink(fn func(agentlog.Entry)) {
+	rt.logSink = fn
+}
+
+// SetArgs sets the parameters ctx_args returns to the script, typically
+// parsed from the arguments after "--" on cleared agent run.
+func (rt *Runtime) SetArgs(args map[string]any) {
+	rt.args = args
 }
 
-// Register registers all primitives on the given bridge.
+// touch notes paths a primitive wrote, for the next git_commit.
+func (rt *Runtime) touch(paths ...string) {
+	for _, p := range paths {
+		if !slices.Contains(rt.touched, p) {
+			rt.touched = append(rt.touched, p)
+		}
+	}
+}
+
+// record appends an agent log entry and forwards it to the log sink.
+func (rt *Runtime) record(e agentlog.Entry) {
+	rt.logMu.Lock()
+	rt.agentLog = append(rt.agentLog, e)
+	rt.logMu.Unlock()
+	if rt.logSink != nil {
+		rt.logSink(e)
+	}
+}
+
+// Register registers all primitives on the given bridge. Registering
+// another Runtime on the same bridge replaces this one's handlers, so one
+// bridge can serve several agent runs in turn. Lines the bridge writes to
+// stderr are logged with action "bridge_stderr".
 func (rt *Runtime) Register(b *Bridge) {
-	b.RegisterPrimitive("importer_scan", rt.importerScan)
-	b.RegisterPrimitive("importer_parse", rt.importerParse)
-	b.RegisterPrimitive("importer_mark_processed", rt.importerMarkProcessed)
-	b.RegisterPrimitive("importer_deduplicate", rt.importerDeduplicate)
-	b.RegisterPrimitive("journal_add_double", rt.journalAddDouble)
-	b.RegisterPrimitive("journal_query", rt.journalQuery)
-	b.RegisterPrimitive("accounts_list", rt.accountsList)
-	b.RegisterPrimitive("accounts_get", rt.accountsGet)
-	b.RegisterPrimitive("accounts_exists", rt.accountsExists)
-	b.RegisterPrimitive("accounts_by_type", rt.accountsByType)
-	b.RegisterPrimitive("config_get", rt.configGet)
-	b.RegisterPrimitive("git_commit", rt.gitCommit)
-	b.RegisterPrimitive("ctx_log", rt.ctxLog)
-	b.RegisterPrimitive("queue_add_review", rt.queueAddReview)
-	b.RegisterPrimitive("ctx_dry_run", rt.ctxDryRun)
+	b.SetStderrHandler(func(line string) {
+		rt.record(agentlog.Entry{
+			Timestamp: time.Now().UTC(),
+			Agent:     rt.agentName,
+			Action:    "bridge_stderr",
+			Details:   line,
+		})
+	})
+	for name, h := range rt.Primitives() {
+		b.RegisterPrimitive(name, h)
+	}
+}
+
+// Primitives returns the handler for each primitive, keyed by name. Known
+// service errors are returned as PrimitiveErrors, and agent log entries a
+// primitive records are stamped with its duration.
+func (rt *Runtime) Primitives() map[string]PrimitiveHandler {
+	handlers := make(map[string]PrimitiveHandler)
+	register := func(name string, h PrimitiveHandler) {
+		handlers[name] = func(args []any, kwargs map[string]any) (any, error) {
+			rt.logMu.Lock()
+			start, logged := time.Now(), len(rt.agentLog)
+			rt.logMu.Unlock()
+			result, err := h(args, kwargs)
+			// Entries the call logged record how long it took.
+			elapsed := time.Since(start).Milliseconds()
+			rt.logMu.Lock()
+			for i := logged; i < len(rt.agentLog); i++ {
+				rt.agentLog[i].DurationMS = elapsed
+			}
+			rt.logMu.Unlock()
+			if err != nil {
+				return nil, classifyError(err)
+			}
+			return result, nil
+		}
+	}
+	register("importer_scan", rt.importerScan)
+	register("importer_parse", rt.importerParse)
+	register("importer_mark_processed", rt.importerMarkProcessed)
+	register("importer_unprocess", rt.importerUnprocess)
+	register("importer_source_file", rt.importerSourceFile)
+	register("importer_deduplicate", rt.importerDeduplicate)
+	register("journal_add_double", rt.journalAddDouble)
+	register("journal_add_split", rt.journalAddSplit)
+	register("journal_void", rt.journalVoid)
+	register("journal_correct", rt.journalCorrect)
+	register("journal_attach_receipt", rt.journalAttachReceipt)
+	register("journal_find_by_receipt", rt.journalFindByReceipt)
+	register("journal_query", rt.journalQuery)
+	register("accounts_list", rt.accountsList)
+	register("accounts_get", rt.accountsGet)
+	register("accounts_exists", rt.accountsExists)
+	register("accounts_by_type", rt.accountsByType)
+	register("accounts_search", rt.accountsSearch)
+	register("accounts_children", rt.accountsChildren)
+	register("rules_match", rt.rulesMatch)
+	register("rules_add", rt.rulesAdd)
+	register("config_get", rt.configGet)
+	register("git_commit", rt.gitCommit)
+	register("ctx_log", rt.ctxLog)
+	register("queue_add_review", rt.queueAddReview)
+	register("queue_list", rt.queueList)
+	register("queue_resolve", rt.queueResolve)
+	register("ctx_dry_run", rt.ctxDryRun)
+	register("ctx_args", rt.ctxArgs)
+	return handlers
 }
 
 // --- Importer primitives ---
@@ -99,27 +215,45 @@ func (rt *Runtime) importerScan(_ []any, _ map[string]any) (any, error) {
 	return result, nil
 }
 
-func (rt *Runtime) importerParse(args []any, _ map[string]any) (any, error) {
+func (rt *Runtime) importerParse(args []any, kwargs map[string]any) (any, error) {
 	if len(args) == 0 {
 		return nil, errors.New("importer_parse requires a filename argument")
 	}
 	fileName, _ := args[0].(string)
 
-	path := filepath.Join(rt.repoRoot, "import", fileName)
-	f, err := os.Open(path)
+	since, err := optionalDateArg(kwargs, "since")
+	if err != nil {
+		return nil, err
+	}
+	until, err := optionalDateArg(kwargs, "until")
 	if err != nil {
-		return nil, fmt.Errorf("open %s: %w", fileName, err)
+		return nil, err
 	}
-	defer f.Close()
 
-	parser := importer.DefaultRegistry().Get("chase")
-	if parser == nil {
-		return nil, errors.New("no parser for format chase")
+	path := filepath.Join(rt.repoRoot, "import", fileName)
+	reg, err := importer.DefaultRegistry(rt.cfg.ImportFormats...)
+	if err != nil {
+		return nil, err
 	}
-
-	txns, err := parser.Parse(f)
+	reg.SetRounding(importer.Rounding(rt.cfg.Import.Rounding))
+	txns, err := reg.ParseFile(path, rt.cfg.BankAccounts)
 	if err != nil {
-		return nil, fmt.Errorf("parsing %s: %w", fileName, err)
+		return nil, err
+	}
+	txns = importer.FilterDateRange(txns, since, until)
+
+	if boolArg(kwargs, "dedupe_within") {
+		var dups []importer.Duplicate
+		txns, dups = importer.DedupeWithin(txns)
+		for _, d := range dups {
+			rt.record(agentlog.Entry{
+				Timestamp: time.Now().UTC(),
+				Agent:     rt.agentName,
+				Action:    "import_duplicate",
+				Details: fmt.Sprintf("%s: dropped row %d duplicating row %d (%s)",
+					fileName, d.Index+1, d.FirstIndex+1, d.Transaction.Reference),
+			})
+		}
 	}
 
 	result := make([]map[string]any, len(txns))
@@ -129,23 +263,115 @@ func (rt *Runtime) importerParse(args []any, _ map[string]any) (any, error) {
 	return result, nil
 }
 
-func (rt *Runtime) importerMarkProcessed(args []any, _ map[string]any) (any, error) {
+// importerMarkProcessed moves a file to import/processed/ and records in the
+// manifest which entries it produced: entry_ids if given, otherwise every
+// entry booked since the previous importer_mark_processed call.
+func (rt *Runtime) importerMarkProcessed(args []any, kwargs map[string]any) (any, error) {
 	if len(args) == 0 {
 		return nil, errors.New("importer_mark_processed requires a filename argument")
 	}
 	fileName, _ := args[0].(string)
 
+	entryIDs := rt.booked
+	if items, ok := kwargs["entry_ids"].([]any); ok {
+		entryIDs = make([]string, 0, len(items))
+		for _, item := range items {
+			if s, ok := item.(string); ok {
+				entryIDs = append(entryIDs, s)
+			}
+		}
+	}
+	rt.booked = nil
+
+	if rt.dryRun {
+		if _, err := os.Stat(filepath.Join(rt.repoRoot, "import", fileName)); err != nil {
+			return nil, fmt.Errorf("moving %s to processed: %w", fileName, err)
+		}
+		rt.logDryRun("importer_mark_processed", fileName, "")
+		return map[string]any{"success": true, "dry_run": true}, nil
+	}
+
 	if err := importer.MarkProcessed(rt.repoRoot, fileName); err != nil {
 		return nil, err
 	}
+	if err := importer.RecordManifest(rt.repoRoot, fileName, entryIDs); err != nil {
+		return nil, err
+	}
+	rt.touch(importer.MarkedPaths(fileName)...)
+	if len(entryIDs) > 0 {
+		rt.touch(importer.ManifestFile)
+	}
+	return map[string]any{"success": true, "entry_count": len(entryIDs)}, nil
+}
+
+func (rt *Runtime) importerUnprocess(args []any, kwargs map[string]any) (any, error) {
+	fileName := stringArg(kwargs, "filename")
+	if fileName == "" && len(args) > 0 {
+		fileName, _ = args[0].(string)
+	}
+	if fileName == "" {
+		return nil, errors.New("importer_unprocess requires a filename argument")
+	}
+
+	if rt.dryRun {
+		if _, err := os.Stat(filepath.Join(rt.repoRoot, "import", "processed", fileName)); err != nil {
+			return nil, fmt.Errorf("moving %s back to import: %w", fileName, err)
+		}
+		rt.logDryRun("importer_unprocess", fileName, "")
+		return map[string]any{"success": true, "dry_run": true}, nil
+	}
+
+	if err := importer.Unprocess(rt.repoRoot, fileName); err != nil {
+		return nil, err
+	}
+	rt.touch(importer.MarkedPaths(fileName)...)
 	return map[string]any{"success": true}, nil
 }
 
+func (rt *Runtime) importerSourceFile(args []any, kwargs map[string]any) (any, error) {
+	entryID := stringArg(kwargs, "entry_id")
+	if entryID == "" && len(args) > 0 {
+		entryID, _ = args[0].(string)
+	}
+	if entryID == "" {
+		return nil, errors.New("importer_source_file requires an entry_id")
+	}
+	file, ok, err := importer.SourceFile(rt.repoRoot, entryID)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return file, nil
+}
+
 func (rt *Runtime) importerDeduplicate(args []any, _ map[string]any) (any, error) {
-	if len(args) > 0 {
-		return args[0], nil
+	if len(args) == 0 {
+		return []any{}, nil
+	}
+	items, ok := args[0].([]any)
+	if !ok {
+		return nil, fmt.Errorf("importer_deduplicate expects a list of transactions, got %T", args[0])
+	}
+
+	txns := make([]model.BankTransaction, len(items))
+	for i, item := range items {
+		m, _ := item.(map[string]any)
+		txn, err := transactionFromMap(m)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		txns[i] = txn
+	}
+
+	fresh, err := importer.Deduplicate(txns, rt.journal)
+	if err != nil {
+		return nil, err
 	}
-	return []any{}, nil
+
+	result := make([]map[string]any, len(fresh))
+	for i, txn := range fresh {
+		result[i] = transactionToMap(txn)
+	}
+	return result, nil
 }
 
 // --- Journal primitives ---
@@ -181,23 +407,268 @@ func (rt *Runtime) journalAddDouble(_ []any, kwargs map[string]any) (any, error)
 		Evidence:      stringArg(kwargs, "evidence"),
 		Tags:          stringArg(kwargs, "tags"),
 		Notes:         stringArg(kwargs, "notes"),
+		Currency:      stringArg(kwargs, "currency"),
+		SourceFile:    stringArg(kwargs, "source_file"),
 	}
 
 	entryID, err := rt.journal.AddDouble(params)
 	if err != nil {
 		return nil, err
 	}
+	rt.booked = append(rt.booked, entryID)
+	result := map[string]any{"entry_id": entryID, "success": true}
+	if warnings := rt.journal.SignWarnings(entryID, params); len(warnings) > 0 {
+		result["warnings"] = violationMaps(warnings)
+	}
+	if rt.dryRun {
+		rt.logDryRun("journal_add_double", fmt.Sprintf("%s %s -> %d/%d", params.Description,
+			params.Amount.StringFixed(2), params.DebitAccount, params.CreditAccount), entryID)
+		result["dry_run"] = true
+		return result, nil
+	}
+	rt.touch(journal.MonthFile(params.Date.Year(), int(params.Date.Month())))
+
+	return result, nil
+}
+
+func (rt *Runtime) journalAddSplit(_ []any, kwargs map[string]any) (any, error) {
+	date, err := parseDate(kwargs["date"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid date: %w", err)
+	}
+
+	debits, err := splitLegsArg(kwargs, "debits")
+	if err != nil {
+		return nil, err
+	}
+	credits, err := splitLegsArg(kwargs, "credits")
+	if err != nil {
+		return nil, err
+	}
+
+	confidence, _ := parseDecimal(kwargs["confidence"])
+
+	status, _ := kwargs["status"].(string)
+	if status == "" {
+		status = string(model.StatusPendingReview)
+	}
+
+	params := journal.SplitParams{
+		Date:         date,
+		Description:  stringArg(kwargs, "description"),
+		Debits:       debits,
+		Credits:      credits,
+		Counterparty: stringArg(kwargs, "counterparty"),
+		Reference:    stringArg(kwargs, "reference"),
+		Confidence:   confidence,
+		Status:       model.EntryStatus(status),
+		Evidence:     stringArg(kwargs, "evidence"),
+		Tags:         stringArg(kwargs, "tags"),
+		Notes:        stringArg(kwargs, "notes"),
+		Currency:     stringArg(kwargs, "currency"),
+		SourceFile:   stringArg(kwargs, "source_file"),
+	}
+
+	entryID, err := rt.journal.AddSplit(params)
+	if err != nil {
+		return nil, err
+	}
+	rt.booked = append(rt.booked, entryID)
+	if rt.dryRun {
+		rt.logDryRun("journal_add_split", fmt.Sprintf("%s, %d debit and %d credit legs",
+			params.Description, len(params.Debits), len(params.Credits)), entryID)
+		return map[string]any{"entry_id": entryID, "success": true, "dry_run": true}, nil
+	}
+	rt.touch(journal.MonthFile(params.Date.Year(), int(params.Date.Month())))
 
 	return map[string]any{"entry_id": entryID, "success": true}, nil
 }
 
+func (rt *Runtime) journalVoid(args []any, kwargs map[string]any) (any, error) {
+	entryID := stringArg(kwargs, "entry_id")
+	if entryID == "" && len(args) > 0 {
+		entryID, _ = args[0].(string)
+	}
+	reason := stringArg(kwargs, "reason")
+	if entryID == "" || reason == "" {
+		return nil, errors.New("journal_void requires entry_id and reason")
+	}
+
+	year, month, _, err := id.ParseEntryID(entryID)
+	if err != nil {
+		return nil, err
+	}
+	if err := rt.journal.VoidEntry(year, month, id.EntryGroup(entryID), reason, false); err != nil {
+		return nil, err
+	}
+	if rt.dryRun {
+		rt.logDryRun("journal_void", reason, id.EntryGroup(entryID))
+		return map[string]any{"success": true, "dry_run": true}, nil
+	}
+	rt.touch(journal.MonthFile(year, month))
+	return map[string]any{"success": true}, nil
+}
+
+func (rt *Runtime) journalAttachReceipt(args []any, kwargs map[string]any) (any, error) {
+	entryID := stringArg(kwargs, "entry_id")
+	if entryID == "" && len(args) > 0 {
+		entryID, _ = args[0].(string)
+	}
+	path := stringArg(kwargs, "path")
+	hash := stringArg(kwargs, "sha256")
+	if entryID == "" || (path == "" && hash == "") {
+		return nil, errors.New("journal_attach_receipt requires entry_id and a path or sha256")
+	}
+	year, month, _, err := id.ParseEntryID(entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if path != "" {
+		if path, err = receiptSource(rt.repoRoot, path); err != nil {
+			return nil, err
+		}
+		if rt.dryRun {
+			hash, err = receipts.Hash(path)
+		} else {
+			hash, err = receipts.Store(rt.repoRoot, path)
+		}
+		if err != nil {
+			return nil, err
+		}
+	} else if _, err := receipts.Path(rt.repoRoot, hash); err != nil {
+		return nil, err
+	}
+	if err := rt.journal.AttachReceipt(id.EntryGroup(entryID), hash); err != nil {
+		return nil, err
+	}
+	if rt.dryRun {
+		rt.logDryRun("journal_attach_receipt", hash, id.EntryGroup(entryID))
+		return map[string]any{"success": true, "dry_run": true, "receipt_hash": hash}, nil
+	}
+	rt.touch(journal.MonthFile(year, month))
+	return map[string]any{"success": true, "receipt_hash": hash}, nil
+}
+
+// receiptSourceDirs are the directories, relative to the repo root, that
+// journal_attach_receipt reads receipt files from.
+var receiptSourceDirs = []string{"import", receipts.Dir}
+
+// receiptSource resolves p, relative to the repo root, to a file under one
+// of receiptSourceDirs, so a script cannot copy other host files into the
+// repo. Symlinks are followed before the check.
+func receiptSource(repoRoot, p string) (string, error) {
+	invalid := fmt.Errorf("invalid receipt path %q: want a file under import/ or receipts/", p)
+	if !filepath.IsLocal(p) {
+		return "", invalid
+	}
+	clean := filepath.Clean(p)
+	dir, _, ok := strings.Cut(filepath.ToSlash(clean), "/")
+	if !ok || !slices.Contains(receiptSourceDirs, dir) {
+		return "", invalid
+	}
+
+	full := filepath.Join(repoRoot, clean)
+	real, err := filepath.EvalSymlinks(full)
+	if err != nil {
+		return "", fmt.Errorf("reading receipt: %w", err)
+	}
+	base, err := filepath.EvalSymlinks(filepath.Join(repoRoot, dir))
+	if err != nil {
+		return "", fmt.Errorf("reading receipt: %w", err)
+	}
+	if rel, err := filepath.Rel(base, real); err != nil || !filepath.IsLocal(rel) {
+		return "", invalid
+	}
+	return full, nil
+}
+
+func (rt *Runtime) journalFindByReceipt(args []any, kwargs map[string]any) (any, error) {
+	hash := stringArg(kwargs, "sha256")
+	if hash == "" && len(args) > 0 {
+		hash, _ = args[0].(string)
+	}
+	legs, err := rt.journal.FindByReceipt(hash)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]any, len(legs))
+	for i, leg := range legs {
+		result[i] = legToMap(leg)
+	}
+	return result, nil
+}
+
+func (rt *Runtime) journalCorrect(_ []any, kwargs map[string]any) (any, error) {
+	entryID := id.EntryGroup(stringArg(kwargs, "entry_id"))
+	if entryID == "" {
+		return nil, errors.New("journal_correct requires entry_id")
+	}
+	year, month, _, err := id.ParseEntryID(entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := parseDate(kwargs["date"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid date: %w", err)
+	}
+	amount, err := parseDecimal(kwargs["amount"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount: %w", err)
+	}
+	confidence, _ := parseDecimal(kwargs["confidence"])
+
+	params := journal.AddDoubleParams{
+		Date:          date,
+		Description:   stringArg(kwargs, "description"),
+		DebitAccount:  intArg(kwargs, "debit_account"),
+		CreditAccount: intArg(kwargs, "credit_account"),
+		Amount:        amount,
+		Counterparty:  stringArg(kwargs, "counterparty"),
+		Reference:     stringArg(kwargs, "reference"),
+		Confidence:    confidence,
+		Evidence:      stringArg(kwargs, "evidence"),
+		Tags:          stringArg(kwargs, "tags"),
+		Notes:         stringArg(kwargs, "notes"),
+		Currency:      stringArg(kwargs, "currency"),
+	}
+	if err := rt.journal.CorrectEntry(year, month, entryID, params); err != nil {
+		return nil, err
+	}
+	if rt.dryRun {
+		rt.logDryRun("journal_correct", params.Description, entryID)
+		return map[string]any{"success": true, "dry_run": true}, nil
+	}
+	rt.touch(journal.MonthFile(year, month))
+	return map[string]any{"success": true}, nil
+}
+
 func (rt *Runtime) journalQuery(_ []any, kwargs map[string]any) (any, error) {
-	now := time.Now()
-	year := intArgDefault(kwargs, "year", now.Year())
-	month := intArgDefault(kwargs, "month", int(now.Month()))
 	statusFilter := stringArg(kwargs, "status")
+	accountFilter := intArg(kwargs, "account_id")
+
+	start, err := optionalDateArg(kwargs, "start_date")
+	if err != nil {
+		return nil, err
+	}
+	end, err := optionalDateArg(kwargs, "end_date")
+	if err != nil {
+		return nil, err
+	}
 
-	legs, err := rt.journal.ReadMonth(year, month)
+	var legs []model.Leg
+	if !start.IsZero() || !end.IsZero() {
+		if start.IsZero() || end.IsZero() {
+			return nil, errors.New("journal_query needs both start_date and end_date")
+		}
+		legs, err = rt.journal.ReadRange(start, end)
+	} else {
+		now := time.Now()
+		year := intArgDefault(kwargs, "year", now.Year())
+		month := intArgDefault(kwargs, "month", int(now.Month()))
+		legs, err = rt.journal.ReadMonth(year, month)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -207,6 +678,9 @@ func (rt *Runtime) journalQuery(_ []any, kwargs map[string]any) (any, error) {
 		if statusFilter != "" && string(leg.Status) != statusFilter {
 			continue
 		}
+		if accountFilter != 0 && leg.AccountID != accountFilter {
+			continue
+		}
 		result = append(result, legToMap(leg))
 	}
 	if result == nil {
@@ -218,12 +692,7 @@ func (rt *Runtime) journalQuery(_ []any, kwargs map[string]any) (any, error) {
 // --- Accounts primitives ---
 
 func (rt *Runtime) accountsList(_ []any, _ map[string]any) (any, error) {
-	accts := rt.accounts.All()
-	result := make([]map[string]any, len(accts))
-	for i, a := range accts {
-		result[i] = accountToMap(a)
-	}
-	return result, nil
+	return accountsToList(rt.accounts.All()), nil
 }
 
 func (rt *Runtime) accountsGet(args []any, _ map[string]any) (any, error) {
@@ -253,12 +722,88 @@ func (rt *Runtime) accountsByType(args []any, _ map[string]any) (any, error) {
 	}
 	typeName, _ := args[0].(string)
 
-	accts := rt.accounts.ByType(model.AccountType(typeName))
-	result := make([]map[string]any, len(accts))
-	for i, a := range accts {
-		result[i] = accountToMap(a)
+	return accountsToList(rt.accounts.ByType(model.AccountType(typeName))), nil
+}
+
+// accountsSearch returns postable accounts whose name contains the query,
+// as candidates for categorizing a transaction.
+func (rt *Runtime) accountsSearch(args []any, kwargs map[string]any) (any, error) {
+	query := stringArg(kwargs, "query")
+	if query == "" && len(args) > 0 {
+		query, _ = args[0].(string)
 	}
-	return result, nil
+	if query == "" {
+		return nil, errors.New("accounts_search requires a query")
+	}
+	return accountsToList(rt.accounts.Search(query)), nil
+}
+
+// accountsChildren returns the accounts under parent_id: its direct
+// children, or with recursive=True every descendant, depth-first.
+func (rt *Runtime) accountsChildren(args []any, kwargs map[string]any) (any, error) {
+	parent := intArg(kwargs, "parent_id")
+	if parent == 0 && len(args) > 0 {
+		parent = toInt(args[0])
+	}
+	if !rt.accounts.Exists(parent) {
+		return nil, fmt.Errorf("unknown account %d", parent)
+	}
+	if boolArg(kwargs, "recursive") {
+		return accountsToList(rt.accounts.Subtree(parent)[1:]), nil
+	}
+	return accountsToList(rt.accounts.Children(parent)), nil
+}
+
+// --- Rules primitives ---
+
+func (rt *Runtime) rulesMatch(args []any, kwargs map[string]any) (any, error) {
+	desc := stringArg(kwargs, "description")
+	if desc == "" && len(args) > 0 {
+		desc, _ = args[0].(string)
+	}
+	if desc == "" {
+		return nil, errors.New("rules_match requires a description")
+	}
+
+	all, err := rules.Load(rt.repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := rules.Match(all, desc)
+	if !ok {
+		return nil, nil //nolint:nilnil // nil tells the script there was no match
+	}
+	return ruleToMap(r), nil
+}
+
+func (rt *Runtime) rulesAdd(_ []any, kwargs map[string]any) (any, error) {
+	pattern := stringArg(kwargs, "vendor_pattern")
+	if pattern == "" {
+		return nil, errors.New("rules_add requires vendor_pattern")
+	}
+	accountID := intArg(kwargs, "account_id")
+	if !rt.accounts.Exists(accountID) {
+		return nil, fmt.Errorf("rules_add: unknown account %d", accountID)
+	}
+	confidence, _ := kwargs["confidence"].(float64)
+
+	if rt.dryRun {
+		rt.logDryRun("rules_add", fmt.Sprintf("%s -> %d", pattern, accountID), "")
+		return map[string]any{"success": true, "replaced": false, "dry_run": true}, nil
+	}
+
+	replaced, err := rules.AppendRule(rt.repoRoot, rules.Rule{
+		VendorPattern: pattern,
+		VendorName:    stringArg(kwargs, "vendor_name"),
+		AccountID:     accountID,
+		Confidence:    confidence,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rt.touch(rules.File)
+	return map[string]any{"success": true, "replaced": replaced}, nil
 }
 
 // --- Config primitive ---
@@ -268,7 +813,7 @@ func (rt *Runtime) configGet(args []any, _ map[string]any) (any, error) {
 		return nil, errors.New("config_get requires a key argument")
 	}
 	key, _ := args[0].(string)
-	return configLookup(rt.cfg, key), nil
+	return rt.cfg.Get(key), nil
 }
 
 // --- Git primitive ---
@@ -278,18 +823,50 @@ func (rt *Runtime) gitCommit(args []any, _ map[string]any) (any, error) {
 		return nil, errors.New("git_commit requires a message argument")
 	}
 	message, _ := args[0].(string)
+	message, err := gitops.NormalizeCommitMessage(message, rt.cfg.Git.CommitPrefixes, rt.agentName)
+	if err != nil {
+		return nil, err
+	}
 
-	hash, err := gitops.CommitAll(
+	if rt.dryRun {
+		rt.logDryRun("git_commit", message, "")
+		return map[string]any{"commit_hash": "", "success": true, "dry_run": true}, nil
+	}
+
+	hash, err := gitops.CommitFull(
 		rt.repoRoot,
+		rt.touched,
 		message,
 		rt.cfg.Git.AuthorName,
 		rt.cfg.Git.AuthorEmail,
 	)
+	if errors.Is(err, gitops.ErrNothingToCommit) {
+		return map[string]any{"commit_hash": "", "success": true, "nothing_to_commit": true}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
+	rt.touched = nil
+
+	logged := gitops.ShortHash(hash)
+	if rt.cfg.Git.FullHashes {
+		logged = hash
+	}
+	rt.record(agentlog.Entry{
+		Timestamp: time.Now().UTC(),
+		Agent:     rt.agentName,
+		Action:    "git_commit",
+		Details:   message,
+	})
+	// Everything logged since the last commit led up to this one.
+	rt.logMu.Lock()
+	for i := rt.committedLogs; i < len(rt.agentLog); i++ {
+		rt.agentLog[i].CommitHash = logged
+	}
+	rt.committedLogs = len(rt.agentLog)
+	rt.logMu.Unlock()
 
-	return map[string]any{"commit_hash": hash, "success": true}, nil
+	return map[string]any{"commit_hash": gitops.ShortHash(hash), "commit_hash_full": hash, "success": true}, nil
 }
 
 // --- Context primitives ---
@@ -300,29 +877,128 @@ func (rt *Runtime) ctxLog(args []any, _ map[string]any) (any, error) {
 		message, _ = args[0].(string)
 	}
 
-	rt.agentLog = append(rt.agentLog, agentlog.Entry{
+	rt.record(agentlog.Entry{
 		Timestamp: time.Now().UTC(),
 		Agent:     rt.agentName,
 		Action:    "log",
 		Details:   message,
 	})
 
-	fmt.Fprintf(os.Stderr, "  [%s] %s\n", rt.agentName, message)
+	if rt.logSink == nil {
+		fmt.Fprintf(os.Stderr, "  [%s] %s\n", rt.agentName, message)
+	}
 	return true, nil
 }
 
 func (rt *Runtime) queueAddReview(_ []any, kwargs map[string]any) (any, error) {
-	rt.queueItems = append(rt.queueItems, kwargs)
-	return map[string]any{
-		"item_id": fmt.Sprintf("q%03d", len(rt.queueItems)),
-		"success": true,
-	}, nil
+	confidence, err := parseDecimal(kwargs["confidence"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid confidence: %w", err)
+	}
+	conf, _ := confidence.Float64()
+	item := queue.Item{
+		ReviewItem: model.ReviewItem{
+			EntryID: stringArg(kwargs, "entry_id"),
+			// description is the name scripts written before reason used.
+			Reason:           cmp.Or(stringArg(kwargs, "reason"), stringArg(kwargs, "description")),
+			SuggestedAccount: intArg(kwargs, "suggested_account"),
+			Confidence:       conf,
+		},
+		Agent: rt.agentName,
+	}
+	if item.Reason == "" {
+		return nil, errors.New("queue_add_review requires a reason")
+	}
+
+	if rt.dryRun {
+		items, err := queue.Load(rt.repoRoot)
+		if err != nil {
+			return nil, err
+		}
+		rt.logDryRun("queue_add_review", item.Reason, item.EntryID)
+		return map[string]any{"item_id": queue.NextID(items), "success": true, "dry_run": true}, nil
+	}
+
+	item, err = queue.Add(rt.repoRoot, item)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"item_id": item.ID, "success": true}, nil
+}
+
+func (rt *Runtime) queueList(_ []any, kwargs map[string]any) (any, error) {
+	items, err := queue.Load(rt.repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	all := boolArg(kwargs, "include_resolved")
+	result := []queueListItem{}
+	for _, it := range items {
+		if all || it.Pending() {
+			result = append(result, queueListItem{Item: it, ItemID: it.ID})
+		}
+	}
+	return result, nil
+}
+
+// queueListItem is a queue item as queue_list returns it. ItemID repeats ID
+// under the key queue_add_review returns and queue_resolve takes, which
+// scripts read before the items were stored as model.ReviewItem.
+type queueListItem struct {
+	queue.Item
+	ItemID string `json:"item_id"`
+}
+
+func (rt *Runtime) queueResolve(args []any, kwargs map[string]any) (any, error) {
+	itemID := stringArg(kwargs, "item_id")
+	if itemID == "" && len(args) > 0 {
+		itemID, _ = args[0].(string)
+	}
+	resolution := stringArg(kwargs, "resolution")
+	if itemID == "" || resolution == "" {
+		return nil, errors.New("queue_resolve requires item_id and resolution")
+	}
+
+	if rt.dryRun {
+		items, err := queue.Load(rt.repoRoot)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.ContainsFunc(items, func(it queue.Item) bool { return it.ID == itemID && it.Pending() }) {
+			return nil, fmt.Errorf("%s: %w", itemID, queue.ErrNotFound)
+		}
+		rt.logDryRun("queue_resolve", resolution, itemID)
+		return map[string]any{"success": true, "dry_run": true}, nil
+	}
+	if _, err := queue.Resolve(rt.repoRoot, itemID, resolution); err != nil {
+		return nil, err
+	}
+	return map[string]any{"success": true}, nil
 }
 
 func (rt *Runtime) ctxDryRun(_ []any, _ map[string]any) (any, error) {
 	return rt.dryRun, nil
 }
 
+func (rt *Runtime) ctxArgs(_ []any, _ map[string]any) (any, error) {
+	if rt.args == nil {
+		return map[string]any{}, nil
+	}
+	return rt.args, nil
+}
+
+// logDryRun records a mutation that was skipped because of --dry-run, so the
+// agent log shows what the run would have done.
+func (rt *Runtime) logDryRun(action, details, entryID string) {
+	rt.record(agentlog.Entry{
+		Timestamp: time.Now().UTC(),
+		Agent:     rt.agentName,
+		Action:    "dry_run:" + action,
+		Details:   details,
+		EntryID:   entryID,
+	})
+}
+
 // --- Type conversion helpers ---
 
 func parseDate(v any) (time.Time, error) {
@@ -337,6 +1013,20 @@ func parseDate(v any) (time.Time, error) {
 	return t, nil
 }
 
+// optionalDateArg parses kwargs[key] as a date, returning the zero time when
+// the key is absent.
+func optionalDateArg(kwargs map[string]any, key string) (time.Time, error) {
+	v, ok := kwargs[key]
+	if !ok || v == nil {
+		return time.Time{}, nil
+	}
+	t, err := parseDate(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return t, nil
+}
+
 func parseDecimal(v any) (decimal.Decimal, error) {
 	switch n := v.(type) {
 	case float64:
@@ -350,27 +1040,12 @@ func parseDecimal(v any) (decimal.Decimal, error) {
 	}
 }
 
-func configLookup(cfg *config.Config, path string) any {
-	switch path {
-	case "business.name":
-		return cfg.Business.Name
-	case "business.entity_type":
-		return cfg.Business.EntityType
-	case "fiscal.year_start":
-		return cfg.Fiscal.YearStart
-	case "thresholds.auto_confirm":
-		return cfg.Thresholds.AutoConfirm
-	case "thresholds.review_flag":
-		return cfg.Thresholds.ReviewFlag
-	case "git.auto_commit":
-		return cfg.Git.AutoCommit
-	case "git.author_name":
-		return cfg.Git.AuthorName
-	case "git.author_email":
-		return cfg.Git.AuthorEmail
-	default:
-		return nil
+func accountsToList(accts []model.Account) []map[string]any {
+	result := make([]map[string]any, len(accts))
+	for i, a := range accts {
+		result[i] = accountToMap(a)
 	}
+	return result
 }
 
 func accountToMap(a model.Account) map[string]any {
@@ -388,23 +1063,66 @@ func accountToMap(a model.Account) map[string]any {
 	if a.Description != "" {
 		m["description"] = a.Description
 	}
+	if a.Archived {
+		m["archived"] = true
+	}
 	return m
 }
 
+func ruleToMap(r rules.Rule) map[string]any {
+	return map[string]any{
+		"pattern":     r.VendorPattern,
+		"vendor_name": r.VendorName,
+		"account_id":  r.AccountID,
+		"confidence":  r.Confidence,
+		"source":      r.Source,
+	}
+}
+
 func transactionToMap(txn model.BankTransaction) map[string]any {
 	amount, _ := txn.Amount.Float64()
-	return map[string]any{
+	m := map[string]any{
 		"date":        txn.Date.Format("2006-01-02"),
 		"description": txn.Description,
 		"amount":      amount,
 		"reference":   txn.Reference,
 	}
+	if txn.BankAccountID != 0 {
+		m["bank_account_id"] = txn.BankAccountID
+	}
+	if txn.Notes != "" {
+		m["notes"] = txn.Notes
+	}
+	return m
+}
+
+func transactionFromMap(m map[string]any) (model.BankTransaction, error) {
+	date, err := parseDate(m["date"])
+	if err != nil {
+		return model.BankTransaction{}, fmt.Errorf("invalid date: %w", err)
+	}
+	amount, err := parseDecimal(m["amount"])
+	if err != nil {
+		return model.BankTransaction{}, fmt.Errorf("invalid amount: %w", err)
+	}
+	return model.BankTransaction{
+		Date:          date,
+		Description:   stringArg(m, "description"),
+		Amount:        amount,
+		Reference:     stringArg(m, "reference"),
+		BankAccountID: intArg(m, "bank_account_id"),
+		Notes:         stringArg(m, "notes"),
+	}, nil
 }
 
 func legToMap(leg model.Leg) map[string]any {
 	debit, _ := leg.Debit.Float64()
 	credit, _ := leg.Credit.Float64()
 	conf, _ := leg.Confidence.Float64()
+	createdAt := ""
+	if !leg.CreatedAt.IsZero() {
+		createdAt = leg.CreatedAt.Format(time.RFC3339)
+	}
 	return map[string]any{
 		"entry_id":     leg.EntryID,
 		"date":         leg.Date.Format("2006-01-02"),
@@ -419,14 +1137,45 @@ func legToMap(leg model.Leg) map[string]any {
 		"evidence":     leg.Evidence,
 		"tags":         leg.Tags,
 		"notes":        leg.Notes,
+		"currency":     leg.Currency,
+		"receipt_hash": leg.ReceiptHash,
+		"created_at":   createdAt,
+		"source_file":  leg.SourceFile,
 	}
 }
 
+// splitLegsArg converts a list of {"account_id": ..., "amount": ...} dicts
+// into split legs.
+func splitLegsArg(m map[string]any, key string) ([]journal.SplitLeg, error) {
+	items, ok := m[key].([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list of {account_id, amount}", key)
+	}
+	legs := make([]journal.SplitLeg, len(items))
+	for i, item := range items {
+		leg, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s[%d]: expected a dict", key, i)
+		}
+		amount, err := parseDecimal(leg["amount"])
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: invalid amount: %w", key, i, err)
+		}
+		legs[i] = journal.SplitLeg{AccountID: intArg(leg, "account_id"), Amount: amount}
+	}
+	return legs, nil
+}
+
 func stringArg(m map[string]any, key string) string {
 	v, _ := m[key].(string)
 	return v
 }
 
+func boolArg(m map[string]any, key string) bool {
+	v, _ := m[key].(bool)
+	return v
+}
+
 func intArg(m map[string]any, key string) int {
 	return toInt(m[key])
 }
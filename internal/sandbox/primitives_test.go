@@ -8,8 +8,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/cleared-dev/cleared/internal/config"
 	"github.com/cleared-dev/cleared/internal/model"
+	"github.com/cleared-dev/cleared/internal/rules"
 )
 
 func TestParseDate(t *testing.T) {
@@ -35,6 +35,24 @@ func TestParseDate(t *testing.T) {
 	}
 }
 
+func TestOptionalDateArg(t *testing.T) {
+	got, err := optionalDateArg(map[string]any{"since": "2025-01-05"}, "since")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), got)
+
+	got, err = optionalDateArg(map[string]any{}, "since")
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+
+	got, err = optionalDateArg(nil, "until")
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+
+	_, err = optionalDateArg(map[string]any{"until": "bad"}, "until")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid until")
+}
+
 func TestParseDecimal(t *testing.T) {
 	tests := []struct {
 		input    any
@@ -60,47 +78,6 @@ func TestParseDecimal(t *testing.T) {
 	}
 }
 
-func TestConfigLookup(t *testing.T) {
-	cfg := &config.Config{
-		Business: config.BusinessConfig{
-			Name:       "Test Corp",
-			EntityType: "llc_single_member",
-		},
-		Fiscal: config.FiscalConfig{
-			YearStart: "01-01",
-		},
-		Thresholds: config.ThresholdsConfig{
-			AutoConfirm: 0.95,
-			ReviewFlag:  0.70,
-		},
-		Git: config.GitConfig{
-			AutoCommit:  true,
-			AuthorName:  "Cleared Agent",
-			AuthorEmail: "agent@cleared.dev",
-		},
-	}
-
-	tests := []struct {
-		path     string
-		expected any
-	}{
-		{"business.name", "Test Corp"},
-		{"business.entity_type", "llc_single_member"},
-		{"fiscal.year_start", "01-01"},
-		{"thresholds.auto_confirm", 0.95},
-		{"thresholds.review_flag", 0.70},
-		{"git.auto_commit", true},
-		{"git.author_name", "Cleared Agent"},
-		{"git.author_email", "agent@cleared.dev"},
-		{"nonexistent.path", nil},
-		{"", nil},
-	}
-	for _, tc := range tests {
-		result := configLookup(cfg, tc.path)
-		assert.Equal(t, tc.expected, result, "path: %s", tc.path)
-	}
-}
-
 func TestAccountToMap(t *testing.T) {
 	acct := model.Account{
 		ID:          1010,
@@ -130,6 +107,21 @@ func TestAccountToMap_WithParent(t *testing.T) {
 	assert.Equal(t, 5000, m["parent_id"])
 }
 
+func TestRuleToMap(t *testing.T) {
+	m := ruleToMap(rules.Rule{
+		VendorPattern: "GITHUB*",
+		VendorName:    "GitHub",
+		AccountID:     5020,
+		Confidence:    0.98,
+		Source:        "seed",
+	})
+	assert.Equal(t, "GITHUB*", m["pattern"])
+	assert.Equal(t, "GitHub", m["vendor_name"])
+	assert.Equal(t, 5020, m["account_id"])
+	assert.InDelta(t, 0.98, m["confidence"], 0.001)
+	assert.Equal(t, "seed", m["source"])
+}
+
 func TestTransactionToMap(t *testing.T) {
 	txn := model.BankTransaction{
 		Date:        time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
@@ -143,6 +135,30 @@ func TestTransactionToMap(t *testing.T) {
 	assert.Equal(t, "GITHUB *PRO", m["description"])
 	assert.InDelta(t, -4.0, m["amount"], 0.001)
 	assert.Equal(t, "chase_20250103_GITHUBPRO", m["reference"])
+	_, hasBank := m["bank_account_id"]
+	assert.False(t, hasBank, "bank_account_id should be omitted when 0")
+
+	txn.BankAccountID = 1010
+	assert.Equal(t, 1010, transactionToMap(txn)["bank_account_id"])
+}
+
+func TestTransactionFromMap(t *testing.T) {
+	m := map[string]any{
+		"date":        "2025-01-03",
+		"description": "GITHUB *PRO",
+		"amount":      float64(-4),
+		"reference":   "chase_20250103_GITHUBPRO",
+	}
+
+	txn, err := transactionFromMap(m)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), txn.Date)
+	assert.Equal(t, "GITHUB *PRO", txn.Description)
+	assert.True(t, txn.Amount.Equal(decimal.NewFromInt(-4)))
+	assert.Equal(t, "chase_20250103_GITHUBPRO", txn.Reference)
+
+	_, err = transactionFromMap(map[string]any{"amount": float64(1)})
+	assert.Error(t, err, "missing date")
 }
 
 func TestStringArg(t *testing.T) {
@@ -152,9 +168,38 @@ func TestStringArg(t *testing.T) {
 	assert.Empty(t, stringArg(m, "missing"))
 }
 
+func TestBoolArg(t *testing.T) {
+	m := map[string]any{"yes": true, "no": false, "str": "true"}
+	assert.True(t, boolArg(m, "yes"))
+	assert.False(t, boolArg(m, "no"))
+	assert.False(t, boolArg(m, "str"))
+	assert.False(t, boolArg(m, "missing"))
+}
+
 func TestIntArg(t *testing.T) {
 	m := map[string]any{"id": float64(1010), "name": "test"}
 	assert.Equal(t, 1010, intArg(m, "id"))
 	assert.Equal(t, 0, intArg(m, "name"))
 	assert.Equal(t, 0, intArg(m, "missing"))
 }
+
+func TestSplitLegsArg(t *testing.T) {
+	m := map[string]any{
+		"debits": []any{
+			map[string]any{"account_id": float64(5030), "amount": "45.00"},
+			map[string]any{"account_id": float64(5020), "amount": float64(55)},
+		},
+		"bad": []any{"5020"},
+	}
+
+	legs, err := splitLegsArg(m, "debits")
+	require.NoError(t, err)
+	require.Len(t, legs, 2)
+	assert.Equal(t, 5030, legs[0].AccountID)
+	assert.True(t, legs[1].Amount.Equal(decimal.NewFromInt(55)))
+
+	_, err = splitLegsArg(m, "bad")
+	require.Error(t, err)
+	_, err = splitLegsArg(m, "missing")
+	require.Error(t, err)
+}
@@ -0,0 +1,639 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cleared-dev/cleared/internal/accounts"
+	"github.com/cleared-dev/cleared/internal/agentlog"
+	"github.com/cleared-dev/cleared/internal/config"
+	"github.com/cleared-dev/cleared/internal/gitops"
+	"github.com/cleared-dev/cleared/internal/importer"
+	"github.com/cleared-dev/cleared/internal/journal"
+	"github.com/cleared-dev/cleared/internal/model"
+	"github.com/cleared-dev/cleared/internal/receipts"
+)
+
+// newTestRepo writes a minimal repo (config + default chart) and returns its root.
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, config.Save(filepath.Join(dir, "cleared.yaml"), config.Default("Test Corp", "llc_single_member")))
+	require.NoError(t, accounts.NewService(accounts.DefaultChart("llc_single_member")).Save(dir))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "import"), 0o755))
+	return dir
+}
+
+func TestRuntime_DryRun(t *testing.T) {
+	dir := newTestRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "import", "chase.csv"), []byte("x"), 0o644))
+
+	rt, err := NewRuntime(dir, "ingest", true)
+	require.NoError(t, err)
+
+	res, err := rt.journalAddDouble(nil, map[string]any{
+		"date":           "2025-01-03",
+		"description":    "GitHub",
+		"debit_account":  float64(5020),
+		"credit_account": float64(1010),
+		"amount":         "4.00",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01-0001", res.(map[string]any)["entry_id"])
+
+	res, err = rt.journalAddDouble(nil, map[string]any{
+		"date":           "2025-01-05",
+		"description":    "AWS",
+		"debit_account":  float64(5020),
+		"credit_account": float64(1010),
+		"amount":         "127.50",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01-0002", res.(map[string]any)["entry_id"], "IDs advance as in a real run")
+
+	_, err = rt.importerMarkProcessed([]any{"chase.csv"}, nil)
+	require.NoError(t, err)
+	_, err = rt.importerMarkProcessed([]any{"missing.csv"}, nil)
+	require.Error(t, err, "still reports files that could not be moved")
+
+	res, err = rt.gitCommit([]any{"import: 2 transactions"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, true, res.(map[string]any)["dry_run"])
+
+	// Nothing on disk changed.
+	_, err = os.Stat(filepath.Join(dir, "2025"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+	_, err = os.Stat(filepath.Join(dir, "import", "chase.csv"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, ".git"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	// The agent log records what would have happened.
+	var actions []string
+	for _, e := range rt.AgentLog() {
+		actions = append(actions, e.Action)
+	}
+	assert.Equal(t, []string{
+		"dry_run:journal_add_double",
+		"dry_run:journal_add_double",
+		"dry_run:importer_mark_processed",
+		"dry_run:git_commit",
+	}, actions)
+	assert.True(t, strings.HasPrefix(rt.AgentLog()[1].Details, "AWS 127.50"))
+}
+
+func TestRuntime_JournalQueryFilters(t *testing.T) {
+	dir := newTestRepo(t)
+	rt, err := NewRuntime(dir, "test", false)
+	require.NoError(t, err)
+
+	for _, e := range []map[string]any{
+		{"debit_account": float64(5020), "credit_account": float64(1010), "status": "auto-confirmed"},
+		{"debit_account": float64(5030), "credit_account": float64(1010), "status": "pending-review"},
+		{"debit_account": float64(5020), "credit_account": float64(1010), "status": "pending-review"},
+	} {
+		e["date"] = "2025-01-10"
+		e["description"] = "entry"
+		e["amount"] = "10.00"
+		_, err := rt.journalAddDouble(nil, e)
+		require.NoError(t, err)
+	}
+
+	query := func(kwargs map[string]any) []map[string]any {
+		t.Helper()
+		kwargs["year"] = float64(2025)
+		kwargs["month"] = float64(1)
+		res, err := rt.journalQuery(nil, kwargs)
+		require.NoError(t, err)
+		if legs, ok := res.([]map[string]any); ok {
+			return legs
+		}
+		return nil
+	}
+
+	assert.Len(t, query(map[string]any{"account_id": float64(5020)}), 2)
+	assert.Len(t, query(map[string]any{"account_id": float64(1010)}), 3)
+
+	legs := query(map[string]any{"account_id": float64(5020), "status": "pending-review"})
+	require.Len(t, legs, 1)
+	assert.Equal(t, "2025-01-0003a", legs[0]["entry_id"])
+
+	assert.Empty(t, query(map[string]any{"account_id": float64(2010)}))
+}
+
+func TestRuntime_JournalAddDouble_SignCheck(t *testing.T) {
+	rt, err := NewRuntime(newTestRepo(t), "test", false)
+	require.NoError(t, err)
+
+	refund := map[string]any{
+		"date":           "2025-01-12",
+		"description":    "GitHub refund",
+		"debit_account":  float64(1010),
+		"credit_account": float64(5020),
+		"amount":         "4.00",
+	}
+	result, err := rt.journalAddDouble(nil, refund)
+	require.NoError(t, err, "the entry is booked despite the warning")
+	res := result.(map[string]any)
+	assert.Equal(t, "2025-01-0001", res["entry_id"])
+	warnings := res["warnings"].([]map[string]any)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, true, warnings[0]["soft"])
+	assert.Equal(t, 10, warnings[0]["invariant"])
+	assert.Equal(t, "2025-01-0001b", warnings[0]["entry_id"])
+
+	refund["debit_account"], refund["credit_account"] = float64(5020), float64(1010)
+	result, err = rt.journalAddDouble(nil, refund)
+	require.NoError(t, err)
+	assert.NotContains(t, result.(map[string]any), "warnings")
+}
+
+func TestRuntime_JournalAddDouble_ClosedMonth(t *testing.T) {
+	dir := newTestRepo(t)
+	require.NoError(t, gitops.Init(dir))
+	entry := map[string]any{
+		"date":           "2025-01-31",
+		"description":    "GitHub",
+		"debit_account":  float64(5020),
+		"credit_account": float64(1010),
+		"amount":         "4.00",
+	}
+	rt, err := NewRuntime(dir, "test", false)
+	require.NoError(t, err)
+	_, err = rt.journalAddDouble(nil, entry)
+	require.NoError(t, err)
+	_, err = gitops.CommitAll(dir, "init: repo", "Test Author", "test@example.com")
+	require.NoError(t, err)
+	require.NoError(t, gitops.Tag(dir, "close-2025-01", "close: 2025-01"))
+
+	rt, err = NewRuntime(dir, "test", false)
+	require.NoError(t, err)
+	_, err = rt.journalAddDouble(nil, entry)
+	require.ErrorIs(t, err, journal.ErrMonthClosed, "agents cannot reopen a month")
+	_, err = rt.journalVoid(nil, map[string]any{"entry_id": "2025-01-0001", "reason": "duplicate"})
+	require.ErrorIs(t, err, journal.ErrMonthClosed)
+
+	entry["date"] = "2025-02-01"
+	_, err = rt.journalAddDouble(nil, entry)
+	require.NoError(t, err)
+}
+
+func TestRuntime_AccountsSearchAndChildren(t *testing.T) {
+	dir := newTestRepo(t)
+	accts, err := accounts.Load(dir)
+	require.NoError(t, err)
+	require.NoError(t, accts.Add(model.Account{ID: 5021, Name: "Cloud Hosting", Type: model.AccountTypeExpense, ParentID: 5020}))
+	require.NoError(t, accts.Add(model.Account{ID: 5022, Name: "AWS", Type: model.AccountTypeExpense, ParentID: 5021}))
+	require.NoError(t, accts.Save(dir))
+	rt, err := NewRuntime(dir, "test", false)
+	require.NoError(t, err)
+
+	res, err := rt.accountsSearch([]any{"software"}, nil)
+	require.NoError(t, err)
+	found := res.([]map[string]any)
+	require.Len(t, found, 1)
+	assert.Equal(t, 5020, found[0]["id"])
+	assert.Equal(t, "Software & SaaS", found[0]["name"])
+
+	res, err = rt.accountsSearch(nil, map[string]any{"query": "no such account"})
+	require.NoError(t, err)
+	assert.Empty(t, res)
+	_, err = rt.accountsSearch(nil, nil)
+	require.Error(t, err)
+
+	res, err = rt.accountsChildren([]any{float64(5020)}, nil)
+	require.NoError(t, err)
+	children := res.([]map[string]any)
+	require.Len(t, children, 1)
+	assert.Equal(t, 5021, children[0]["id"])
+
+	res, err = rt.accountsChildren(nil, map[string]any{"parent_id": float64(5020), "recursive": true})
+	require.NoError(t, err)
+	descendants := res.([]map[string]any)
+	require.Len(t, descendants, 2)
+	assert.Equal(t, 5022, descendants[1]["id"])
+
+	_, err = rt.accountsChildren([]any{float64(9999)}, nil)
+	require.EqualError(t, err, "unknown account 9999")
+}
+
+func TestRuntime_JournalAttachReceipt(t *testing.T) {
+	dir := newTestRepo(t)
+	rt, err := NewRuntime(dir, "test", false)
+	require.NoError(t, err)
+
+	_, err = rt.journalAddDouble(nil, map[string]any{
+		"date":           "2025-01-10",
+		"description":    "Staples",
+		"debit_account":  float64(5030),
+		"credit_account": float64(1010),
+		"amount":         "23.10",
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "import", "staples.jpg"), []byte("receipt"), 0o644))
+
+	res, err := rt.journalAttachReceipt(nil, map[string]any{"entry_id": "2025-01-0001", "path": "import/staples.jpg"})
+	require.NoError(t, err)
+	hash, _ := res.(map[string]any)["receipt_hash"].(string)
+	require.Len(t, hash, 64)
+	_, err = os.Stat(filepath.Join(dir, "receipts", hash+".jpg"))
+	require.NoError(t, err, "receipt stored under its hash")
+
+	res, err = rt.journalFindByReceipt([]any{hash}, nil)
+	require.NoError(t, err)
+	legs := res.([]any)
+	require.Len(t, legs, 2)
+	assert.Equal(t, "2025-01-0001a", legs[0].(map[string]any)["entry_id"])
+	assert.Equal(t, hash, legs[0].(map[string]any)["receipt_hash"])
+
+	_, err = rt.journalAttachReceipt(nil, map[string]any{"entry_id": "2025-01-0001"})
+	require.Error(t, err, "needs a path or sha256")
+}
+
+func TestRuntime_JournalAttachReceipt_Rejected(t *testing.T) {
+	dir := newTestRepo(t)
+	rt, err := NewRuntime(dir, "test", false)
+	require.NoError(t, err)
+
+	_, err = rt.journalAddDouble(nil, map[string]any{
+		"date":           "2025-01-10",
+		"description":    "Staples",
+		"debit_account":  float64(5030),
+		"credit_account": float64(1010),
+		"amount":         "23.10",
+	})
+	require.NoError(t, err)
+
+	outside := filepath.Join(t.TempDir(), "id_rsa")
+	require.NoError(t, os.WriteFile(outside, []byte("secret"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cleared.txt"), []byte("repo file"), 0o644))
+	require.NoError(t, os.Symlink(outside, filepath.Join(dir, "import", "link.jpg")))
+
+	for _, path := range []string{
+		outside,
+		"../" + filepath.Base(filepath.Dir(outside)) + "/id_rsa",
+		"import/../../id_rsa",
+		"cleared.txt",
+		"import",
+		"import/link.jpg",
+	} {
+		_, err := rt.journalAttachReceipt(nil, map[string]any{"entry_id": "2025-01-0001", "path": path})
+		assert.ErrorContains(t, err, "invalid receipt path", path)
+	}
+	_, err = os.Stat(filepath.Join(dir, "receipts"))
+	assert.True(t, os.IsNotExist(err), "nothing copied into the repo")
+
+	_, err = rt.journalAttachReceipt(nil, map[string]any{"entry_id": "2025-01-0001", "sha256": strings.Repeat("ab", 32)})
+	require.ErrorIs(t, err, receipts.ErrNotFound, "a hash must name a stored receipt")
+	legs, err := rt.journalFindByReceipt([]any{strings.Repeat("ab", 32)}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, legs)
+}
+
+func TestRuntime_Queue(t *testing.T) {
+	dir := newTestRepo(t)
+	rt, err := NewRuntime(dir, "ingest", false)
+	require.NoError(t, err)
+
+	res, err := rt.queueAddReview(nil, map[string]any{
+		"entry_id":          "2025-01-0003",
+		"reason":            "STAPLES #1234",
+		"suggested_account": 5030,
+		"confidence":        0.4,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "q001", res.(map[string]any)["item_id"])
+
+	// A new runtime, as in a later agent run, still sees the item.
+	rt, err = NewRuntime(dir, "review", false)
+	require.NoError(t, err)
+	res, err = rt.queueList(nil, map[string]any{})
+	require.NoError(t, err)
+	items := res.([]queueListItem)
+	require.Len(t, items, 1)
+	assert.Equal(t, "q001", items[0].ID)
+	data, err := json.Marshal(items[0])
+	require.NoError(t, err)
+	var listed map[string]any
+	require.NoError(t, json.Unmarshal(data, &listed))
+	assert.Equal(t, "q001", listed["item_id"], "the key queue_add_review returns")
+	assert.Equal(t, "q001", listed["id"])
+	assert.Equal(t, "ingest", items[0].Agent)
+	assert.Equal(t, "STAPLES #1234", items[0].Reason)
+	assert.Equal(t, 5030, items[0].SuggestedAccount)
+	assert.InDelta(t, 0.4, items[0].Confidence, 1e-9)
+
+	_, err = rt.queueAddReview(nil, map[string]any{"entry_id": "2025-01-0004", "description": "SQ *COFFEE"})
+	require.NoError(t, err, "description is still accepted as the reason")
+	_, err = rt.queueAddReview(nil, map[string]any{"entry_id": "2025-01-0004"})
+	require.Error(t, err)
+
+	_, err = rt.queueResolve([]any{"q001"}, map[string]any{"resolution": "recategorized to 5030"})
+	require.NoError(t, err)
+	res, err = rt.queueList(nil, map[string]any{})
+	require.NoError(t, err)
+	assert.Len(t, res, 1)
+	res, err = rt.queueList(nil, map[string]any{"include_resolved": true})
+	require.NoError(t, err)
+	assert.Len(t, res, 2)
+
+	_, err = rt.queueResolve(nil, map[string]any{"item_id": "q042", "resolution": "confirmed"})
+	var pe *PrimitiveError
+	require.ErrorAs(t, classifyError(err), &pe)
+	assert.Equal(t, CodeNotFound, pe.Code)
+}
+
+func TestRuntime_ImportManifest(t *testing.T) {
+	dir := newTestRepo(t)
+	for _, name := range []string{"chase.csv", "amex.csv"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "import", name), []byte("x"), 0o644))
+	}
+	rt, err := NewRuntime(dir, "ingest", false)
+	require.NoError(t, err)
+
+	add := func(desc string) {
+		t.Helper()
+		_, err := rt.journalAddDouble(nil, map[string]any{
+			"date":           "2025-01-10",
+			"description":    desc,
+			"debit_account":  float64(5020),
+			"credit_account": float64(1010),
+			"amount":         "10.00",
+		})
+		require.NoError(t, err)
+	}
+	add("GitHub")
+	add("AWS")
+	res, err := rt.importerMarkProcessed([]any{"chase.csv"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.(map[string]any)["entry_count"], "entries booked since the last file")
+	add("Dropbox")
+	_, err = rt.importerMarkProcessed([]any{"amex.csv"}, nil)
+	require.NoError(t, err)
+
+	file, err := rt.importerSourceFile([]any{"2025-01-0002"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "chase.csv", file)
+	file, err = rt.importerSourceFile(nil, map[string]any{"entry_id": "2025-01-0003a"})
+	require.NoError(t, err)
+	assert.Equal(t, "amex.csv", file)
+	file, err = rt.importerSourceFile([]any{"2025-01-0042"}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, file)
+	assert.Contains(t, rt.touched, importer.ManifestFile)
+}
+
+func TestRuntime_ImporterUnprocess(t *testing.T) {
+	dir := newTestRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "import", "chase.csv"), []byte("x"), 0o644))
+	rt, err := NewRuntime(dir, "ingest", false)
+	require.NoError(t, err)
+
+	_, err = rt.importerMarkProcessed([]any{"chase.csv"}, nil)
+	require.NoError(t, err)
+	_, err = rt.importerUnprocess([]any{"chase.csv"}, nil)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "import", "chase.csv"))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "import", "processed"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "import", "processed", "chase.csv"), []byte("y"), 0o644))
+	_, err = rt.importerUnprocess(nil, map[string]any{"filename": "chase.csv"})
+	require.Error(t, err, "won't overwrite a file waiting in import/")
+}
+
+func TestRuntime_GitCommitNothingToCommit(t *testing.T) {
+	dir := newTestRepo(t)
+	require.NoError(t, gitops.Init(dir))
+	rt, err := NewRuntime(dir, "test", false)
+	require.NoError(t, err)
+
+	res, err := rt.gitCommit([]any{"import: nothing yet"}, nil)
+	require.NoError(t, err, "no changes is not a failure")
+	assert.Equal(t, map[string]any{"commit_hash": "", "success": true, "nothing_to_commit": true}, res)
+}
+
+func TestRuntime_GitCommitPrefix(t *testing.T) {
+	dir := newTestRepo(t)
+	require.NoError(t, gitops.Init(dir))
+	rt, err := NewRuntime(dir, "categorize", true)
+	require.NoError(t, err)
+
+	_, err = rt.gitCommit([]any{"Updated 5 transaction categories"}, nil)
+	require.NoError(t, err)
+	logged := rt.AgentLog()[len(rt.AgentLog())-1]
+	assert.Equal(t, "categorize: Updated 5 transaction categories", logged.Details, "prefixed with the agent name")
+
+	_, err = rt.gitCommit([]any{"rules: github"}, nil)
+	require.ErrorIs(t, err, gitops.ErrCommitPrefix)
+}
+
+func TestRuntime_GitCommitOnlyTouched(t *testing.T) {
+	dir := newTestRepo(t)
+	require.NoError(t, gitops.Init(dir))
+	_, err := gitops.CommitAll(dir, "init", "Test Author", "test@example.com")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "import", "chase.csv"), []byte("x"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("stray"), 0o644))
+
+	rt, err := NewRuntime(dir, "ingest", false)
+	require.NoError(t, err)
+	_, err = rt.journalAddDouble(nil, map[string]any{
+		"date":           "2025-01-03",
+		"description":    "GitHub",
+		"debit_account":  float64(5020),
+		"credit_account": float64(1010),
+		"amount":         "4.00",
+	})
+	require.NoError(t, err)
+	_, err = rt.importerMarkProcessed([]any{"chase.csv"}, nil)
+	require.NoError(t, err)
+
+	res, err := rt.gitCommit([]any{"import: 1 transaction"}, nil)
+	require.NoError(t, err)
+	short, full := res.(map[string]any)["commit_hash"].(string), res.(map[string]any)["commit_hash_full"].(string)
+	assert.Len(t, short, 7)
+	assert.Len(t, full, 40)
+	assert.True(t, strings.HasPrefix(full, short))
+
+	logged := rt.AgentLog()[len(rt.AgentLog())-1]
+	assert.Equal(t, "git_commit", logged.Action)
+	assert.Equal(t, short, logged.CommitHash, "agent log keeps the short hash by default")
+
+	changed, err := gitops.ChangedPaths(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"notes.txt"}, changed, "only the journal and processed file were committed")
+
+	res, err = rt.gitCommit([]any{"import: again"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, true, res.(map[string]any)["nothing_to_commit"], "touched paths reset after a commit")
+}
+
+func TestRuntime_GitCommitFullHashes(t *testing.T) {
+	dir := newTestRepo(t)
+	require.NoError(t, gitops.Init(dir))
+	rt, err := NewRuntime(dir, "ingest", false)
+	require.NoError(t, err)
+	rt.cfg.Git.FullHashes = true
+
+	_, err = rt.rulesAdd(nil, map[string]any{"vendor_pattern": "GITHUB*", "account_id": float64(5020)})
+	require.NoError(t, err)
+	res, err := rt.gitCommit([]any{"categorize: github"}, nil)
+	require.NoError(t, err)
+
+	require.Len(t, rt.AgentLog(), 1)
+	assert.Equal(t, res.(map[string]any)["commit_hash_full"], rt.AgentLog()[0].CommitHash)
+	assert.Len(t, rt.AgentLog()[0].CommitHash, 40)
+}
+
+func TestRuntime_CommitHashStamped(t *testing.T) {
+	dir := newTestRepo(t)
+	require.NoError(t, gitops.Init(dir))
+	rt, err := NewRuntime(dir, "ingest", false)
+	require.NoError(t, err)
+	b := newFakeBridge(t)
+	rt.Register(b)
+	rt.SetLogSink(func(agentlog.Entry) {})
+
+	result, err := b.RunScript(`ctx_log("booking")
+journal_add_double(date="2025-01-03", description="GitHub", debit_account=5020, credit_account=1010, amount="4.00")
+first = git_commit("import: 1 transaction")
+ctx_log("categorizing")
+rules_add(vendor_pattern="GITHUB*", account_id=5020)
+second = git_commit("categorize: github")
+ctx_log("done")
+[first["commit_hash"], second["commit_hash"]]`, b.PrimitiveNames())
+	require.NoError(t, err)
+	hashes := result.([]any)
+
+	var got [][2]string
+	for _, e := range rt.AgentLog() {
+		got = append(got, [2]string{e.Action + " " + e.Details, e.CommitHash})
+	}
+	assert.Equal(t, [][2]string{
+		{"log booking", hashes[0].(string)},
+		{"git_commit import: 1 transaction", hashes[0].(string)},
+		{"log categorizing", hashes[1].(string)},
+		{"git_commit categorize: github", hashes[1].(string)},
+		{"log done", ""},
+	}, got, "entries carry the commit that contains their work")
+
+	assert.Positive(t, rt.AgentLog()[1].DurationMS, "git_commit is timed")
+}
+
+func TestRuntime_CtxArgs(t *testing.T) {
+	rt, err := NewRuntime(newTestRepo(t), "test", false)
+	require.NoError(t, err)
+	b := newFakeBridge(t)
+	rt.Register(b)
+
+	result, err := b.RunScript(`len(ctx_args())`, []string{"ctx_args"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, result, "no args is an empty dict")
+
+	rt.SetArgs(map[string]any{"month": "2025-01", "force": true})
+	result, err = b.RunScript(`a = ctx_args()
+a["month"] + " " + str(a["force"])`, []string{"ctx_args"})
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01 True", result)
+}
+
+func TestRuntime_LogSinkStreams(t *testing.T) {
+	rt, err := NewRuntime(newTestRepo(t), "test", false)
+	require.NoError(t, err)
+	b := newFakeBridge(t)
+	rt.Register(b)
+
+	streamed := make(chan agentlog.Entry, 1)
+	rt.SetLogSink(func(e agentlog.Entry) { streamed <- e })
+
+	// The script only finishes once the sink has seen the first entry.
+	b.RegisterPrimitive("await_streamed", func(_ []any, _ map[string]any) (any, error) {
+		select {
+		case e := <-streamed:
+			return e.Details, nil
+		case <-time.After(5 * time.Second):
+			return nil, nil //nolint:nilnil // script sees None on timeout
+		}
+	})
+
+	result, err := b.RunScript(`ctx_log("step one")
+await_streamed()`, []string{"ctx_log", "await_streamed"})
+	require.NoError(t, err)
+	assert.Equal(t, "step one", result, "entry reached the sink before the script finished")
+
+	require.Len(t, rt.AgentLog(), 1, "entries are still collected")
+	assert.Equal(t, "log", rt.AgentLog()[0].Action)
+}
+
+func TestRuntime_BridgeStderrLogged(t *testing.T) {
+	rt, err := NewRuntime(newTestRepo(t), "ingest", false)
+	require.NoError(t, err)
+	b := newFakeBridge(t)
+	rt.Register(b)
+
+	_, err = b.RunScript(`raise ValueError("bad row")`, nil)
+	require.ErrorContains(t, err, "ValueError: bad row")
+
+	// Stderr arrives on its own pipe, so it may trail the error.
+	assert.Eventually(t, func() bool {
+		for _, e := range rt.AgentLog() {
+			if e.Action == "bridge_stderr" && e.Details == "ValueError: bad row" {
+				return e.Agent == "ingest"
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRuntime_StructuredErrors(t *testing.T) {
+	rt, err := NewRuntime(newTestRepo(t), "test", false)
+	require.NoError(t, err)
+	b := newFakeBridge(t)
+	rt.Register(b)
+
+	_, err = b.RunScript(`journal_add_double(date="2025-01-15", description="Bad",
+    debit_account=9999, credit_account=1010, amount=10.0,
+    status="auto-confirmed", confidence=0.9)`, b.PrimitiveNames())
+	var pe *PrimitiveError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, CodeValidationFailed, pe.Code)
+	assert.Contains(t, pe.Message, "unknown account 9999")
+	assert.Equal(t, []any{"2025-01-0001"}, pe.Data["entry_ids"])
+
+	_, err = b.RunScript(`journal_void(entry_id="2025-01-0042", reason="typo")`, b.PrimitiveNames())
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, CodeNotFound, pe.Code, "distinct from validation failures")
+
+	_, err = b.RunScript(`journal_void(entry_id="garbage", reason="typo")`, b.PrimitiveNames())
+	require.Error(t, err)
+	assert.NotErrorAs(t, err, &pe, "untyped errors stay plain")
+}
+
+func TestRuntime_SharedBridge(t *testing.T) {
+	dir := newTestRepo(t)
+	b := newFakeBridge(t)
+	pid := b.cmd.Process.Pid
+
+	for _, agent := range []string{"first", "second"} {
+		rt, err := NewRuntime(dir, agent, false)
+		require.NoError(t, err)
+		rt.Register(b)
+		rt.SetLogSink(func(agentlog.Entry) {})
+
+		result, err := b.RunScript(`ctx_log("hello")
+accounts_exists(1010)`, b.PrimitiveNames())
+		require.NoError(t, err, agent)
+		assert.Equal(t, true, result)
+
+		require.Len(t, rt.AgentLog(), 1, "entries go to the runtime registered last")
+		assert.Equal(t, agent, rt.AgentLog()[0].Agent)
+	}
+	assert.Equal(t, pid, b.cmd.Process.Pid, "bridge was not relaunched")
+}
@@ -0,0 +1,179 @@
+package sandbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cleared-dev/cleared/internal/agentlog"
+)
+
+// JSON-RPC error codes the server returns for malformed requests.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInternalError  = -32603
+)
+
+// maxRequestBytes bounds a request body.
+const maxRequestBytes = 10 << 20
+
+// Server exposes a Runtime's primitives over HTTP as JSON-RPC 2.0, for
+// clients such as a web UI. Each POST body is one request whose method is a
+// primitive name; params are an array of positional arguments or an object
+// of keyword arguments, as a script would pass them. Requests must be sent as
+// application/json and, when they carry an Origin, come from the server's
+// own origin, so a web page open in the user's browser cannot post to it.
+// Calls are serialized,
+// since the Runtime carries state between them, and the agent log entries
+// each call records are appended to logs/agent-log.csv.
+type Server struct {
+	rt       *Runtime
+	handlers map[string]PrimitiveHandler
+
+	mu     sync.Mutex
+	logged int // agent log entries already written
+}
+
+// NewServer creates a Server for rt's primitives.
+func NewServer(rt *Runtime) *Server {
+	return &Server{rt: rt, handlers: rt.Primitives()}
+}
+
+// rpcRequest is an incoming JSON-RPC request.
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      any             `json:"id,omitempty"`
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "JSON-RPC requests must be POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+	if mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mt != "application/json" {
+		http.Error(w, "JSON-RPC requests must be sent as application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin requests are not allowed", http.StatusForbidden)
+		return
+	}
+
+	var req rpcRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestBytes)).Decode(&req); err != nil {
+		writeRPC(w, nil, nil, &RPCError{Code: codeParseError, Message: "parse error: " + err.Error()})
+		return
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		writeRPC(w, req.ID, nil, &RPCError{Code: codeInvalidRequest, Message: `invalid request: want jsonrpc "2.0" and a method`})
+		return
+	}
+	handler, ok := s.handlers[req.Method]
+	if !ok {
+		writeRPC(w, req.ID, nil, &RPCError{Code: codeMethodNotFound, Message: "unknown primitive: " + req.Method})
+		return
+	}
+	args, kwargs, err := decodeParams(req.Params)
+	if err != nil {
+		writeRPC(w, req.ID, nil, &RPCError{Code: codeInvalidRequest, Message: err.Error()})
+		return
+	}
+
+	result, err := s.call(handler, args, kwargs)
+	if err != nil {
+		writeRPC(w, req.ID, nil, toRPCError(err))
+		return
+	}
+	writeRPC(w, req.ID, result, nil)
+}
+
+// sameOrigin reports whether r has no Origin header, as from a non-browser
+// client, or an Origin whose host is the one r was sent to.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// call runs handler with the server locked and flushes the agent log
+// entries it records. A panicking primitive is reported as an internal
+// error, so one bad call does not leave the server locked.
+func (s *Server) call(handler PrimitiveHandler, args []any, kwargs map[string]any) (result any, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.flushLog()
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PrimitiveError{Code: codeInternalError, Message: fmt.Sprintf("internal error: %v", r)}
+		}
+	}()
+	return handler(args, kwargs)
+}
+
+// decodeParams splits params into positional or keyword arguments.
+func decodeParams(params json.RawMessage) ([]any, map[string]any, error) {
+	params = bytes.TrimSpace(params)
+	if len(params) == 0 || bytes.Equal(params, []byte("null")) {
+		return nil, nil, nil
+	}
+	var err error
+	var args []any
+	var kwargs map[string]any
+	switch params[0] {
+	case '[':
+		err = json.Unmarshal(params, &args)
+	case '{':
+		err = json.Unmarshal(params, &kwargs)
+	default:
+		return nil, nil, errors.New("invalid params: want an array or an object")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid params: %w", err)
+	}
+	return args, kwargs, nil
+}
+
+// flushLog appends the agent log entries recorded since the last flush.
+// Must be called with s.mu held.
+func (s *Server) flushLog() {
+	entries := s.rt.AgentLog()
+	if len(entries) == s.logged {
+		return
+	}
+	if err := agentlog.Append(s.rt.repoRoot, entries[s.logged:]); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to write agent log: %v\n", err)
+		return
+	}
+	s.logged = len(entries)
+}
+
+// writeRPC writes a JSON-RPC response carrying either result or rpcErr.
+func writeRPC(w http.ResponseWriter, id, result any, rpcErr *RPCError) {
+	resp := map[string]any{"jsonrpc": "2.0", "id": id}
+	if rpcErr != nil {
+		resp["error"] = rpcErr
+	} else {
+		resp["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
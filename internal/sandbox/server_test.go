@@ -0,0 +1,159 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cleared-dev/cleared/internal/agentlog"
+)
+
+// rpcReply is a decoded JSON-RPC response from the server.
+type rpcReply struct {
+	Result any       `json:"result"`
+	Error  *RPCError `json:"error"`
+	ID     any       `json:"id"`
+}
+
+func postRPC(t *testing.T, url, body string) rpcReply {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var reply rpcReply
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+	return reply
+}
+
+func TestServer_AccountsList(t *testing.T) {
+	rt, err := NewRuntime(newTestRepo(t), "serve", false)
+	require.NoError(t, err)
+	srv := httptest.NewServer(NewServer(rt))
+	defer srv.Close()
+
+	reply := postRPC(t, srv.URL, `{"jsonrpc": "2.0", "method": "accounts_list", "id": 1}`)
+	require.Nil(t, reply.Error)
+	assert.InDelta(t, 1, reply.ID, 0)
+	accts := reply.Result.([]any)
+	require.NotEmpty(t, accts)
+	assert.Equal(t, "Business Checking", accts[0].(map[string]any)["name"])
+
+	reply = postRPC(t, srv.URL, `{"jsonrpc": "2.0", "method": "accounts_get", "params": [5020], "id": 2}`)
+	require.Nil(t, reply.Error)
+	assert.Equal(t, "Software & SaaS", reply.Result.(map[string]any)["name"], "positional params")
+}
+
+func TestServer_JournalQuery(t *testing.T) {
+	dir := newTestRepo(t)
+	rt, err := NewRuntime(dir, "serve", false)
+	require.NoError(t, err)
+	srv := httptest.NewServer(NewServer(rt))
+	defer srv.Close()
+
+	reply := postRPC(t, srv.URL, `{"jsonrpc": "2.0", "method": "journal_add_double", "id": 1, "params": {
+		"date": "2025-01-03", "description": "GitHub", "debit_account": 5020,
+		"credit_account": 1010, "amount": "4.00", "status": "auto-confirmed"}}`)
+	require.Nil(t, reply.Error)
+	assert.Equal(t, "2025-01-0001", reply.Result.(map[string]any)["entry_id"])
+
+	reply = postRPC(t, srv.URL, `{"jsonrpc": "2.0", "method": "journal_query", "params": {"year": 2025, "month": 1}, "id": 2}`)
+	require.Nil(t, reply.Error)
+	legs := reply.Result.([]any)
+	require.Len(t, legs, 2)
+	assert.Equal(t, "2025-01-0001a", legs[0].(map[string]any)["entry_id"])
+
+	reply = postRPC(t, srv.URL, `{"jsonrpc": "2.0", "method": "ctx_log", "params": ["booked from the UI"], "id": 3}`)
+	require.Nil(t, reply.Error)
+	entries, err := agentlog.Read(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "logged actions are written as they happen")
+	assert.Equal(t, "serve", entries[0].Agent)
+	assert.Equal(t, "booked from the UI", entries[0].Details)
+}
+
+func TestServer_Errors(t *testing.T) {
+	rt, err := NewRuntime(newTestRepo(t), "serve", true)
+	require.NoError(t, err)
+	srv := httptest.NewServer(NewServer(rt))
+	defer srv.Close()
+
+	reply := postRPC(t, srv.URL, `{"jsonrpc": "2.0", "method": "journal_add_double", "id": 1, "params": {
+		"date": "2025-01-03", "description": "Bad", "debit_account": 9999,
+		"credit_account": 1010, "amount": "4.00"}}`)
+	require.NotNil(t, reply.Error)
+	assert.Equal(t, CodeValidationFailed, reply.Error.Code, "writes are validated as for agents")
+
+	reply = postRPC(t, srv.URL, `{"jsonrpc": "2.0", "method": "no_such_primitive", "id": 2}`)
+	require.NotNil(t, reply.Error)
+	assert.Equal(t, codeMethodNotFound, reply.Error.Code)
+
+	reply = postRPC(t, srv.URL, `{"jsonrpc": "2.0", "method": "accounts_get", "params": "5020", "id": 3}`)
+	require.NotNil(t, reply.Error)
+	assert.Equal(t, codeInvalidRequest, reply.Error.Code)
+
+	reply = postRPC(t, srv.URL, `not json`)
+	require.NotNil(t, reply.Error)
+	assert.Equal(t, codeParseError, reply.Error.Code)
+
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestServer_RejectsCrossSite(t *testing.T) {
+	rt, err := NewRuntime(newTestRepo(t), "serve", true)
+	require.NoError(t, err)
+	srv := httptest.NewServer(NewServer(rt))
+	defer srv.Close()
+
+	post := func(contentType, origin string) int {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, srv.URL,
+			strings.NewReader(`{"jsonrpc": "2.0", "method": "accounts_list", "id": 1}`))
+		require.NoError(t, err)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, post("text/plain", ""), "a form or fetch without preflight")
+	assert.Equal(t, http.StatusUnsupportedMediaType, post("", ""))
+	assert.Equal(t, http.StatusForbidden, post("application/json", "https://evil.example"))
+	assert.Equal(t, http.StatusForbidden, post("application/json", "null"))
+
+	assert.Equal(t, http.StatusOK, post("application/json; charset=utf-8", ""))
+	assert.Equal(t, http.StatusOK, post("application/json", srv.URL), "the server's own origin")
+}
+
+func TestServer_PanicDoesNotWedge(t *testing.T) {
+	rt, err := NewRuntime(newTestRepo(t), "serve", true)
+	require.NoError(t, err)
+	s := NewServer(rt)
+	s.handlers["boom"] = func([]any, map[string]any) (any, error) { panic("nil map") }
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	reply := postRPC(t, srv.URL, `{"jsonrpc": "2.0", "method": "boom", "id": 1}`)
+	require.NotNil(t, reply.Error)
+	assert.Equal(t, codeInternalError, reply.Error.Code)
+	assert.Contains(t, reply.Error.Message, "nil map")
+
+	reply = postRPC(t, srv.URL, `{"jsonrpc": "2.0", "method": "accounts_get", "params": [5020], "id": 2}`)
+	require.Nil(t, reply.Error, "the server still answers after a panic")
+	assert.Equal(t, "Software & SaaS", reply.Result.(map[string]any)["name"])
+}
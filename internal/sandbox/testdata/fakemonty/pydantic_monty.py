@@ -0,0 +1,75 @@
+"""
+Minimal stand-in for pydantic_monty used by the Go bridge tests.
+
+It runs scripts with CPython instead of the Monty sandbox, but exposes the
+same start()/resume() snapshot protocol: each call to an external function
+pauses the script and surfaces a MontySnapshot until the host resumes it.
+Only what bridge.py uses is implemented.
+"""
+
+import ast
+import queue
+import threading
+
+
+class MontyComplete:
+    def __init__(self, output):
+        self.output = output
+
+
+class MontySnapshot:
+    def __init__(self, run, function_name, args, kwargs):
+        self._run = run
+        self.function_name = function_name
+        self.args = args
+        self.kwargs = kwargs
+
+    def resume(self, return_value=None):
+        self._run.to_script.put(return_value)
+        return self._run.next_progress()
+
+
+class _Run:
+    def __init__(self, code, external_functions):
+        self.code = code
+        self.external_functions = external_functions
+        self.to_script = queue.Queue()
+        self.to_host = queue.Queue()
+
+    def next_progress(self):
+        kind, payload = self.to_host.get()
+        if kind == "call":
+            name, args, kwargs = payload
+            return MontySnapshot(self, name, args, kwargs)
+        if kind == "error":
+            raise payload
+        return MontyComplete(payload)
+
+    def external(self, name):
+        def call(*args, **kwargs):
+            self.to_host.put(("call", (name, args, kwargs)))
+            return self.to_script.get()
+
+        return call
+
+    def execute(self):
+        try:
+            env = {name: self.external(name) for name in self.external_functions}
+            tree = ast.parse(self.code)
+            last = None
+            if tree.body and isinstance(tree.body[-1], ast.Expr):
+                last = ast.Expression(tree.body.pop().value)
+            exec(compile(tree, "<script>", "exec"), env)
+            output = eval(compile(last, "<script>", "eval"), env) if last else None
+            self.to_host.put(("done", output))
+        except Exception as e:  # surfaced to the host like a Monty error
+            self.to_host.put(("error", e))
+
+
+class Monty:
+    def __init__(self, code, external_functions=()):
+        self._run = _Run(code, list(external_functions or ()))
+
+    def start(self):
+        threading.Thread(target=self._run.execute, daemon=True).start()
+        return self._run.next_progress()